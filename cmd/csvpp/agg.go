@@ -0,0 +1,140 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/osamingo/go-csvpp"
+	"github.com/osamingo/go-csvpp/cmd/csvpp/internal/aggregate"
+	"github.com/osamingo/go-csvpp/cmd/csvpp/internal/fileutil"
+)
+
+var aggCmd = &cobra.Command{
+	Use:   "agg [file]",
+	Short: "Group CSV++ records and compute aggregates",
+	Long: `Group CSV++ records by one or more field paths and emit one record per group.
+Reads from file or stdin if no file is specified.
+
+Output columns are the group-by values, then count, sum_*, avg_*, min_* and max_*,
+then collected values. Collected simple values and arrays become an array field
+(tags[]); collected structured values become an array-structured field
+(address[](street^city)). Without --group-by, all records form a single group.
+
+Examples:
+  csvpp agg --group-by country --sum amount --count orders.csvpp
+  csvpp agg --group-by country --collect name --collect address orders.csvpp
+  csvpp agg --group-by country,city --avg geo.lat --to json places.csvpp`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runAgg,
+}
+
+func init() {
+	aggCmd.Flags().StringSlice("group-by", nil, "field paths to group by (repeatable or comma-separated)")
+	aggCmd.Flags().Bool("count", false, "emit the number of records in each group")
+	aggCmd.Flags().StringSlice("sum", nil, "field paths to sum")
+	aggCmd.Flags().StringSlice("avg", nil, "field paths to average")
+	aggCmd.Flags().StringSlice("min", nil, "field paths to take the minimum of")
+	aggCmd.Flags().StringSlice("max", nil, "field paths to take the maximum of")
+	aggCmd.Flags().StringSlice("collect", nil, "field paths whose values are collected into an array")
+	aggCmd.Flags().StringP("output", "o", "", "output file (writes to stdout if not specified)")
+	aggCmd.Flags().String("to", "", "output format (csvpp, json, ndjson, yaml, csv, toml, xml, msgpack, parquet) - defaults to the output file extension or csvpp")
+
+	rootCmd.AddCommand(aggCmd)
+}
+
+func runAgg(cmd *cobra.Command, args []string) (retErr error) {
+	var spec aggregate.Spec
+	var err error
+	flags := cmd.Flags()
+	if spec.GroupBy, err = flags.GetStringSlice("group-by"); err != nil {
+		return err
+	}
+	if spec.Count, err = flags.GetBool("count"); err != nil {
+		return err
+	}
+	if spec.Sum, err = flags.GetStringSlice("sum"); err != nil {
+		return err
+	}
+	if spec.Avg, err = flags.GetStringSlice("avg"); err != nil {
+		return err
+	}
+	if spec.Min, err = flags.GetStringSlice("min"); err != nil {
+		return err
+	}
+	if spec.Max, err = flags.GetStringSlice("max"); err != nil {
+		return err
+	}
+	if spec.Collect, err = flags.GetStringSlice("collect"); err != nil {
+		return err
+	}
+	outputFile, err := flags.GetString("output")
+	if err != nil {
+		return err
+	}
+	toFormat, err := flags.GetString("to")
+	if err != nil {
+		return err
+	}
+
+	r, err := fileutil.OpenInputFromArgs(args)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := r.Close(); cerr != nil && retErr == nil {
+			retErr = fmt.Errorf("failed to close input: %w", cerr)
+		}
+	}()
+
+	reader := csvpp.NewReader(r)
+	headers, err := reader.Headers()
+	if err != nil {
+		return fmt.Errorf("failed to read headers: %w", err)
+	}
+
+	agg, err := aggregate.New(headers, spec)
+	if err != nil {
+		return err
+	}
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read record: %w", err)
+		}
+		if err := agg.Add(record); err != nil {
+			return err
+		}
+	}
+
+	w, err := fileutil.OpenOutput(outputFile, cmd.OutOrStdout())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := w.Close(); cerr != nil && retErr == nil {
+			retErr = fmt.Errorf("failed to close output: %w", cerr)
+		}
+	}()
+
+	outHeaders, err := agg.Headers()
+	if err != nil {
+		return err
+	}
+	out, err := newRecordWriter(w, outputFormat(toFormat, outputFile), outHeaders)
+	if err != nil {
+		return err
+	}
+	for _, record := range agg.Results() {
+		if err := out.Write(record); err != nil {
+			return fmt.Errorf("failed to write record: %w", err)
+		}
+	}
+	return out.Close()
+}
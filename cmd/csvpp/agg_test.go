@@ -0,0 +1,70 @@
+package main_test
+
+import (
+	"testing"
+)
+
+func TestAggCommand(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantErr    bool
+		wantOutput string
+	}{
+		{
+			name: "success: group by with sum, count and collect",
+			args: []string{"agg", "--group-by", "country", "--sum", "amount", "--count", "--collect", "tags", "testdata/agg/orders.csvpp"},
+			wantOutput: "country,count,sum_amount,tags[]\n" +
+				"JP,2,150.5,new~gift~gift\n" +
+				"US,2,250,vip\n",
+		},
+		{
+			name: "success: collect structured field",
+			args: []string{"agg", "--group-by", "country", "--collect", "customer", "testdata/agg/orders.csvpp"},
+			wantOutput: "country,customer[](name^city)\n" +
+				"JP,Alice^Tokyo~Carol^Osaka\n" +
+				"US,Bob^Boston~Dave^Denver\n",
+		},
+		{
+			name:       "success: json output",
+			args:       []string{"agg", "--group-by", "country", "--collect", "customer.name", "--to", "json", "testdata/agg/orders.csvpp"},
+			wantOutput: `[{"country":"JP","customer_name":["Alice","Carol"]},{"country":"US","customer_name":["Bob","Dave"]}]`,
+		},
+		{
+			name:    "error: nothing to aggregate",
+			args:    []string{"agg", "testdata/agg/orders.csvpp"},
+			wantErr: true,
+		},
+		{
+			name:    "error: unknown field",
+			args:    []string{"agg", "--group-by", "region", "testdata/agg/orders.csvpp"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			stdout, _, err := runCommand(t, tt.args...)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error but got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+
+			if stdout != tt.wantOutput && stdout != tt.wantOutput+"\n" {
+				t.Errorf("output = %q, want %q", stdout, tt.wantOutput)
+			}
+		})
+	}
+}
@@ -1,8 +1,11 @@
 package main
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
+	"os"
 	"path/filepath"
 	"strings"
 
@@ -12,21 +15,54 @@ import (
 	"github.com/osamingo/go-csvpp/cmd/csvpp/internal/converter"
 	"github.com/osamingo/go-csvpp/cmd/csvpp/internal/fileutil"
 	"github.com/osamingo/go-csvpp/csvpputil"
+	"github.com/osamingo/go-csvpp/csvpputil/parquet"
 )
 
 // Format represents output format.
 type Format string
 
 const (
-	FormatJSON  Format = "json"
-	FormatYAML  Format = "yaml"
-	FormatCSVPP Format = "csvpp"
+	FormatJSON    Format = "json"
+	FormatYAML    Format = "yaml"
+	FormatCSVPP   Format = "csvpp"
+	FormatCSV     Format = "csv"
+	FormatNDJSON  Format = "ndjson"
+	FormatTOML    Format = "toml"
+	FormatXML     Format = "xml"
+	FormatMsgpack Format = "msgpack"
+	FormatParquet Format = "parquet"
 )
 
 var convertCmd = &cobra.Command{
 	Use:   "convert",
-	Short: "Convert between CSV++ and JSON/YAML",
-	Long: `Convert CSV++ files to JSON/YAML or vice versa.
+	Short: "Convert between CSV++ and JSON/NDJSON/YAML/CSV/TOML/XML/MessagePack/Parquet",
+	Long: `Convert CSV++ files to JSON/NDJSON/YAML/plain CSV/TOML/XML/MessagePack/Parquet or vice versa.
+
+JSON arrays and NDJSON (JSON Lines, .ndjson/.jsonl) are converted to CSV++
+record by record without buffering the whole input. Headers are inferred from
+the first --sample-size records, or taken from --schema.
+
+Inferred headers merge the keys of all (sampled) records, array elements and
+nested objects, and use delimiters that do not occur in the data. Values that
+cannot be converted without loss, such as a string where other records have
+an object, are reported on stderr as warnings.
+
+Files with the .csv extension are treated as CSV++. Use --from csv or --to csv
+for plain CSV, where structured fields are flattened into dotted columns
+(geo.lat) and arrays into joined (tags[]) or indexed (tags[0]) columns.
+
+TOML is written as an array of tables ([[records]]), XML as one <record>
+element per record with nested component elements and <item> array elements,
+and MessagePack as a stream of maps.
+
+Parquet uses native nested types: array fields become repeated (list) columns,
+structured fields groups, and array-structured fields repeated groups.
+
+JSON, NDJSON and YAML values are written as strings unless --typed or --type is
+given. --typed writes numbers, true/false and empty values as JSON/YAML numbers,
+booleans and nulls; --type path=kind sets the type (string, auto, number,
+boolean) of one field, such as age=number or geo.lat=number. Values are only
+typed when converting back yields the same text, so typed output round-trips.
 
 Examples:
   # Convert CSVPP to JSON
@@ -36,9 +72,31 @@ Examples:
   # Convert CSVPP to YAML
   csvpp convert -i input.csvpp -o output.yaml
 
+  # Convert CSVPP to JSON with numbers, booleans and nulls
+  csvpp convert -i input.csvpp --to json --typed
+  csvpp convert -i input.csvpp --to json --type age=number --type active=boolean
+
   # Convert JSON to CSVPP
   csvpp convert -i input.json -o output.csvpp
 
+  # Stream CSVPP to and from NDJSON
+  csvpp convert -i input.csvpp -o output.ndjson
+  csvpp convert -i input.jsonl --to csvpp
+
+  # Flatten CSVPP to plain CSV and nest it back
+  csvpp convert -i input.csvpp --to csv --csv-arrays indexed
+  csvpp convert -i input.csv --from csv --to csvpp
+  csvpp convert -i input.csv --from csv --to csvpp --schema 'name,geo(lat^lon),tags[]'
+
+  # Convert CSVPP to TOML, XML or MessagePack and back
+  csvpp convert -i input.csvpp -o output.toml
+  csvpp convert -i input.csvpp -o output.xml
+  csvpp convert -i input.msgpack -o output.csvpp
+
+  # Convert CSVPP to Parquet and back
+  csvpp convert -i input.csvpp -o output.parquet
+  csvpp convert -i input.parquet -o output.csvpp
+
   # Using stdin/stdout
   cat input.csvpp | csvpp convert --to json
   cat input.json | csvpp convert --from json --to csvpp`,
@@ -48,8 +106,13 @@ Examples:
 func init() {
 	convertCmd.Flags().StringP("input", "i", "", "input file (reads from stdin if not specified)")
 	convertCmd.Flags().StringP("output", "o", "", "output file (writes to stdout if not specified)")
-	convertCmd.Flags().String("from", "", "input format when using stdin (json, yaml, csvpp)")
-	convertCmd.Flags().String("to", "", "output format (json, yaml, csvpp)")
+	convertCmd.Flags().String("from", "", "input format when using stdin (json, ndjson, yaml, csvpp, csv, toml, xml, msgpack, parquet)")
+	convertCmd.Flags().String("to", "", "output format (json, ndjson, yaml, csvpp, csv, toml, xml, msgpack, parquet)")
+	convertCmd.Flags().String("schema", "", "CSV++ header line, or a file starting with one, to use as headers for csv, json and ndjson input")
+	convertCmd.Flags().Int("sample-size", converter.DefaultSampleSize, "number of json/ndjson records used to infer headers (0 for all)")
+	convertCmd.Flags().String("csv-arrays", "joined", "how to flatten arrays into plain CSV (joined, indexed)")
+	convertCmd.Flags().Bool("typed", false, "write numbers, booleans and nulls in json, ndjson and yaml output")
+	convertCmd.Flags().StringArray("type", nil, "type of a field in json, ndjson and yaml output, as path=kind (string, auto, number, boolean; repeatable)")
 
 	rootCmd.AddCommand(convertCmd)
 }
@@ -72,6 +135,41 @@ func runConvert(cmd *cobra.Command, _ []string) (retErr error) { //nolint:unused
 	if err != nil {
 		return err
 	}
+	schemaSpec, err := cmd.Flags().GetString("schema")
+	if err != nil {
+		return err
+	}
+	sampleSize, err := cmd.Flags().GetInt("sample-size")
+	if err != nil {
+		return err
+	}
+	csvArrays, err := cmd.Flags().GetString("csv-arrays")
+	if err != nil {
+		return err
+	}
+	typed, err := cmd.Flags().GetBool("typed")
+	if err != nil {
+		return err
+	}
+	typeSpecs, err := cmd.Flags().GetStringArray("type")
+	if err != nil {
+		return err
+	}
+
+	valueTypes, err := parseValueTypes(typed, typeSpecs)
+	if err != nil {
+		return err
+	}
+
+	var arrayMode csvpputil.CSVArrayMode
+	switch strings.ToLower(csvArrays) {
+	case "joined":
+		arrayMode = csvpputil.CSVArrayJoin
+	case "indexed":
+		arrayMode = csvpputil.CSVArrayIndex
+	default:
+		return fmt.Errorf("invalid --csv-arrays %q (must be joined or indexed)", csvArrays)
+	}
 
 	// Determine input format
 	var inputFormat Format
@@ -95,11 +193,26 @@ func runConvert(cmd *cobra.Command, _ []string) (retErr error) { //nolint:unused
 	// Infer input format from output format for stdin
 	if inputFormat == "" && inputFile == "" {
 		if outFormat == FormatCSVPP {
-			return fmt.Errorf("--from flag is required when reading from stdin and converting to csvpp (specify json or yaml)")
+			return fmt.Errorf("--from flag is required when reading from stdin and converting to csvpp (specify json, ndjson, yaml, csv, toml, xml, msgpack or parquet)")
 		}
 		inputFormat = FormatCSVPP
 	}
 
+	if valueTypes != nil && outFormat != FormatJSON && outFormat != FormatNDJSON && outFormat != FormatYAML {
+		return fmt.Errorf("--typed and --type are only supported with json, ndjson and yaml output")
+	}
+
+	var schema []*csvpp.ColumnHeader
+	if schemaSpec != "" {
+		if inputFormat != FormatCSV && inputFormat != FormatJSON && inputFormat != FormatNDJSON {
+			return fmt.Errorf("--schema is only supported with csv, json and ndjson input")
+		}
+		schema, err = loadSchema(schemaSpec)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Open input
 	r, err := fileutil.OpenInput(inputFile)
 	if err != nil {
@@ -122,12 +235,66 @@ func runConvert(cmd *cobra.Command, _ []string) (retErr error) { //nolint:unused
 		}
 	}()
 
+	// Report values that cannot be converted without loss
+	stderr := cmd.ErrOrStderr()
+	warnings := converter.WithWarnings(func(w converter.Warning) {
+		fmt.Fprintf(stderr, "warning: %s\n", w)
+	})
+
 	// Route to appropriate converter
 	switch {
 	case inputFormat == FormatCSVPP && (outFormat == FormatJSON || outFormat == FormatYAML):
-		return convertFromCSVPP(r, w, outFormat)
-	case (inputFormat == FormatJSON || inputFormat == FormatYAML) && outFormat == FormatCSVPP:
-		return convertToCSVPP(r, w, inputFormat)
+		return convertFromCSVPP(r, w, outFormat, valueTypes)
+	case inputFormat == FormatCSV && (outFormat == FormatJSON || outFormat == FormatYAML):
+		return convertFromCSV(r, w, outFormat, schema, valueTypes)
+	case inputFormat == FormatCSVPP && outFormat == FormatCSV:
+		return streamFromCSVPP(r, func(headers []*csvpp.ColumnHeader) recordWriter {
+			return csvpputil.NewCSVWriter(w, headers, csvpputil.WithCSVArrayMode(arrayMode))
+		})
+	case inputFormat == FormatCSVPP && outFormat == FormatNDJSON:
+		return streamFromCSVPP(r, func(headers []*csvpp.ColumnHeader) recordWriter {
+			var opts []csvpputil.NDJSONWriterOption
+			if valueTypes != nil {
+				opts = append(opts, csvpputil.WithNDJSONValueTypes(*valueTypes))
+			}
+			return csvpputil.NewNDJSONWriter(w, headers, opts...)
+		})
+	case inputFormat == FormatCSVPP && outFormat == FormatTOML:
+		return streamFromCSVPP(r, func(headers []*csvpp.ColumnHeader) recordWriter {
+			return csvpputil.NewTOMLWriter(w, headers)
+		})
+	case inputFormat == FormatCSVPP && outFormat == FormatXML:
+		return streamFromCSVPP(r, func(headers []*csvpp.ColumnHeader) recordWriter {
+			return csvpputil.NewXMLWriter(w, headers)
+		})
+	case inputFormat == FormatCSVPP && outFormat == FormatMsgpack:
+		return streamFromCSVPP(r, func(headers []*csvpp.ColumnHeader) recordWriter {
+			return csvpputil.NewMsgpackWriter(w, headers)
+		})
+	case inputFormat == FormatCSVPP && outFormat == FormatParquet:
+		return streamFromCSVPP(r, func(headers []*csvpp.ColumnHeader) recordWriter {
+			return parquet.NewWriter(w, headers)
+		})
+	case inputFormat == FormatParquet && outFormat == FormatCSVPP:
+		src, err := parquetSource(r)
+		if err != nil {
+			return err
+		}
+		pr := parquet.NewReader(src)
+		defer pr.Close()
+		return streamToCSVPP(pr, w, inputFormat)
+	case (inputFormat == FormatJSON || inputFormat == FormatNDJSON) && outFormat == FormatCSVPP:
+		opts := []converter.DecoderOption{converter.WithSampleSize(sampleSize), warnings}
+		if schema != nil {
+			opts = append(opts, converter.WithHeaders(schema))
+		}
+		if inputFormat == FormatJSON {
+			return streamToCSVPP(converter.NewJSONDecoder(r, opts...), w, inputFormat)
+		}
+		return streamToCSVPP(converter.NewNDJSONDecoder(r, opts...), w, inputFormat)
+	case (inputFormat == FormatYAML || inputFormat == FormatCSV || inputFormat == FormatTOML ||
+		inputFormat == FormatXML || inputFormat == FormatMsgpack) && outFormat == FormatCSVPP:
+		return convertToCSVPP(r, w, inputFormat, schema, warnings)
 	case inputFormat == outFormat:
 		return fmt.Errorf("input and output formats are the same: %s", inputFormat)
 	default:
@@ -144,17 +311,75 @@ func detectFormat(filename string) Format {
 	switch ext {
 	case ".json":
 		return FormatJSON
+	case ".ndjson", ".jsonl":
+		return FormatNDJSON
 	case ".yaml", ".yml":
 		return FormatYAML
 	case ".csvpp", ".csv":
 		return FormatCSVPP
+	case ".toml":
+		return FormatTOML
+	case ".xml":
+		return FormatXML
+	case ".msgpack", ".mpk":
+		return FormatMsgpack
+	case ".parquet":
+		return FormatParquet
 	default:
 		return ""
 	}
 }
 
+// parseValueTypes builds the value types for the --typed and --type flags.
+// It returns nil if neither flag is given.
+func parseValueTypes(typed bool, specs []string) (*csvpputil.ValueTypes, error) {
+	if !typed && len(specs) == 0 {
+		return nil, nil
+	}
+
+	vt := &csvpputil.ValueTypes{}
+	if typed {
+		vt.Default = csvpputil.TypeAuto
+	}
+	for _, spec := range specs {
+		path, kind, ok := strings.Cut(spec, "=")
+		if !ok || path == "" {
+			return nil, fmt.Errorf("invalid --type %q (must be path=kind)", spec)
+		}
+		t, err := csvpputil.ParseValueType(kind)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --type %q: %w", spec, err)
+		}
+		if vt.Paths == nil {
+			vt.Paths = make(map[string]csvpputil.ValueType)
+		}
+		vt.Paths[path] = t
+	}
+	return vt, nil
+}
+
+// writeTyped writes records as JSON or YAML, typing values by vt if non-nil.
+func writeTyped(w io.Writer, outFormat Format, headers []*csvpp.ColumnHeader, records [][]*csvpp.Field, vt *csvpputil.ValueTypes) error {
+	switch outFormat {
+	case FormatJSON:
+		var opts []csvpputil.JSONArrayWriterOption
+		if vt != nil {
+			opts = append(opts, csvpputil.WithJSONValueTypes(*vt))
+		}
+		return csvpputil.WriteJSON(w, headers, records, opts...)
+	case FormatYAML:
+		var opts []csvpputil.YAMLArrayWriterOption
+		if vt != nil {
+			opts = append(opts, csvpputil.WithYAMLValueTypes(*vt))
+		}
+		return csvpputil.WriteYAML(w, headers, records, opts...)
+	default:
+		return fmt.Errorf("unsupported output format: %s", outFormat)
+	}
+}
+
 // convertFromCSVPP converts CSVPP to JSON or YAML.
-func convertFromCSVPP(r io.Reader, w io.Writer, outFormat Format) error {
+func convertFromCSVPP(r io.Reader, w io.Writer, outFormat Format, vt *csvpputil.ValueTypes) error {
 	reader := csvpp.NewReader(r)
 
 	headers, err := reader.Headers()
@@ -167,27 +392,128 @@ func convertFromCSVPP(r io.Reader, w io.Writer, outFormat Format) error {
 		return fmt.Errorf("failed to read records: %w", err)
 	}
 
-	switch outFormat {
-	case FormatJSON:
-		return csvpputil.WriteJSON(w, headers, records)
-	case FormatYAML:
-		return csvpputil.WriteYAML(w, headers, records)
-	default:
-		return fmt.Errorf("unsupported output format: %s", outFormat)
+	return writeTyped(w, outFormat, headers, records, vt)
+}
+
+// streamFromCSVPP reads CSVPP records one at a time and writes them with the
+// recordWriter returned by newWriter.
+func streamFromCSVPP(r io.Reader, newWriter func([]*csvpp.ColumnHeader) recordWriter) error {
+	reader := csvpp.NewReader(r)
+
+	headers, err := reader.Headers()
+	if err != nil {
+		return fmt.Errorf("failed to read headers: %w", err)
+	}
+
+	writer := newWriter(headers)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read record: %w", err)
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
 	}
+	return writer.Close()
 }
 
-// convertToCSVPP converts JSON or YAML to CSVPP.
-func convertToCSVPP(r io.Reader, w io.Writer, inputFormat Format) error {
+// recordReader reads headers and then records one at a time, like csvpp.Reader.
+type recordReader interface {
+	Headers() ([]*csvpp.ColumnHeader, error)
+	Read() ([]*csvpp.Field, error)
+}
+
+// streamToCSVPP converts records from a streaming decoder to CSVPP one at a time.
+func streamToCSVPP(dec recordReader, w io.Writer, inputFormat Format) error {
+	headers, err := dec.Headers()
+	if errors.Is(err, io.EOF) {
+		return fmt.Errorf("no data found in input")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", inputFormat, err)
+	}
+
+	writer := csvpp.NewWriter(w)
+	writer.SetHeaders(headers)
+	if err := writer.WriteHeader(); err != nil {
+		return err
+	}
+	for {
+		record, err := dec.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %w", inputFormat, err)
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// parquetSource returns r if it supports random access, as files do, and
+// otherwise reads it into memory, as Parquet footers are at the end of the input.
+func parquetSource(r io.Reader) (parquet.Source, error) {
+	if src, ok := r.(parquet.Source); ok {
+		if _, err := src.Seek(0, io.SeekCurrent); err == nil {
+			return src, nil
+		}
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read input: %w", err)
+	}
+	return bytes.NewReader(data), nil
+}
+
+// convertFromCSV nests plain CSV and writes it as JSON or YAML.
+func convertFromCSV(r io.Reader, w io.Writer, outFormat Format, schema []*csvpp.ColumnHeader, vt *csvpputil.ValueTypes) error {
+	headers, records, err := converter.FromCSV(r, schema)
+	if err != nil {
+		return fmt.Errorf("failed to parse csv: %w", err)
+	}
+
+	return writeTyped(w, outFormat, headers, records, vt)
+}
+
+// loadSchema parses a CSVPP header line given inline or as the first line of a file.
+func loadSchema(spec string) ([]*csvpp.ColumnHeader, error) {
+	src := spec
+	if data, err := os.ReadFile(spec); err == nil {
+		src = string(data)
+	}
+	headers, err := csvpp.NewReader(strings.NewReader(src)).Headers()
+	if err != nil {
+		return nil, fmt.Errorf("invalid --schema: %w", err)
+	}
+	return headers, nil
+}
+
+// convertToCSVPP converts YAML, plain CSV, TOML, XML or MessagePack to CSVPP.
+// schema, if non-nil, describes how plain CSV columns are nested.
+func convertToCSVPP(r io.Reader, w io.Writer, inputFormat Format, schema []*csvpp.ColumnHeader, opts ...converter.DecoderOption) error {
 	var headers []*csvpp.ColumnHeader
 	var records [][]*csvpp.Field
 	var err error
 
 	switch inputFormat {
-	case FormatJSON:
-		headers, records, err = converter.FromJSON(r)
 	case FormatYAML:
-		headers, records, err = converter.FromYAML(r)
+		headers, records, err = converter.FromYAML(r, opts...)
+	case FormatCSV:
+		headers, records, err = converter.FromCSV(r, schema)
+	case FormatTOML:
+		headers, records, err = converter.FromTOML(r, opts...)
+	case FormatXML:
+		headers, records, err = converter.FromXML(r, opts...)
+	case FormatMsgpack:
+		headers, records, err = converter.FromMsgpack(r, opts...)
 	default:
 		return fmt.Errorf("unsupported input format: %s", inputFormat)
 	}
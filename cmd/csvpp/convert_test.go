@@ -34,6 +34,57 @@ func TestConvertCommand(t *testing.T) {
   age: "25"
 `,
 		},
+		{
+			name:       "success: csvpp to ndjson",
+			args:       []string{"convert", "-i", "testdata/convert/simple.csvpp", "--to", "ndjson"},
+			wantOutput: "{\"name\":\"Alice\",\"age\":\"30\"}\n{\"name\":\"Bob\",\"age\":\"25\"}\n",
+		},
+		{
+			name:       "success: ndjson to csvpp",
+			args:       []string{"convert", "-i", "testdata/convert/simple.ndjson", "--to", "csvpp"},
+			wantOutput: "name,age\nAlice,30\nBob,25\n",
+		},
+		{
+			name:       "success: json to csvpp with schema",
+			args:       []string{"convert", "-i", "testdata/convert/simple.json", "--to", "csvpp", "--schema", "age,name"},
+			wantOutput: "age,name\n30,Alice\n25,Bob\n",
+		},
+		{
+			name:       "success: json to csvpp with sample size",
+			args:       []string{"convert", "-i", "testdata/convert/simple.json", "--to", "csvpp", "--sample-size", "1"},
+			wantOutput: "name,age\nAlice,30\nBob,25\n",
+		},
+		{
+			name:       "success: csvpp to toml",
+			args:       []string{"convert", "-i", "testdata/convert/simple.csvpp", "--to", "toml"},
+			wantOutput: "[[records]]\nname = \"Alice\"\nage = \"30\"\n\n[[records]]\nname = \"Bob\"\nage = \"25\"\n",
+		},
+		{
+			name:       "success: toml to csvpp",
+			args:       []string{"convert", "-i", "testdata/convert/simple.toml", "--to", "csvpp"},
+			wantOutput: "name,age\nAlice,30\nBob,25\n",
+		},
+		{
+			name: "success: csvpp to xml",
+			args: []string{"convert", "-i", "testdata/convert/simple.csvpp", "--to", "xml"},
+			wantOutput: `<?xml version="1.0" encoding="UTF-8"?>
+<records>
+  <record>
+    <name>Alice</name>
+    <age>30</age>
+  </record>
+  <record>
+    <name>Bob</name>
+    <age>25</age>
+  </record>
+</records>
+`,
+		},
+		{
+			name:       "success: xml to csvpp",
+			args:       []string{"convert", "-i", "testdata/convert/simple.xml", "--to", "csvpp"},
+			wantOutput: "name,age\nAlice,30\nBob,25\n",
+		},
 		{
 			name:    "error: missing output format",
 			args:    []string{"convert", "-i", "testdata/convert/simple.csvpp"},
@@ -95,6 +146,41 @@ func TestConvertRoundtrip(t *testing.T) {
 			fromFormat:   "yaml",
 			wantContains: []string{"name", "age", "Alice", "Bob"},
 		},
+		{
+			name:         "success: toml roundtrip",
+			inputFile:    "testdata/convert/nested.csvpp",
+			format:       "toml",
+			fromFormat:   "toml",
+			wantContains: []string{"name,tags[],geo(lat^lon),address[](street^city)", "Alice,go~rust,35.6^139.7,1-1 Chiyoda^Tokyo~5th Ave^New York"},
+		},
+		{
+			name:         "success: xml roundtrip",
+			inputFile:    "testdata/convert/nested.csvpp",
+			format:       "xml",
+			fromFormat:   "xml",
+			wantContains: []string{"name,tags[],geo(lat^lon),address[](street^city)", "Alice,go~rust,35.6^139.7,1-1 Chiyoda^Tokyo~5th Ave^New York"},
+		},
+		{
+			name:         "success: xml roundtrip of names that are not XML names",
+			inputFile:    "testdata/convert/names.csvpp",
+			format:       "xml",
+			fromFormat:   "xml",
+			wantContains: []string{"name,1st,-rank[]", "Alice,gold,1~2"},
+		},
+		{
+			name:         "success: parquet roundtrip",
+			inputFile:    "testdata/convert/nested.csvpp",
+			format:       "parquet",
+			fromFormat:   "parquet",
+			wantContains: []string{"name,tags[],geo(lat^lon),address[](street^city)", "Alice,go~rust,35.6^139.7,1-1 Chiyoda^Tokyo~5th Ave^New York", "Bob,python,40.7^-74.0,"},
+		},
+		{
+			name:         "success: msgpack roundtrip",
+			inputFile:    "testdata/convert/nested.csvpp",
+			format:       "msgpack",
+			fromFormat:   "msgpack",
+			wantContains: []string{"name,tags[],geo(lat^lon),address[](street^city)", "Bob,python,40.7^-74.0,"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -191,3 +277,200 @@ func TestConvertWithFromFlag(t *testing.T) {
 		})
 	}
 }
+
+func TestConvertCSV(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantErr    bool
+		wantOutput string
+	}{
+		{
+			name: "success: csvpp to csv with joined arrays",
+			args: []string{"convert", "-i", "testdata/convert/nested.csvpp", "--to", "csv"},
+			wantOutput: "name,tags[],geo.lat,geo.lon,address[].street,address[].city\n" +
+				"Alice,go~rust,35.6,139.7,1-1 Chiyoda~5th Ave,Tokyo~New York\n" +
+				"Bob,python,40.7,-74.0,,\n",
+		},
+		{
+			name: "success: csvpp to csv with indexed arrays",
+			args: []string{"convert", "-i", "testdata/convert/nested.csvpp", "--to", "csv", "--csv-arrays", "indexed"},
+			wantOutput: "name,tags[0],tags[1],geo.lat,geo.lon,address[0].street,address[0].city,address[1].street,address[1].city\n" +
+				"Alice,go,rust,35.6,139.7,1-1 Chiyoda,Tokyo,5th Ave,New York\n" +
+				"Bob,python,,40.7,-74.0,,,,\n",
+		},
+		{
+			name: "success: csv to csvpp by column names",
+			args: []string{"convert", "-i", "testdata/convert/nested.csv", "--from", "csv", "--to", "csvpp"},
+			wantOutput: "name,tags[],geo(lat^lon),address[](street^city)\n" +
+				"Alice,go~rust,35.6^139.7,1-1 Chiyoda^Tokyo~5th Ave^New York\n" +
+				"Bob,python,40.7^-74.0,\n",
+		},
+		{
+			name:       "success: csv to json with schema",
+			args:       []string{"convert", "-i", "testdata/convert/nested.csv", "--from", "csv", "--to", "json", "--schema", "name,tags[|],geo(lat^lon)"},
+			wantOutput: "[{\"name\":\"Alice\",\"tags\":[\"go\",\"rust\"],\"geo\":{\"lat\":\"35.6\",\"lon\":\"139.7\"}},{\"name\":\"Bob\",\"tags\":[\"python\"],\"geo\":{\"lat\":\"40.7\",\"lon\":\"-74.0\"}}]\n",
+		},
+		{
+			name: "success: csv to csvpp with schema file",
+			args: []string{"convert", "-i", "testdata/convert/nested.csv", "--from", "csv", "--to", "csvpp", "--schema", "testdata/convert/nested.csvpp"},
+			wantOutput: "name,tags[],geo(lat^lon),address[](street^city)\n" +
+				"Alice,go~rust,35.6^139.7,1-1 Chiyoda^Tokyo~5th Ave^New York\n" +
+				"Bob,python,40.7^-74.0,\n",
+		},
+		{
+			name:    "error: invalid csv-arrays",
+			args:    []string{"convert", "-i", "testdata/convert/nested.csvpp", "--to", "csv", "--csv-arrays", "nested"},
+			wantErr: true,
+		},
+		{
+			name:    "error: schema with yaml input",
+			args:    []string{"convert", "-i", "testdata/convert/simple.yaml", "--to", "csvpp", "--schema", "name"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			stdout, _, err := runCommand(t, tt.args...)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error but got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+
+			if diff := cmp.Diff(tt.wantOutput, stdout); diff != "" {
+				t.Errorf("output mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestConvertTyped(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantErr    bool
+		wantOutput string
+	}{
+		{
+			name:       "success: typed json",
+			args:       []string{"convert", "-i", "testdata/convert/typed.csvpp", "--to", "json", "--typed"},
+			wantOutput: `[{"id":1,"name":"Alice","active":true,"score":35.60,"geo":{"lat":35.6,"lon":139.7},"code":"007"},{"id":2,"name":"Bob","active":false,"score":null,"geo":{"lat":40.7,"lon":-74.0},"code":12}]` + "\n",
+		},
+		{
+			name:       "success: json with field types",
+			args:       []string{"convert", "-i", "testdata/convert/typed.csvpp", "--to", "ndjson", "--type", "id=number", "--type", "geo.lat=auto", "--type", "active=bool"},
+			wantOutput: `{"id":1,"name":"Alice","active":true,"score":"35.60","geo":{"lat":35.6,"lon":"139.7"},"code":"007"}` + "\n" + `{"id":2,"name":"Bob","active":false,"score":"","geo":{"lat":40.7,"lon":"-74.0"},"code":"12"}` + "\n",
+		},
+		{
+			name:       "success: typed with string override",
+			args:       []string{"convert", "-i", "testdata/convert/typed.csvpp", "--to", "yaml", "--typed", "--type", "code=string"},
+			wantOutput: "- id: 1\n  name: Alice\n  active: true\n  score: \"35.60\"\n  geo:\n    lat: 35.6\n    lon: 139.7\n  code: \"007\"\n- id: 2\n  name: Bob\n  active: false\n  score: null\n  geo:\n    lat: 40.7\n    lon: \"-74.0\"\n  code: \"12\"\n",
+		},
+		{
+			name:    "error: invalid type spec",
+			args:    []string{"convert", "-i", "testdata/convert/typed.csvpp", "--to", "json", "--type", "id"},
+			wantErr: true,
+		},
+		{
+			name:    "error: unknown type",
+			args:    []string{"convert", "-i", "testdata/convert/typed.csvpp", "--to", "json", "--type", "id=date"},
+			wantErr: true,
+		},
+		{
+			name:    "error: typed csv output",
+			args:    []string{"convert", "-i", "testdata/convert/typed.csvpp", "--to", "csv", "--typed"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			stdout, _, err := runCommand(t, tt.args...)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error but got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+
+			if diff := cmp.Diff(tt.wantOutput, stdout); diff != "" {
+				t.Errorf("output mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestConvertTypedRoundtrip(t *testing.T) {
+	t.Parallel()
+
+	const inputFile = "testdata/convert/typed.csvpp"
+	want, err := os.ReadFile(inputFile)
+	if err != nil {
+		t.Fatalf("failed to read input: %v", err)
+	}
+
+	for _, format := range []string{"json", "ndjson", "yaml"} {
+		t.Run("success: "+format, func(t *testing.T) {
+			t.Parallel()
+
+			tmpDir := t.TempDir()
+			intermediateFile := filepath.Join(tmpDir, "intermediate."+format)
+			if _, _, err := runCommand(t, "convert", "-i", inputFile, "-o", intermediateFile, "--typed"); err != nil {
+				t.Fatalf("step 1 (to %s) failed: %v", format, err)
+			}
+
+			got, _, err := runCommand(t, "convert", "-i", intermediateFile, "--to", "csvpp")
+			if err != nil {
+				t.Fatalf("step 2 (to csvpp) failed: %v", err)
+			}
+			if diff := cmp.Diff(string(want), got); diff != "" {
+				t.Errorf("roundtrip mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestConvertWarnings(t *testing.T) {
+	t.Parallel()
+
+	stdout, stderr, err := runCommand(t, "convert", "-i", "testdata/convert/mixed.json", "--to", "csvpp")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantOutput := "name,geo(lat^lon),tags[],address[](city^zip)\n" +
+		"Alice,35.6^139.7,go,\n" +
+		"Bob,,python,\n" +
+		"Carol,,,Tokyo^~Osaka^530\n"
+	if diff := cmp.Diff(wantOutput, stdout); diff != "" {
+		t.Errorf("output mismatch (-want +got):\n%s", diff)
+	}
+
+	wantStderr := "warning: record 2: geo: value \"unknown\" dropped, expected an object\n"
+	if diff := cmp.Diff(wantStderr, stderr); diff != "" {
+		t.Errorf("stderr mismatch (-want +got):\n%s", diff)
+	}
+}
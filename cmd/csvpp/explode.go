@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/osamingo/go-csvpp"
+	"github.com/osamingo/go-csvpp/cmd/csvpp/internal/fileutil"
+	"github.com/osamingo/go-csvpp/csvpputil"
+)
+
+var explodeCmd = &cobra.Command{
+	Use:   "explode [file]",
+	Short: "Turn an array field into one record per element",
+	Long: `Turn an array or array-structured field into one record per element.
+Reads from file or stdin if no file is specified.
+
+An array field becomes a simple field holding one element. The components of an
+array-structured field are promoted to columns named <column>_<component>.
+Records with an empty array are kept with empty element values.
+
+Examples:
+  csvpp explode -c tags people.csvpp
+  csvpp explode -c address people.csvpp -o addresses.csvpp
+  csvpp explode -c address people.csvpp | csvpp nest -c address`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runExplode,
+}
+
+var nestCmd = &cobra.Command{
+	Use:   "nest [file]",
+	Short: "Group consecutive records back into an array field",
+	Long: `Group consecutive records whose other fields are equal into one record,
+collecting the element values into an array field. This is the inverse of explode.
+Reads from file or stdin if no file is specified.
+
+If a simple field named <column> exists its values become an array field;
+otherwise the <column>_<component> columns become an array-structured field.
+
+Examples:
+  csvpp nest -c tags exploded.csvpp
+  csvpp nest -c address exploded.csvpp -o people.csvpp`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runNest,
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{explodeCmd, nestCmd} {
+		cmd.Flags().StringP("column", "c", "", "array field name")
+		cmd.Flags().StringP("output", "o", "", "output file (writes to stdout if not specified)")
+		if err := cmd.MarkFlagRequired("column"); err != nil {
+			panic(err)
+		}
+		rootCmd.AddCommand(cmd)
+	}
+}
+
+func runExplode(cmd *cobra.Command, args []string) error {
+	return runTransform(cmd, args, csvpputil.Explode)
+}
+
+func runNest(cmd *cobra.Command, args []string) error {
+	return runTransform(cmd, args, csvpputil.Nest)
+}
+
+// runTransform runs a column transformation from the input to the output of cmd.
+func runTransform(cmd *cobra.Command, args []string, transform func(*csvpp.Writer, *csvpp.Reader, string) error) (retErr error) {
+	column, err := cmd.Flags().GetString("column")
+	if err != nil {
+		return err
+	}
+	outputFile, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return err
+	}
+
+	r, err := fileutil.OpenInputFromArgs(args)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := r.Close(); cerr != nil && retErr == nil {
+			retErr = fmt.Errorf("failed to close input: %w", cerr)
+		}
+	}()
+
+	w, err := fileutil.OpenOutput(outputFile, cmd.OutOrStdout())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := w.Close(); cerr != nil && retErr == nil {
+			retErr = fmt.Errorf("failed to close output: %w", cerr)
+		}
+	}()
+
+	return transform(csvpp.NewWriter(w), csvpp.NewReader(r), column)
+}
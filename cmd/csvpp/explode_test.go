@@ -0,0 +1,82 @@
+package main_test
+
+import (
+	"testing"
+)
+
+func TestExplodeNestCommands(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantErr    bool
+		wantOutput string
+	}{
+		{
+			name: "success: explode array-structured field",
+			args: []string{"explode", "-c", "address", "testdata/explode/people.csvpp"},
+			wantOutput: "name,tags[],address_street,address_city\n" +
+				"Alice,go~rust,1-1 Chiyoda,Tokyo\n" +
+				"Alice,go~rust,5th Ave,New York\n" +
+				"Bob,python,Main St,Boston\n" +
+				"Carol,,,\n",
+		},
+		{
+			name: "success: explode array field",
+			args: []string{"explode", "--column", "tags", "testdata/explode/people.csvpp"},
+			wantOutput: "name,tags,address[](street^city)\n" +
+				"Alice,go,1-1 Chiyoda^Tokyo~5th Ave^New York\n" +
+				"Alice,rust,1-1 Chiyoda^Tokyo~5th Ave^New York\n" +
+				"Bob,python,Main St^Boston\n" +
+				"Carol,,\n",
+		},
+		{
+			name: "success: nest promoted columns",
+			args: []string{"nest", "-c", "address", "testdata/explode/addresses.csvpp"},
+			wantOutput: "name,tags[],address[](street^city)\n" +
+				"Alice,go~rust,1-1 Chiyoda^Tokyo~5th Ave^New York\n" +
+				"Bob,python,Main St^Boston\n" +
+				"Carol,,\n",
+		},
+		{
+			name:    "error: missing column",
+			args:    []string{"explode", "testdata/explode/people.csvpp"},
+			wantErr: true,
+		},
+		{
+			name:    "error: not an array field",
+			args:    []string{"explode", "-c", "name", "testdata/explode/people.csvpp"},
+			wantErr: true,
+		},
+		{
+			name:    "error: nothing to nest",
+			args:    []string{"nest", "-c", "email", "testdata/explode/people.csvpp"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			stdout, _, err := runCommand(t, tt.args...)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error but got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+
+			if stdout != tt.wantOutput {
+				t.Errorf("output = %q, want %q", stdout, tt.wantOutput)
+			}
+		})
+	}
+}
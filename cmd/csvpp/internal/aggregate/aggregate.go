@@ -0,0 +1,406 @@
+// Package aggregate implements grouping and aggregation for the csvpp agg command.
+//
+// Records are grouped by the values of one or more field paths, and each group
+// produces one output record with the group values followed by the requested
+// aggregates. Collected values keep their CSV++ shape: simple values and arrays
+// are collected into an array field, and structured values into an
+// array-structured field.
+package aggregate
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/osamingo/go-csvpp"
+)
+
+// ErrInvalidSpec is returned when a Spec does not fit the input headers.
+var ErrInvalidSpec = errors.New("aggregate: invalid specification")
+
+// Spec describes how to group and aggregate records.
+// Every entry is a field path in csvpp.Record syntax.
+type Spec struct {
+	GroupBy []string // Paths whose values identify a group; each must select a single value
+	Count   bool     // Emit the number of records in each group
+	Sum     []string // Paths to sum numerically
+	Avg     []string // Paths to average numerically
+	Min     []string // Paths to take the minimum of
+	Max     []string // Paths to take the maximum of
+	Collect []string // Paths whose values are collected into an array
+}
+
+// Aggregator accumulates records into groups.
+type Aggregator struct {
+	headers []*csvpp.ColumnHeader // input headers
+	out     []*csvpp.ColumnHeader // output headers
+	groupBy []*csvpp.Path
+	count   bool
+	aggs    []aggregate
+	groups  map[string]*group
+	order   []*group // groups in order of first appearance
+}
+
+// group is the accumulated state of one group.
+type group struct {
+	key    []string
+	count  int
+	states []state
+}
+
+// aggregate is one output column computed from a path.
+type aggregate struct {
+	kind   string // "sum", "avg", "min", "max", "collect"
+	path   *csvpp.Path
+	header *csvpp.ColumnHeader // header of the value selected by path
+}
+
+// state accumulates the values of one aggregate within a group.
+type state struct {
+	sum    float64
+	n      int
+	values []string       // min/max candidates and collected simple values
+	elems  []*csvpp.Field // collected structured values
+}
+
+// New validates spec against headers and returns an empty Aggregator.
+func New(headers []*csvpp.ColumnHeader, spec Spec) (*Aggregator, error) {
+	a := &Aggregator{headers: headers, groups: make(map[string]*group)}
+
+	for _, s := range spec.GroupBy {
+		p, h, err := resolve(headers, s)
+		if err != nil {
+			return nil, err
+		}
+		if h.Kind != csvpp.SimpleField {
+			return nil, fmt.Errorf("%w: group-by %q must select a simple value", ErrInvalidSpec, s)
+		}
+		a.groupBy = append(a.groupBy, p)
+		a.out = append(a.out, &csvpp.ColumnHeader{Name: columnName(s), Kind: csvpp.SimpleField})
+	}
+
+	if spec.Count {
+		a.count = true
+		a.out = append(a.out, &csvpp.ColumnHeader{Name: "count", Kind: csvpp.SimpleField})
+	}
+
+	for _, list := range []struct {
+		kind  string
+		paths []string
+	}{
+		{"sum", spec.Sum},
+		{"avg", spec.Avg},
+		{"min", spec.Min},
+		{"max", spec.Max},
+	} {
+		for _, s := range list.paths {
+			p, h, err := resolve(headers, s)
+			if err != nil {
+				return nil, err
+			}
+			if h.Kind != csvpp.SimpleField && h.Kind != csvpp.ArrayField {
+				return nil, fmt.Errorf("%w: %s %q is structured; select a component", ErrInvalidSpec, list.kind, s)
+			}
+			a.aggs = append(a.aggs, aggregate{kind: list.kind, path: p, header: h})
+			a.out = append(a.out, &csvpp.ColumnHeader{Name: list.kind + "_" + columnName(s), Kind: csvpp.SimpleField})
+		}
+	}
+
+	for _, s := range spec.Collect {
+		p, h, err := resolve(headers, s)
+		if err != nil {
+			return nil, err
+		}
+		a.aggs = append(a.aggs, aggregate{kind: "collect", path: p, header: h})
+		a.out = append(a.out, collectHeader(columnName(s), h))
+	}
+
+	if len(a.out) == 0 {
+		return nil, fmt.Errorf("%w: nothing to output", ErrInvalidSpec)
+	}
+	seen := make(map[string]bool, len(a.out))
+	for _, h := range a.out {
+		if seen[h.Name] {
+			return nil, fmt.Errorf("%w: output column %q appears more than once", ErrInvalidSpec, h.Name)
+		}
+		seen[h.Name] = true
+	}
+
+	return a, nil
+}
+
+// Headers returns the output headers. The array delimiter of a column that
+// collects simple values is the first of csvpp.Delimiters that occurs in none
+// of the collected values, so call Headers after every record was added.
+// It returns an error wrapping csvpp.ErrNoFreeDelimiter if there is none.
+func (a *Aggregator) Headers() ([]*csvpp.ColumnHeader, error) {
+	out := slices.Clone(a.out)
+	offset := len(a.out) - len(a.aggs)
+	for i, agg := range a.aggs {
+		if agg.kind != "collect" || agg.header.Kind != csvpp.SimpleField {
+			continue
+		}
+		d, err := a.collectDelimiter(i)
+		if err != nil {
+			return nil, fmt.Errorf("%w: field %q", err, out[offset+i].Name)
+		}
+		h := *out[offset+i]
+		h.ArrayDelimiter = d
+		out[offset+i] = &h
+	}
+	return out, nil
+}
+
+// collectDelimiter returns the first delimiter candidate that occurs in none of
+// the values collected by the i-th aggregate.
+func (a *Aggregator) collectDelimiter(i int) (rune, error) {
+	for _, d := range csvpp.Delimiters() {
+		if !slices.ContainsFunc(a.order, func(g *group) bool {
+			return slices.ContainsFunc(g.states[i].values, func(v string) bool {
+				return strings.ContainsRune(v, d)
+			})
+		}) {
+			return d, nil
+		}
+	}
+	return 0, csvpp.ErrNoFreeDelimiter
+}
+
+// Add adds a record to its group. A group-by path that selects nothing in
+// the record, such as "tags[0]" of an empty array, groups by an empty value.
+func (a *Aggregator) Add(fields []*csvpp.Field) error {
+	rec := csvpp.NewRecord(a.headers, fields)
+
+	key := make([]string, len(a.groupBy))
+	for i, p := range a.groupBy {
+		v, err := rec.GetPath(p)
+		if err != nil && !errors.Is(err, csvpp.ErrFieldNotFound) {
+			return err
+		}
+		key[i] = v
+	}
+
+	id := groupID(key)
+	g, ok := a.groups[id]
+	if !ok {
+		g = &group{key: key, states: make([]state, len(a.aggs))}
+		a.groups[id] = g
+		a.order = append(a.order, g)
+	}
+	g.count++
+
+	for i, agg := range a.aggs {
+		if err := g.states[i].add(rec, agg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Results returns one record per group in order of first appearance.
+// Without group-by paths there is always exactly one group, even for empty input.
+func (a *Aggregator) Results() [][]*csvpp.Field {
+	groups := a.order
+	if len(groups) == 0 && len(a.groupBy) == 0 {
+		groups = []*group{{states: make([]state, len(a.aggs))}}
+	}
+
+	results := make([][]*csvpp.Field, len(groups))
+	for i, g := range groups {
+		record := make([]*csvpp.Field, 0, len(a.out))
+		for _, k := range g.key {
+			record = append(record, &csvpp.Field{Value: k})
+		}
+		if a.count {
+			record = append(record, &csvpp.Field{Value: strconv.Itoa(g.count)})
+		}
+		for j, agg := range a.aggs {
+			record = append(record, g.states[j].result(agg))
+		}
+		results[i] = record
+	}
+	return results
+}
+
+// add accumulates the values selected by agg in rec.
+func (s *state) add(rec *csvpp.Record, agg aggregate) error {
+	if agg.kind == "collect" && (agg.header.Kind == csvpp.StructuredField || agg.header.Kind == csvpp.ArrayStructuredField) {
+		elems, err := rec.ElementsPath(agg.path)
+		if err != nil && !errors.Is(err, csvpp.ErrFieldNotFound) {
+			return err
+		}
+		for _, e := range elems {
+			s.elems = append(s.elems, &csvpp.Field{Components: e.Fields})
+		}
+		return nil
+	}
+
+	values, err := rec.GetAllPath(agg.path)
+	if err != nil && !errors.Is(err, csvpp.ErrFieldNotFound) {
+		return err
+	}
+
+	switch agg.kind {
+	case "sum", "avg":
+		for _, v := range values {
+			f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil {
+				continue // empty and non-numeric values are skipped
+			}
+			s.sum += f
+			s.n++
+		}
+	case "min", "max":
+		for _, v := range values {
+			if v != "" {
+				s.values = append(s.values, v)
+			}
+		}
+	default:
+		s.values = append(s.values, values...)
+	}
+	return nil
+}
+
+// result returns the output field for agg.
+func (s *state) result(agg aggregate) *csvpp.Field {
+	switch agg.kind {
+	case "sum":
+		return &csvpp.Field{Value: formatFloat(s.sum)}
+	case "avg":
+		if s.n == 0 {
+			return &csvpp.Field{}
+		}
+		return &csvpp.Field{Value: formatFloat(s.sum / float64(s.n))}
+	case "min":
+		if len(s.values) == 0 {
+			return &csvpp.Field{}
+		}
+		return &csvpp.Field{Value: slices.MinFunc(s.values, compareValues(s.values))}
+	case "max":
+		if len(s.values) == 0 {
+			return &csvpp.Field{}
+		}
+		return &csvpp.Field{Value: slices.MaxFunc(s.values, compareValues(s.values))}
+	default:
+		if agg.header.Kind == csvpp.StructuredField || agg.header.Kind == csvpp.ArrayStructuredField {
+			return &csvpp.Field{Components: s.elems}
+		}
+		return &csvpp.Field{Values: s.values}
+	}
+}
+
+// compareValues returns a comparison that is numeric if every value is a number
+// and lexical otherwise.
+func compareValues(values []string) func(a, b string) int {
+	nums := make(map[string]float64, len(values))
+	for _, v := range values {
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return strings.Compare
+		}
+		nums[v] = f
+	}
+	return func(a, b string) int {
+		return cmp.Compare(nums[a], nums[b])
+	}
+}
+
+// resolve parses s and returns it with the header of the value it selects.
+func resolve(headers []*csvpp.ColumnHeader, s string) (*csvpp.Path, *csvpp.ColumnHeader, error) {
+	p, err := csvpp.ParsePath(s)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrInvalidSpec, err)
+	}
+	h, err := p.Header(headers)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrInvalidSpec, err)
+	}
+	return p, h, nil
+}
+
+// collectHeader returns the output header for collecting values described by h.
+func collectHeader(name string, h *csvpp.ColumnHeader) *csvpp.ColumnHeader {
+	switch h.Kind {
+	case csvpp.ArrayField:
+		return &csvpp.ColumnHeader{Name: name, Kind: csvpp.ArrayField, ArrayDelimiter: h.ArrayDelimiter}
+	case csvpp.StructuredField:
+		return &csvpp.ColumnHeader{
+			Name:               name,
+			Kind:               csvpp.ArrayStructuredField,
+			ArrayDelimiter:     freeDelimiter(h),
+			ComponentDelimiter: h.ComponentDelimiter,
+			Components:         h.Components,
+		}
+	case csvpp.ArrayStructuredField:
+		return &csvpp.ColumnHeader{
+			Name:               name,
+			Kind:               csvpp.ArrayStructuredField,
+			ArrayDelimiter:     h.ArrayDelimiter,
+			ComponentDelimiter: h.ComponentDelimiter,
+			Components:         h.Components,
+		}
+	default:
+		// The delimiter is chosen from the collected values in Headers.
+		return &csvpp.ColumnHeader{Name: name, Kind: csvpp.ArrayField, ArrayDelimiter: csvpp.DefaultArrayDelimiter}
+	}
+}
+
+// freeDelimiter returns an array delimiter not used anywhere within h.
+func freeDelimiter(h *csvpp.ColumnHeader) rune {
+	used := make(map[rune]bool)
+	var walk func(*csvpp.ColumnHeader)
+	walk = func(h *csvpp.ColumnHeader) {
+		used[h.ArrayDelimiter] = true
+		used[h.ComponentDelimiter] = true
+		for _, c := range h.Components {
+			walk(c)
+		}
+	}
+	walk(h)
+
+	for _, d := range csvpp.Delimiters() {
+		if !used[d] {
+			return d
+		}
+	}
+	return csvpp.DefaultArrayDelimiter
+}
+
+// columnName converts a path to an output column name by dropping indices
+// and joining segments with "_" ("address[].city" -> "address_city").
+func columnName(path string) string {
+	var sb strings.Builder
+	inIndex := false
+	for _, r := range path {
+		switch {
+		case r == '[':
+			inIndex = true
+		case r == ']':
+			inIndex = false
+		case inIndex:
+		case r == '.':
+			sb.WriteRune('_')
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// groupID returns a map key for a group key.
+func groupID(key []string) string {
+	var sb strings.Builder
+	for _, k := range key {
+		sb.WriteString(strconv.Quote(k))
+	}
+	return sb.String()
+}
+
+// formatFloat formats a sum or average without trailing zeros.
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
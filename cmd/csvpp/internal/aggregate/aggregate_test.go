@@ -0,0 +1,238 @@
+package aggregate_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/osamingo/go-csvpp"
+	"github.com/osamingo/go-csvpp/cmd/csvpp/internal/aggregate"
+)
+
+const testInput = `id,country,amount,tags[],customer(name^city),items[](sku^qty)
+1,JP,100,new~gift,Alice^Tokyo,A^1~B^2
+2,US,250,,Bob^Boston,C^5
+3,JP,50.5,gift,Carol^Osaka,
+4,US,,vip,Dave^Denver,A^3
+`
+
+// run aggregates testInput with spec and returns the output as CSV++.
+func run(t *testing.T, spec aggregate.Spec) (string, error) {
+	t.Helper()
+
+	reader := csvpp.NewReader(strings.NewReader(testInput))
+	headers, err := reader.Headers()
+	if err != nil {
+		t.Fatalf("Headers() error = %v", err)
+	}
+	records, err := reader.ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+
+	agg, err := aggregate.New(headers, spec)
+	if err != nil {
+		return "", err
+	}
+	for _, record := range records {
+		if err := agg.Add(record); err != nil {
+			return "", err
+		}
+	}
+
+	outHeaders, err := agg.Headers()
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	w := csvpp.NewWriter(&buf)
+	w.SetHeaders(outHeaders)
+	if err := w.WriteAll(agg.Results()); err != nil {
+		t.Fatalf("WriteAll() error = %v", err)
+	}
+	return buf.String(), nil
+}
+
+func TestAggregator(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		spec aggregate.Spec
+		want string
+	}{
+		{
+			name: "success: count and numeric aggregates",
+			spec: aggregate.Spec{
+				GroupBy: []string{"country"},
+				Count:   true,
+				Sum:     []string{"amount"},
+				Avg:     []string{"amount"},
+				Min:     []string{"id"},
+				Max:     []string{"items[].qty"},
+			},
+			want: "country,count,sum_amount,avg_amount,min_id,max_items_qty\n" +
+				"JP,2,150.5,75.25,1,2\n" +
+				"US,2,250,250,2,5\n",
+		},
+		{
+			name: "success: collect simple and array values into array fields",
+			spec: aggregate.Spec{GroupBy: []string{"country"}, Collect: []string{"id", "tags", "customer.city"}},
+			want: "country,id[],tags[],customer_city[]\n" +
+				"JP,1~3,new~gift~gift,Tokyo~Osaka\n" +
+				"US,2~4,vip,Boston~Denver\n",
+		},
+		{
+			name: "success: collect structured values into array-structured fields",
+			spec: aggregate.Spec{GroupBy: []string{"country"}, Collect: []string{"customer", "items"}},
+			want: "country,customer[](name^city),items[](sku^qty)\n" +
+				"JP,Alice^Tokyo~Carol^Osaka,A^1~B^2\n" +
+				"US,Bob^Boston~Dave^Denver,C^5~A^3\n",
+		},
+		{
+			name: "success: group by component",
+			spec: aggregate.Spec{GroupBy: []string{"customer.city"}, Count: true},
+			want: "customer_city,count\n" +
+				"Tokyo,1\n" +
+				"Boston,1\n" +
+				"Osaka,1\n" +
+				"Denver,1\n",
+		},
+		{
+			name: "success: group by an index beyond short arrays",
+			spec: aggregate.Spec{GroupBy: []string{"tags[0]"}, Count: true},
+			want: "tags,count\n" +
+				"new,1\n" +
+				",1\n" +
+				"gift,1\n" +
+				"vip,1\n",
+		},
+		{
+			name: "success: no group by is a single group",
+			spec: aggregate.Spec{Count: true, Sum: []string{"items[].qty"}, Min: []string{"country"}},
+			want: "count,sum_items_qty,min_country\n" +
+				"4,11,JP\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := run(t, tt.spec)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("output mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNew_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		spec aggregate.Spec
+	}{
+		{name: "error: empty spec", spec: aggregate.Spec{}},
+		{name: "error: unknown field", spec: aggregate.Spec{GroupBy: []string{"region"}}},
+		{name: "error: group by array", spec: aggregate.Spec{GroupBy: []string{"tags"}}},
+		{name: "error: group by structured", spec: aggregate.Spec{GroupBy: []string{"customer"}}},
+		{name: "error: sum structured", spec: aggregate.Spec{Sum: []string{"items"}}},
+		{name: "error: duplicate output column", spec: aggregate.Spec{GroupBy: []string{"id"}, Collect: []string{"id"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := run(t, tt.spec); !errors.Is(err, aggregate.ErrInvalidSpec) {
+				t.Errorf("error = %v, want %v", err, aggregate.ErrInvalidSpec)
+			}
+		})
+	}
+}
+
+func TestNew_CountColumnCollision(t *testing.T) {
+	t.Parallel()
+
+	headers := []*csvpp.ColumnHeader{{Name: "count", Kind: csvpp.SimpleField}}
+	if _, err := aggregate.New(headers, aggregate.Spec{GroupBy: []string{"count"}, Count: true}); !errors.Is(err, aggregate.ErrInvalidSpec) {
+		t.Errorf("error = %v, want %v", err, aggregate.ErrInvalidSpec)
+	}
+}
+
+func TestAggregator_MultiValuedGroupKey(t *testing.T) {
+	t.Parallel()
+
+	if _, err := run(t, aggregate.Spec{GroupBy: []string{"items[].sku"}}); !errors.Is(err, csvpp.ErrInvalidPath) {
+		t.Errorf("error = %v, want %v", err, csvpp.ErrInvalidPath)
+	}
+}
+
+func TestAggregator_CollectDelimiter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		values  []string
+		want    rune
+		wantErr error
+	}{
+		{name: "success: default delimiter", values: []string{"a", "b"}, want: '~'},
+		{name: "success: values contain the default delimiter", values: []string{"a~b", "c"}, want: '^'},
+		{name: "success: values contain several delimiters", values: []string{"a~b", "c^d"}, want: ';'},
+		{name: "error: values contain every delimiter", values: []string{string(csvpp.Delimiters())}, wantErr: csvpp.ErrNoFreeDelimiter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			headers := []*csvpp.ColumnHeader{{Name: "name", Kind: csvpp.SimpleField}}
+			agg, err := aggregate.New(headers, aggregate.Spec{Collect: []string{"name"}})
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			for _, v := range tt.values {
+				if err := agg.Add([]*csvpp.Field{{Value: v}}); err != nil {
+					t.Fatalf("Add() error = %v", err)
+				}
+			}
+
+			got, err := agg.Headers()
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Headers() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Headers() error = %v", err)
+			}
+			if got[0].ArrayDelimiter != tt.want {
+				t.Errorf("ArrayDelimiter = %q, want %q", got[0].ArrayDelimiter, tt.want)
+			}
+
+			var buf bytes.Buffer
+			w := csvpp.NewWriter(&buf)
+			w.SetHeaders(got)
+			if err := w.WriteAll(agg.Results()); err != nil {
+				t.Fatalf("WriteAll() error = %v", err)
+			}
+			records, err := csvpp.NewReader(&buf).ReadAll()
+			if err != nil {
+				t.Fatalf("ReadAll() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.values, records[0][0].Values); diff != "" {
+				t.Errorf("round-tripped values mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
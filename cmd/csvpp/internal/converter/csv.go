@@ -0,0 +1,347 @@
+package converter
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/osamingo/go-csvpp"
+)
+
+// FromCSV reads plain CSV and nests it into CSVPP headers and records.
+//
+// Column names follow the dotted/indexed convention produced by csvpputil.CSVWriter:
+//
+//	name               simple field
+//	geo.lat            component "lat" of structured field "geo"
+//	tags[0], tags[1]   array field, one column per element
+//	tags[]             array field, elements joined with the array delimiter
+//	address[0].city    component of array-structured field "address"
+//	address[].city     array-structured component, elements joined with the array delimiter
+//
+// If schema is non-nil it defines the output headers instead, and each field is
+// looked up by the same convention; a plain column name ("tags") is also accepted
+// for a joined array. Columns not described by the headers are ignored.
+// Characters that are not valid in CSVPP names are replaced with '_'.
+func FromCSV(r io.Reader, schema []*csvpp.ColumnHeader) ([]*csvpp.ColumnHeader, [][]*csvpp.Field, error) {
+	reader := csv.NewReader(r)
+
+	names, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	columns := make(map[string]int, len(names))
+	for i, name := range names {
+		key := canonicalColumn(name)
+		if _, ok := columns[key]; ok {
+			return nil, nil, fmt.Errorf("duplicate CSV column %q", name)
+		}
+		columns[key] = i
+	}
+
+	headers := schema
+	if headers == nil {
+		headers, err = inferCSVHeaders(names)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var records [][]*csvpp.Field
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read CSV record: %w", err)
+		}
+		lookup := columnLookup(columns, row)
+		record := make([]*csvpp.Field, len(headers))
+		for i, h := range headers {
+			record[i] = nestField(h, "", lookup)
+		}
+		records = append(records, record)
+	}
+
+	return headers, records, nil
+}
+
+// columnSegment is one dot-separated part of a column name.
+type columnSegment struct {
+	name  string
+	index string // "" for no index, "*" for "[]", otherwise the element index
+}
+
+// parseColumn splits a column name such as "address[0].city" into segments.
+// Invalid name characters are replaced with '_'.
+func parseColumn(name string) []columnSegment {
+	parts := strings.Split(strings.TrimSpace(name), ".")
+	segments := make([]columnSegment, len(parts))
+	for i, part := range parts {
+		seg := columnSegment{name: part}
+		if open := strings.IndexByte(part, '['); open > 0 && strings.HasSuffix(part, "]") {
+			index := part[open+1 : len(part)-1]
+			if index == "" {
+				seg = columnSegment{name: part[:open], index: "*"}
+			} else if _, err := strconv.Atoi(index); err == nil {
+				seg = columnSegment{name: part[:open], index: index}
+			}
+		}
+		seg.name = sanitizeName(seg.name)
+		segments[i] = seg
+	}
+	return segments
+}
+
+// canonicalColumn returns the normalized form of a column name used for lookups.
+func canonicalColumn(name string) string {
+	segments := parseColumn(name)
+	parts := make([]string, len(segments))
+	for i, seg := range segments {
+		switch seg.index {
+		case "":
+			parts[i] = seg.name
+		case "*":
+			parts[i] = seg.name + "[]"
+		default:
+			parts[i] = seg.name + "[" + seg.index + "]"
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// sanitizeName replaces characters that are not valid in CSVPP names with '_'.
+func sanitizeName(name string) string {
+	if name == "" {
+		return "_"
+	}
+	if csvpp.IsValidName(name) {
+		return name
+	}
+	return strings.Map(func(r rune) rune {
+		if csvpp.IsValidName(string(r)) {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// columnNode is a node of the header tree inferred from column names.
+type columnNode struct {
+	name     string
+	array    bool
+	children []*columnNode
+	byName   map[string]*columnNode
+}
+
+// child returns the child named name, creating it if needed.
+func (n *columnNode) child(name string) *columnNode {
+	if c, ok := n.byName[name]; ok {
+		return c
+	}
+	c := &columnNode{name: name, byName: make(map[string]*columnNode)}
+	n.children = append(n.children, c)
+	n.byName[name] = c
+	return c
+}
+
+// inferCSVHeaders builds headers from column names in order of first appearance.
+func inferCSVHeaders(names []string) ([]*csvpp.ColumnHeader, error) {
+	root := &columnNode{byName: make(map[string]*columnNode)}
+	for _, name := range names {
+		n := root
+		for _, seg := range parseColumn(name) {
+			n = n.child(seg.name)
+			if seg.index != "" {
+				n.array = true
+			}
+		}
+	}
+	headers, err := nodeHeaders(root.children, 0)
+	if err != nil {
+		return nil, err
+	}
+	return csvpp.AssignDelimiters(headers, nil)
+}
+
+// nodeHeaders converts tree nodes at the given nesting level into headers
+// without delimiters.
+func nodeHeaders(nodes []*columnNode, level int) ([]*csvpp.ColumnHeader, error) {
+	if 2*level+1 >= len(csvpp.Delimiters()) {
+		return nil, fmt.Errorf("CSV columns are nested too deeply")
+	}
+
+	headers := make([]*csvpp.ColumnHeader, 0, len(nodes))
+	for _, n := range nodes {
+		h := &csvpp.ColumnHeader{Name: n.name}
+		switch {
+		case n.array && len(n.children) > 0:
+			h.Kind = csvpp.ArrayStructuredField
+		case n.array:
+			h.Kind = csvpp.ArrayField
+		case len(n.children) > 0:
+			h.Kind = csvpp.StructuredField
+		default:
+			h.Kind = csvpp.SimpleField
+		}
+		if len(n.children) > 0 {
+			components, err := nodeHeaders(n.children, level+1)
+			if err != nil {
+				return nil, err
+			}
+			h.Components = components
+		}
+		headers = append(headers, h)
+	}
+	return headers, nil
+}
+
+// lookup returns the value of a canonical column name and whether the column exists.
+type lookup func(name string) (string, bool)
+
+// columnLookup returns a lookup over one CSV row.
+func columnLookup(columns map[string]int, row []string) lookup {
+	return func(name string) (string, bool) {
+		i, ok := columns[name]
+		if !ok || i >= len(row) {
+			return "", ok
+		}
+		return row[i], true
+	}
+}
+
+// nestField builds the field for h from the columns named prefix+h.Name...
+func nestField(h *csvpp.ColumnHeader, prefix string, get lookup) *csvpp.Field {
+	name := prefix + h.Name
+
+	switch h.Kind {
+	case csvpp.ArrayField:
+		for _, joined := range []string{name + "[]", name} {
+			if v, ok := get(joined); ok {
+				return &csvpp.Field{Values: splitJoined(v, h.ArrayDelimiter)}
+			}
+		}
+		var values []string
+		for i := 0; ; i++ {
+			v, ok := get(name + "[" + strconv.Itoa(i) + "]")
+			if !ok {
+				break
+			}
+			values = append(values, v)
+		}
+		for len(values) > 0 && values[len(values)-1] == "" {
+			values = values[:len(values)-1]
+		}
+		return &csvpp.Field{Values: values}
+
+	case csvpp.StructuredField:
+		components := make([]*csvpp.Field, len(h.Components))
+		for i, c := range h.Components {
+			components[i] = nestField(c, name+".", get)
+		}
+		return &csvpp.Field{Components: components}
+
+	case csvpp.ArrayStructuredField:
+		if elems, ok := nestJoinedElements(h, name+"[].", get); ok {
+			return &csvpp.Field{Components: elems}
+		}
+		if elems, ok := nestJoinedElements(h, name+".", get); ok {
+			return &csvpp.Field{Components: elems}
+		}
+		var elems []*csvpp.Field
+		for i := 0; ; i++ {
+			elemPrefix := name + "[" + strconv.Itoa(i) + "]."
+			elem, ok := nestElement(h, elemPrefix, get)
+			if !ok {
+				break
+			}
+			elems = append(elems, elem)
+		}
+		for len(elems) > 0 && isEmptyField(elems[len(elems)-1]) {
+			elems = elems[:len(elems)-1]
+		}
+		return &csvpp.Field{Components: elems}
+
+	default:
+		v, _ := get(name)
+		return &csvpp.Field{Value: v}
+	}
+}
+
+// nestElement builds one array-structured element from columns starting with prefix.
+// It reports false if no such column exists.
+func nestElement(h *csvpp.ColumnHeader, prefix string, get lookup) (*csvpp.Field, bool) {
+	found := false
+	probe := func(name string) (string, bool) {
+		v, ok := get(name)
+		found = found || ok
+		return v, ok
+	}
+	components := make([]*csvpp.Field, len(h.Components))
+	for i, c := range h.Components {
+		components[i] = nestField(c, prefix, probe)
+	}
+	return &csvpp.Field{Components: components}, found
+}
+
+// nestJoinedElements builds array-structured elements from component columns
+// starting with prefix whose values are joined with the array delimiter.
+// It reports false if no such column exists.
+func nestJoinedElements(h *csvpp.ColumnHeader, prefix string, get lookup) ([]*csvpp.Field, bool) {
+	// Split every column once and count the elements.
+	split := make(map[string][]string)
+	count := 0
+	probe := func(name string) (string, bool) {
+		v, ok := get(prefix + name)
+		if ok {
+			parts := splitJoined(v, h.ArrayDelimiter)
+			split[name] = parts
+			count = max(count, len(parts))
+		}
+		return "", ok
+	}
+	if _, ok := nestElement(h, "", probe); !ok {
+		return nil, false
+	}
+
+	elems := make([]*csvpp.Field, count)
+	for i := range elems {
+		elems[i], _ = nestElement(h, "", func(name string) (string, bool) {
+			parts, ok := split[name]
+			if !ok || i >= len(parts) {
+				return "", ok
+			}
+			return parts[i], true
+		})
+	}
+	return elems, true
+}
+
+// splitJoined splits a joined array value; an empty value has no elements.
+func splitJoined(v string, delim rune) []string {
+	if v == "" {
+		return nil
+	}
+	return strings.Split(v, string(delim))
+}
+
+// isEmptyField reports whether f holds no values at all.
+func isEmptyField(f *csvpp.Field) bool {
+	if f.Value != "" || len(f.Values) > 0 {
+		return false
+	}
+	for _, c := range f.Components {
+		if !isEmptyField(c) {
+			return false
+		}
+	}
+	return true
+}
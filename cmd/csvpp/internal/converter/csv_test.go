@@ -0,0 +1,181 @@
+package converter_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/osamingo/go-csvpp"
+	"github.com/osamingo/go-csvpp/cmd/csvpp/internal/converter"
+)
+
+func TestFromCSV(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		input       string
+		schema      string
+		wantHeaders []*csvpp.ColumnHeader
+		wantRecords [][]*csvpp.Field
+		wantErr     bool
+	}{
+		{
+			name:  "success: dotted and joined columns",
+			input: "name,geo.lat,geo.lon,tags[]\nAlice,35.6,139.7,go~rust\nBob,40.7,-74.0,\n",
+			wantHeaders: []*csvpp.ColumnHeader{
+				{Name: "name", Kind: csvpp.SimpleField, ArrayDelimiter: '~', ComponentDelimiter: '^'},
+				{Name: "geo", Kind: csvpp.StructuredField, ArrayDelimiter: '~', ComponentDelimiter: '^', Components: []*csvpp.ColumnHeader{
+					{Name: "lat", Kind: csvpp.SimpleField, ArrayDelimiter: ';', ComponentDelimiter: ':'},
+					{Name: "lon", Kind: csvpp.SimpleField, ArrayDelimiter: ';', ComponentDelimiter: ':'},
+				}},
+				{Name: "tags", Kind: csvpp.ArrayField, ArrayDelimiter: '~', ComponentDelimiter: '^'},
+			},
+			wantRecords: [][]*csvpp.Field{
+				{{Value: "Alice"}, {Components: []*csvpp.Field{{Value: "35.6"}, {Value: "139.7"}}}, {Values: []string{"go", "rust"}}},
+				{{Value: "Bob"}, {Components: []*csvpp.Field{{Value: "40.7"}, {Value: "-74.0"}}}, {}},
+			},
+		},
+		{
+			name:  "success: indexed columns with trailing empty elements trimmed",
+			input: "name,tags[0],tags[1],address[0].city,address[1].city\nAlice,go,rust,Tokyo,Osaka\nBob,python,,Boston,\n",
+			wantHeaders: []*csvpp.ColumnHeader{
+				{Name: "name", Kind: csvpp.SimpleField, ArrayDelimiter: '~', ComponentDelimiter: '^'},
+				{Name: "tags", Kind: csvpp.ArrayField, ArrayDelimiter: '~', ComponentDelimiter: '^'},
+				{Name: "address", Kind: csvpp.ArrayStructuredField, ArrayDelimiter: '~', ComponentDelimiter: '^', Components: []*csvpp.ColumnHeader{
+					{Name: "city", Kind: csvpp.SimpleField, ArrayDelimiter: ';', ComponentDelimiter: ':'},
+				}},
+			},
+			wantRecords: [][]*csvpp.Field{
+				{
+					{Value: "Alice"},
+					{Values: []string{"go", "rust"}},
+					{Components: []*csvpp.Field{
+						{Components: []*csvpp.Field{{Value: "Tokyo"}}},
+						{Components: []*csvpp.Field{{Value: "Osaka"}}},
+					}},
+				},
+				{
+					{Value: "Bob"},
+					{Values: []string{"python"}},
+					{Components: []*csvpp.Field{
+						{Components: []*csvpp.Field{{Value: "Boston"}}},
+					}},
+				},
+			},
+		},
+		{
+			name:  "success: joined array-structured columns",
+			input: "address[].street,address[].city\nMain St~5th Ave,Boston~New York\n",
+			wantHeaders: []*csvpp.ColumnHeader{
+				{Name: "address", Kind: csvpp.ArrayStructuredField, ArrayDelimiter: '~', ComponentDelimiter: '^', Components: []*csvpp.ColumnHeader{
+					{Name: "street", Kind: csvpp.SimpleField, ArrayDelimiter: ';', ComponentDelimiter: ':'},
+					{Name: "city", Kind: csvpp.SimpleField, ArrayDelimiter: ';', ComponentDelimiter: ':'},
+				}},
+			},
+			wantRecords: [][]*csvpp.Field{
+				{{Components: []*csvpp.Field{
+					{Components: []*csvpp.Field{{Value: "Main St"}, {Value: "Boston"}}},
+					{Components: []*csvpp.Field{{Value: "5th Ave"}, {Value: "New York"}}},
+				}}},
+			},
+		},
+		{
+			name:  "success: invalid name characters are replaced",
+			input: "first name,e-mail\nAlice,alice@example.com\n",
+			wantHeaders: []*csvpp.ColumnHeader{
+				{Name: "first_name", Kind: csvpp.SimpleField, ArrayDelimiter: '~', ComponentDelimiter: '^'},
+				{Name: "e-mail", Kind: csvpp.SimpleField, ArrayDelimiter: '~', ComponentDelimiter: '^'},
+			},
+			wantRecords: [][]*csvpp.Field{
+				{{Value: "Alice"}, {Value: "alice@example.com"}},
+			},
+		},
+		{
+			name:   "success: schema with plain joined column",
+			input:  "name,tags,geo.lat,geo.lon,extra\nAlice,go|rust,35.6,139.7,ignored\n",
+			schema: "name,tags[|],geo(lat^lon)",
+			wantHeaders: []*csvpp.ColumnHeader{
+				{Name: "name", Kind: csvpp.SimpleField},
+				{Name: "tags", Kind: csvpp.ArrayField, ArrayDelimiter: '|'},
+				{Name: "geo", Kind: csvpp.StructuredField, ComponentDelimiter: '^', Components: []*csvpp.ColumnHeader{
+					{Name: "lat", Kind: csvpp.SimpleField},
+					{Name: "lon", Kind: csvpp.SimpleField},
+				}},
+			},
+			wantRecords: [][]*csvpp.Field{
+				{{Value: "Alice"}, {Values: []string{"go", "rust"}}, {Components: []*csvpp.Field{{Value: "35.6"}, {Value: "139.7"}}}},
+			},
+		},
+		{
+			name:   "success: schema with indexed array-structured columns",
+			input:  "address[0].street,address[0].city,address[1].street,address[1].city\nMain St,Boston,,\n",
+			schema: "address[](street^city)",
+			wantHeaders: []*csvpp.ColumnHeader{
+				{Name: "address", Kind: csvpp.ArrayStructuredField, ArrayDelimiter: '~', ComponentDelimiter: '^', Components: []*csvpp.ColumnHeader{
+					{Name: "street", Kind: csvpp.SimpleField},
+					{Name: "city", Kind: csvpp.SimpleField},
+				}},
+			},
+			wantRecords: [][]*csvpp.Field{
+				{{Components: []*csvpp.Field{
+					{Components: []*csvpp.Field{{Value: "Main St"}, {Value: "Boston"}}},
+				}}},
+			},
+		},
+		{
+			name:        "success: empty input",
+			input:       "",
+			wantHeaders: nil,
+			wantRecords: nil,
+		},
+		{
+			name:    "error: duplicate column",
+			input:   "name,name\nAlice,Bob\n",
+			wantErr: true,
+		},
+		{
+			name:    "error: inconsistent number of fields",
+			input:   "name,age\nAlice\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var schema []*csvpp.ColumnHeader
+			if tt.schema != "" {
+				var err error
+				schema, err = csvpp.NewReader(strings.NewReader(tt.schema)).Headers()
+				if err != nil {
+					t.Fatalf("Headers() error = %v", err)
+				}
+			}
+
+			headers, records, err := converter.FromCSV(strings.NewReader(tt.input), schema)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error but got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+
+			if diff := cmp.Diff(tt.wantHeaders, headers); diff != "" {
+				t.Errorf("headers mismatch (-want +got):\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tt.wantRecords, records); diff != "" {
+				t.Errorf("records mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
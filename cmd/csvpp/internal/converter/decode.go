@@ -6,28 +6,32 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"slices"
+	"strings"
 
 	"github.com/goccy/go-yaml"
 
 	"github.com/osamingo/go-csvpp"
 )
 
-// keyOrderInfo holds the ordered keys extracted from the first record.
+// keyOrderInfo holds object keys in order of first appearance, and the key
+// order of nested objects and arrays of objects by key.
 type keyOrderInfo struct {
 	keys   []string
 	nested map[string]*keyOrderInfo
 }
 
 // FromJSON reads JSON array and converts to CSVPP headers and records.
-// The JSON must be an array of objects with consistent keys.
-func FromJSON(r io.Reader) ([]*csvpp.ColumnHeader, [][]*csvpp.Field, error) {
+// Headers are inferred from all records; only WithWarnings applies, and
+// values the headers cannot represent are reported to it.
+func FromJSON(r io.Reader, opts ...DecoderOption) ([]*csvpp.ColumnHeader, [][]*csvpp.Field, error) {
 	data, err := io.ReadAll(r)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to read input: %w", err)
 	}
 
 	var records []map[string]any
-	if err := json.Unmarshal(data, &records); err != nil {
+	if err := decodeJSON(data, &records); err != nil {
 		return nil, nil, fmt.Errorf("failed to decode JSON: %w", err)
 	}
 
@@ -41,14 +45,15 @@ func FromJSON(r io.Reader) ([]*csvpp.ColumnHeader, [][]*csvpp.Field, error) {
 	}
 
 	headers := inferHeaders(records, order)
-	fields := convertRecords(headers, records)
+	fields := convertRecords(headers, records, newDecoderConfig(opts).warn)
 
 	return headers, fields, nil
 }
 
 // FromYAML reads YAML array and converts to CSVPP headers and records.
-// The YAML must be an array of objects with consistent keys.
-func FromYAML(r io.Reader) ([]*csvpp.ColumnHeader, [][]*csvpp.Field, error) {
+// Headers are inferred from all records; only WithWarnings applies, and
+// values the headers cannot represent are reported to it.
+func FromYAML(r io.Reader, opts ...DecoderOption) ([]*csvpp.ColumnHeader, [][]*csvpp.Field, error) {
 	data, err := io.ReadAll(r)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to read input: %w", err)
@@ -69,21 +74,35 @@ func FromYAML(r io.Reader) ([]*csvpp.ColumnHeader, [][]*csvpp.Field, error) {
 	}
 
 	headers := inferHeaders(records, order)
-	fields := convertRecords(headers, records)
+	fields := convertRecords(headers, records, newDecoderConfig(opts).warn)
 
 	return headers, fields, nil
 }
 
-// extractJSONKeyOrder extracts key order from the first record in a JSON array.
+// decodeJSON decodes data into v, keeping numbers as json.Number so that
+// their text is preserved exactly.
+func decodeJSON(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+// extractJSONKeyOrder extracts the merged key order of all records in a JSON array.
 func extractJSONKeyOrder(data []byte) (*keyOrderInfo, error) {
 	var raw []json.RawMessage
 	if err := json.Unmarshal(data, &raw); err != nil {
 		return nil, err
 	}
-	if len(raw) == 0 {
-		return &keyOrderInfo{nested: make(map[string]*keyOrderInfo)}, nil
+
+	order := &keyOrderInfo{nested: make(map[string]*keyOrderInfo)}
+	for _, r := range raw {
+		o, err := readJSONObjectOrder(json.NewDecoder(bytes.NewReader(r)))
+		if err != nil {
+			return nil, err
+		}
+		order = mergeKeyOrder(order, o)
 	}
-	return readJSONObjectOrder(json.NewDecoder(bytes.NewReader(raw[0])))
+	return order, nil
 }
 
 // readJSONObjectOrder reads one JSON object from a decoder and extracts ordered keys.
@@ -95,167 +114,79 @@ func readJSONObjectOrder(dec *json.Decoder) (*keyOrderInfo, error) {
 	if d, ok := t.(json.Delim); !ok || d != '{' {
 		return nil, fmt.Errorf("expected '{', got %v", t)
 	}
-
-	info := &keyOrderInfo{nested: make(map[string]*keyOrderInfo)}
-
-	for dec.More() {
-		t, err = dec.Token()
-		if err != nil {
-			return nil, err
-		}
-		key, ok := t.(string)
-		if !ok {
-			return nil, fmt.Errorf("expected string key, got %T", t)
-		}
-		info.keys = append(info.keys, key)
-
-		nested, err := readJSONValueOrder(dec)
-		if err != nil {
-			return nil, err
-		}
-		if nested != nil {
-			info.nested[key] = nested
-		}
-	}
-
-	// consume closing '}'
-	_, err = dec.Token()
-	return info, err
+	return readJSONDelimOrder(dec, '{')
 }
 
-// readJSONValueOrder reads one JSON value, extracting key order if it's an object or array of objects.
+// readJSONValueOrder reads one JSON value and returns the key order of objects
+// in it, or nil for scalars.
 func readJSONValueOrder(dec *json.Decoder) (*keyOrderInfo, error) {
 	t, err := dec.Token()
 	if err != nil {
 		return nil, err
 	}
-
 	d, ok := t.(json.Delim)
 	if !ok {
 		return nil, nil // scalar value
 	}
+	return readJSONDelimOrder(dec, d)
+}
+
+// readJSONDelimOrder reads the rest of an object or array after its opening
+// delimiter. Objects return their keys and the order of their values; arrays
+// return the merged order of their elements.
+func readJSONDelimOrder(dec *json.Decoder, open json.Delim) (*keyOrderInfo, error) {
+	var info *keyOrderInfo
+	if open == '{' {
+		info = &keyOrderInfo{nested: make(map[string]*keyOrderInfo)}
+	}
 
-	switch d {
-	case '{':
-		// Object value - extract component keys
-		info := &keyOrderInfo{nested: make(map[string]*keyOrderInfo)}
-		for dec.More() {
-			t, err = dec.Token()
+	for dec.More() {
+		if open == '[' {
+			o, err := readJSONValueOrder(dec)
 			if err != nil {
 				return nil, err
 			}
-			key, ok := t.(string)
-			if !ok {
-				return nil, fmt.Errorf("expected string key, got %T", t)
-			}
-			info.keys = append(info.keys, key)
-			if err := skipJSONValue(dec); err != nil {
-				return nil, err
-			}
+			info = mergeKeyOrder(info, o)
+			continue
 		}
-		_, err = dec.Token() // '}'
-		return info, err
 
-	case '[':
-		// Array value - check if first element is an object
-		if !dec.More() {
-			_, err = dec.Token() // ']'
-			return nil, err
-		}
-
-		t, err = dec.Token()
+		t, err := dec.Token()
 		if err != nil {
 			return nil, err
 		}
-
-		if d2, ok := t.(json.Delim); ok && d2 == '{' {
-			// Array of objects - extract keys from the first object
-			info := &keyOrderInfo{nested: make(map[string]*keyOrderInfo)}
-			for dec.More() {
-				t, err = dec.Token()
-				if err != nil {
-					return nil, err
-				}
-				key, ok := t.(string)
-				if !ok {
-					return nil, fmt.Errorf("expected string key, got %T", t)
-				}
-				info.keys = append(info.keys, key)
-				if err := skipJSONValue(dec); err != nil {
-					return nil, err
-				}
-			}
-			_, err = dec.Token() // closing '}' of first object
-			if err != nil {
-				return nil, err
-			}
-			// Skip remaining array elements
-			for dec.More() {
-				if err := skipJSONValue(dec); err != nil {
-					return nil, err
-				}
-			}
-			_, err = dec.Token() // ']'
-			return info, err
-		}
-
-		// First element is not an object - skip the rest
-		if d2, ok := t.(json.Delim); ok {
-			if err := skipJSONDelimContent(dec, d2); err != nil {
-				return nil, err
-			}
+		key, ok := t.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected string key, got %T", t)
 		}
-		for dec.More() {
-			if err := skipJSONValue(dec); err != nil {
-				return nil, err
-			}
+		if !slices.Contains(info.keys, key) {
+			info.keys = append(info.keys, key)
 		}
-		_, err = dec.Token() // ']'
-		return nil, err
-	}
-
-	return nil, nil
-}
-
-// skipJSONValue reads and discards one complete JSON value.
-func skipJSONValue(dec *json.Decoder) error {
-	t, err := dec.Token()
-	if err != nil {
-		return err
-	}
-	d, ok := t.(json.Delim)
-	if !ok {
-		return nil // scalar
-	}
-	return skipJSONDelimContent(dec, d)
-}
-
-// skipJSONDelimContent skips content after an opening delimiter ('{' or '[') was consumed.
-func skipJSONDelimContent(dec *json.Decoder, open json.Delim) error {
-	for dec.More() {
-		if open == '{' {
-			if _, err := dec.Token(); err != nil { // skip key
-				return err
-			}
+		nested, err := readJSONValueOrder(dec)
+		if err != nil {
+			return nil, err
 		}
-		if err := skipJSONValue(dec); err != nil {
-			return err
+		if nested != nil {
+			info.nested[key] = mergeKeyOrder(info.nested[key], nested)
 		}
 	}
-	_, err := dec.Token() // closing delimiter
-	return err
+
+	// consume the closing delimiter
+	_, err := dec.Token()
+	return info, err
 }
 
-// extractYAMLKeyOrder extracts key order from the first record in a YAML sequence.
+// extractYAMLKeyOrder extracts the merged key order of all records in a YAML sequence.
 func extractYAMLKeyOrder(data []byte) (*keyOrderInfo, error) {
 	var records []yaml.MapSlice
 	if err := yaml.Unmarshal(data, &records); err != nil {
 		return nil, err
 	}
-	if len(records) == 0 {
-		return &keyOrderInfo{nested: make(map[string]*keyOrderInfo)}, nil
+
+	order := &keyOrderInfo{nested: make(map[string]*keyOrderInfo)}
+	for _, record := range records {
+		order = mergeKeyOrder(order, buildYAMLKeyOrder(record))
 	}
-	return buildYAMLKeyOrder(records[0]), nil
+	return order, nil
 }
 
 // buildYAMLKeyOrder builds keyOrderInfo from a yaml.MapSlice.
@@ -263,210 +194,192 @@ func buildYAMLKeyOrder(ms yaml.MapSlice) *keyOrderInfo {
 	info := &keyOrderInfo{nested: make(map[string]*keyOrderInfo)}
 	for _, item := range ms {
 		key := fmt.Sprintf("%v", item.Key)
-		info.keys = append(info.keys, key)
-
-		switch v := item.Value.(type) {
-		case yaml.MapSlice:
-			info.nested[key] = buildYAMLKeyOrder(v)
-		case []any:
-			if len(v) > 0 {
-				if ms2, ok := v[0].(yaml.MapSlice); ok {
-					info.nested[key] = buildYAMLKeyOrder(ms2)
-				}
-			}
+		if !slices.Contains(info.keys, key) {
+			info.keys = append(info.keys, key)
+		}
+		if nested := yamlValueOrder(item.Value); nested != nil {
+			info.nested[key] = mergeKeyOrder(info.nested[key], nested)
 		}
 	}
 	return info
 }
 
-// inferHeaders infers CSVPP headers from data using the provided key order.
-//
-// Header inference rules:
-//   - string → SimpleField
-//   - []string → ArrayField
-//   - map[string]any → StructuredField
-//   - []map[string]any → ArrayStructuredField
-func inferHeaders(data []map[string]any, order *keyOrderInfo) []*csvpp.ColumnHeader {
-	if len(data) == 0 {
+// yamlValueOrder returns the key order of a YAML mapping, or the merged order
+// of the mappings in a sequence, or nil for scalars.
+func yamlValueOrder(v any) *keyOrderInfo {
+	switch val := v.(type) {
+	case yaml.MapSlice:
+		return buildYAMLKeyOrder(val)
+	case []any:
+		var order *keyOrderInfo
+		for _, elem := range val {
+			order = mergeKeyOrder(order, yamlValueOrder(elem))
+		}
+		return order
+	default:
 		return nil
 	}
+}
 
-	keyTypes := make(map[string]csvpp.FieldKind)
-	keyComponents := make(map[string][]*csvpp.ColumnHeader)
-
-	// Analyze all records to determine consistent types
+// convertRecords converts data records to CSVPP fields, reporting values the
+// headers cannot represent to warn if it is non-nil.
+func convertRecords(headers []*csvpp.ColumnHeader, data []map[string]any, warn func(Warning)) [][]*csvpp.Field {
+	c := &recordConverter{headers: headers, warn: warn}
+	records := make([][]*csvpp.Field, 0, len(data))
 	for _, record := range data {
-		for key, value := range record {
-			kind, components := inferFieldKind(value)
-			if existing, ok := keyTypes[key]; ok {
-				// Keep the more complex type if there's a mismatch
-				if kind > existing {
-					keyTypes[key] = kind
-					keyComponents[key] = components
-				}
-			} else {
-				keyTypes[key] = kind
-				keyComponents[key] = components
-			}
-		}
-	}
-
-	// Build headers using preserved key order
-	headers := make([]*csvpp.ColumnHeader, 0, len(order.keys))
-	for _, key := range order.keys {
-		components := keyComponents[key]
-		if nestedOrder, ok := order.nested[key]; ok && len(components) > 0 {
-			components = reorderComponents(components, nestedOrder)
-		}
-
-		header := &csvpp.ColumnHeader{
-			Name:               key,
-			Kind:               keyTypes[key],
-			ArrayDelimiter:     csvpp.DefaultArrayDelimiter,
-			ComponentDelimiter: csvpp.DefaultComponentDelimiter,
-			Components:         components,
-		}
-		headers = append(headers, header)
+		records = append(records, c.convert(record))
 	}
-
-	return headers
+	return records
 }
 
-// reorderComponents reorders component headers according to the key order info.
-func reorderComponents(components []*csvpp.ColumnHeader, order *keyOrderInfo) []*csvpp.ColumnHeader {
-	compMap := make(map[string]*csvpp.ColumnHeader, len(components))
-	for _, c := range components {
-		compMap[c.Name] = c
-	}
-
-	ordered := make([]*csvpp.ColumnHeader, 0, len(order.keys))
-	for _, key := range order.keys {
-		if c, ok := compMap[key]; ok {
-			ordered = append(ordered, c)
-		}
-	}
-	return ordered
+// recordConverter converts decoded records to CSVPP fields one at a time.
+type recordConverter struct {
+	headers []*csvpp.ColumnHeader
+	warn    func(Warning)
+	unknown bool // report keys that are not in the headers
+	count   int  // records converted so far
 }
 
-// inferFieldKind determines the FieldKind from a value.
-func inferFieldKind(value any) (csvpp.FieldKind, []*csvpp.ColumnHeader) {
-	switch v := value.(type) {
-	case []any:
-		if len(v) == 0 {
-			return csvpp.ArrayField, nil
-		}
-		// Check first element to determine if it's array of strings or array of objects
-		switch elem := v[0].(type) {
-		case map[string]any:
-			// ArrayStructuredField
-			components := inferComponentHeaders(elem)
-			return csvpp.ArrayStructuredField, components
-		default:
-			// ArrayField
-			return csvpp.ArrayField, nil
-		}
-	case map[string]any:
-		// StructuredField
-		components := inferComponentHeaders(v)
-		return csvpp.StructuredField, components
-	default:
-		// SimpleField
-		return csvpp.SimpleField, nil
-	}
+// convert converts the next record.
+func (c *recordConverter) convert(record map[string]any) []*csvpp.Field {
+	c.count++
+	return c.fields(c.headers, record, "", nil)
 }
 
-// inferComponentHeaders creates headers for structured field components.
-// Note: The returned order may be non-deterministic (map iteration).
-// Callers should use reorderComponents to fix the order.
-func inferComponentHeaders(m map[string]any) []*csvpp.ColumnHeader {
-	headers := make([]*csvpp.ColumnHeader, 0, len(m))
-	for key, value := range m {
-		kind, components := inferFieldKind(value)
-		header := &csvpp.ColumnHeader{
-			Name:               key,
-			Kind:               kind,
-			ArrayDelimiter:     csvpp.DefaultArrayDelimiter,
-			ComponentDelimiter: csvpp.DefaultComponentDelimiter,
-			Components:         components,
-		}
-		headers = append(headers, header)
+// fields converts the values of an object to fields of headers. prefix is the
+// path of the object and active holds the delimiters of the enclosing fields.
+func (c *recordConverter) fields(headers []*csvpp.ColumnHeader, m map[string]any, prefix string, active []rune) []*csvpp.Field {
+	fields := make([]*csvpp.Field, len(headers))
+	for i, h := range headers {
+		fields[i] = c.value(h, m[h.Name], joinPath(prefix, h.Name), active)
 	}
-	return headers
-}
-
-// convertRecords converts data records to CSVPP fields.
-func convertRecords(headers []*csvpp.ColumnHeader, data []map[string]any) [][]*csvpp.Field {
-	records := make([][]*csvpp.Field, 0, len(data))
 
-	for _, record := range data {
-		fields := make([]*csvpp.Field, len(headers))
-		for i, header := range headers {
-			value := record[header.Name]
-			fields[i] = convertValue(header, value)
+	if c.unknown && c.warn != nil {
+		keys := make([]string, 0, len(m))
+		for key, v := range m {
+			if v != nil && !slices.ContainsFunc(headers, func(h *csvpp.ColumnHeader) bool { return h.Name == key }) {
+				keys = append(keys, key)
+			}
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			c.report(joinPath(prefix, key), "key not in headers, value dropped")
 		}
-		records = append(records, fields)
 	}
-
-	return records
+	return fields
 }
 
-// convertValue converts a single value to a CSVPP Field.
-func convertValue(header *csvpp.ColumnHeader, value any) *csvpp.Field {
+// value converts a single value to a field of header h.
+func (c *recordConverter) value(h *csvpp.ColumnHeader, value any, path string, active []rune) *csvpp.Field {
 	if value == nil {
 		return &csvpp.Field{}
 	}
 
-	switch header.Kind {
+	switch h.Kind {
 	case csvpp.SimpleField:
-		return &csvpp.Field{Value: toString(value)}
+		return &csvpp.Field{Value: c.scalar(value, path, active)}
 
 	case csvpp.ArrayField:
+		active = append(slices.Clip(active), h.ArrayDelimiter)
 		arr, ok := value.([]any)
 		if !ok {
-			return &csvpp.Field{Values: []string{toString(value)}}
+			return &csvpp.Field{Values: []string{c.scalar(value, path, active)}}
 		}
 		values := make([]string, len(arr))
 		for i, v := range arr {
-			values[i] = toString(v)
+			values[i] = c.scalar(v, path, active)
 		}
 		return &csvpp.Field{Values: values}
 
 	case csvpp.StructuredField:
 		m, ok := value.(map[string]any)
 		if !ok {
+			c.report(path, fmt.Sprintf("%s dropped, expected an object", describe(value)))
 			return &csvpp.Field{}
 		}
-		components := make([]*csvpp.Field, len(header.Components))
-		for i, compHeader := range header.Components {
-			compValue := m[compHeader.Name]
-			components[i] = convertValue(compHeader, compValue)
-		}
-		return &csvpp.Field{Components: components}
+		active = append(slices.Clip(active), h.ComponentDelimiter)
+		return &csvpp.Field{Components: c.fields(h.Components, m, path, active)}
 
 	case csvpp.ArrayStructuredField:
-		arr, ok := value.([]any)
-		if !ok {
+		var arr []any
+		switch v := value.(type) {
+		case []any:
+			arr = v
+		case map[string]any:
+			arr = []any{v}
+		default:
+			c.report(path, fmt.Sprintf("%s dropped, expected an array of objects", describe(value)))
 			return &csvpp.Field{Components: []*csvpp.Field{}}
 		}
+		active = append(slices.Clip(active), h.ArrayDelimiter, h.ComponentDelimiter)
 		components := make([]*csvpp.Field, len(arr))
 		for i, elem := range arr {
 			m, ok := elem.(map[string]any)
 			if !ok {
+				if elem != nil {
+					c.report(path, fmt.Sprintf("element %d: %s dropped, expected an object", i+1, describe(elem)))
+				}
 				components[i] = &csvpp.Field{}
 				continue
 			}
-			compFields := make([]*csvpp.Field, len(header.Components))
-			for j, compHeader := range header.Components {
-				compValue := m[compHeader.Name]
-				compFields[j] = convertValue(compHeader, compValue)
-			}
-			components[i] = &csvpp.Field{Components: compFields}
+			components[i] = &csvpp.Field{Components: c.fields(h.Components, m, path, active)}
 		}
 		return &csvpp.Field{Components: components}
 
 	default:
-		return &csvpp.Field{Value: toString(value)}
+		return &csvpp.Field{Value: c.scalar(value, path, active)}
+	}
+}
+
+// scalar converts a value to a string, reporting objects and arrays, which are
+// written as text, and values containing an active delimiter, which cannot be
+// read back.
+func (c *recordConverter) scalar(value any, path string, active []rune) string {
+	switch v := value.(type) {
+	case map[string]any:
+		if len(v) == 0 {
+			return ""
+		}
+		c.report(path, "object written as text")
+	case []any:
+		c.report(path, "nested array written as text")
+	}
+
+	s := toString(value)
+	for _, d := range active {
+		if strings.ContainsRune(s, d) {
+			c.report(path, fmt.Sprintf("value %q contains delimiter %q", s, d))
+			break
+		}
+	}
+	return s
+}
+
+// report passes a warning about the current record to c.warn.
+func (c *recordConverter) report(path, msg string) {
+	if c.warn != nil {
+		c.warn(Warning{Record: c.count, Field: path, Message: msg})
+	}
+}
+
+// describe names the type of a decoded value for warnings.
+func describe(v any) string {
+	switch v.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	default:
+		return fmt.Sprintf("value %q", toString(v))
+	}
+}
+
+// joinPath appends name to the dotted field path prefix.
+func joinPath(prefix, name string) string {
+	if prefix == "" {
+		return name
 	}
+	return prefix + "." + name
 }
 
 // toString converts any value to string.
@@ -477,6 +390,8 @@ func toString(v any) string {
 	switch val := v.(type) {
 	case string:
 		return val
+	case json.Number:
+		return string(val)
 	case float64:
 		// JSON numbers are decoded as float64
 		if val == float64(int64(val)) {
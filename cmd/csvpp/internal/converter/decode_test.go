@@ -169,8 +169,8 @@ func TestFromJSONStructuredField(t *testing.T) {
 		{
 			Name: "geo", Kind: csvpp.StructuredField, ArrayDelimiter: '~', ComponentDelimiter: '^',
 			Components: []*csvpp.ColumnHeader{
-				{Name: "lat", Kind: csvpp.SimpleField, ArrayDelimiter: '~', ComponentDelimiter: '^'},
-				{Name: "lon", Kind: csvpp.SimpleField, ArrayDelimiter: '~', ComponentDelimiter: '^'},
+				{Name: "lat", Kind: csvpp.SimpleField, ArrayDelimiter: ';', ComponentDelimiter: ':'},
+				{Name: "lon", Kind: csvpp.SimpleField, ArrayDelimiter: ';', ComponentDelimiter: ':'},
 			},
 		},
 	}
@@ -206,8 +206,8 @@ func TestFromJSONArrayStructuredField(t *testing.T) {
 		{
 			Name: "addresses", Kind: csvpp.ArrayStructuredField, ArrayDelimiter: '~', ComponentDelimiter: '^',
 			Components: []*csvpp.ColumnHeader{
-				{Name: "street", Kind: csvpp.SimpleField, ArrayDelimiter: '~', ComponentDelimiter: '^'},
-				{Name: "city", Kind: csvpp.SimpleField, ArrayDelimiter: '~', ComponentDelimiter: '^'},
+				{Name: "street", Kind: csvpp.SimpleField, ArrayDelimiter: ';', ComponentDelimiter: ':'},
+				{Name: "city", Kind: csvpp.SimpleField, ArrayDelimiter: ';', ComponentDelimiter: ':'},
 			},
 		},
 	}
@@ -255,6 +255,16 @@ func TestToString(t *testing.T) {
 			input: `[{"value":true}]`,
 			want:  "true",
 		},
+		{
+			name:  "success: number text preserved",
+			input: `[{"value":35.60}]`,
+			want:  "35.60",
+		},
+		{
+			name:  "success: large integer preserved",
+			input: `[{"value":12345678901234567890}]`,
+			want:  "12345678901234567890",
+		},
 	}
 
 	for _, tt := range tests {
@@ -285,3 +295,118 @@ func TestToString(t *testing.T) {
 		})
 	}
 }
+
+func TestFromJSONHeterogeneous(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		input        string
+		wantHeader   string
+		wantRecords  [][]*csvpp.Field
+		wantWarnings []string
+	}{
+		{
+			name:       "success: keys merged across records",
+			input:      `[{"name":"Alice"},{"name":"Bob","age":30}]`,
+			wantHeader: "name,age",
+			wantRecords: [][]*csvpp.Field{
+				{{Value: "Alice"}, {}},
+				{{Value: "Bob"}, {Value: "30"}},
+			},
+		},
+		{
+			name:       "success: components merged across array elements",
+			input:      `[{"address":[{"street":"Main"},{"city":"LA","zip":"90001"}]}]`,
+			wantHeader: "address[](street^city^zip)",
+			wantRecords: [][]*csvpp.Field{
+				{{Components: []*csvpp.Field{
+					{Components: []*csvpp.Field{{Value: "Main"}, {}, {}}},
+					{Components: []*csvpp.Field{{}, {Value: "LA"}, {Value: "90001"}}},
+				}}},
+			},
+		},
+		{
+			name:       "success: mixed arrays and objects",
+			input:      `[{"tags":[],"geo":{"lat":"1"}},{"tags":"solo","geo":[{"lat":"2"},{"lon":"3"}]}]`,
+			wantHeader: "tags[],geo[](lat^lon)",
+			wantRecords: [][]*csvpp.Field{
+				{{Values: []string{}}, {Components: []*csvpp.Field{{Components: []*csvpp.Field{{Value: "1"}, {}}}}}},
+				{{Values: []string{"solo"}}, {Components: []*csvpp.Field{
+					{Components: []*csvpp.Field{{Value: "2"}, {}}},
+					{Components: []*csvpp.Field{{}, {Value: "3"}}},
+				}}},
+			},
+		},
+		{
+			name:       "success: nested array in component uses distinct delimiters",
+			input:      `[{"people":[{"name":"Alice","tags":["go","rust"]},{"name":"Bob","tags":["python"]}]}]`,
+			wantHeader: "people[](name^tags[;])",
+			wantRecords: [][]*csvpp.Field{
+				{{Components: []*csvpp.Field{
+					{Components: []*csvpp.Field{{Value: "Alice"}, {Values: []string{"go", "rust"}}}},
+					{Components: []*csvpp.Field{{Value: "Bob"}, {Values: []string{"python"}}}},
+				}}},
+			},
+		},
+		{
+			name:       "success: delimiters absent from values",
+			input:      `[{"tags":["a~b","c"],"geo":{"lat":"1^2","lon":"3"}}]`,
+			wantHeader: "tags[^],geo;(lat;lon)",
+			wantRecords: [][]*csvpp.Field{
+				{{Values: []string{"a~b", "c"}}, {Components: []*csvpp.Field{{Value: "1^2"}, {Value: "3"}}}},
+			},
+		},
+		{
+			name:       "success: lossy values reported",
+			input:      `[{"geo":"unknown","tags":[["a"],"b"]},{"geo":{"lat":"1"},"tags":["c"]}]`,
+			wantHeader: "geo(lat),tags[]",
+			wantRecords: [][]*csvpp.Field{
+				{{}, {Values: []string{"[a]", "b"}}},
+				{{Components: []*csvpp.Field{{Value: "1"}}}, {Values: []string{"c"}}},
+			},
+			wantWarnings: []string{
+				`record 1: geo: value "unknown" dropped, expected an object`,
+				`record 1: tags: nested array written as text`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var warnings []string
+			headers, records, err := converter.FromJSON(strings.NewReader(tt.input), converter.WithWarnings(func(w converter.Warning) {
+				warnings = append(warnings, w.String())
+			}))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.wantHeader, formatHeaders(t, headers)); diff != "" {
+				t.Errorf("headers mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantRecords, records); diff != "" {
+				t.Errorf("records mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantWarnings, warnings); diff != "" {
+				t.Errorf("warnings mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// formatHeaders returns the CSVPP header line for headers.
+func formatHeaders(t *testing.T, headers []*csvpp.ColumnHeader) string {
+	t.Helper()
+
+	var buf strings.Builder
+	w := csvpp.NewWriter(&buf)
+	w.SetHeaders(headers)
+	if err := w.WriteHeader(); err != nil {
+		t.Fatalf("WriteHeader() error = %v", err)
+	}
+	w.Flush()
+	return strings.TrimSuffix(buf.String(), "\n")
+}
@@ -0,0 +1,168 @@
+package converter
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/osamingo/go-csvpp"
+)
+
+// shape accumulates the values observed for one key across all records and
+// array elements.
+type shape struct {
+	scalar bool // strings, numbers and booleans
+	array  bool // arrays
+	object bool // objects with at least one key, or arrays containing them
+
+	fields map[string]*shape // keys of object values
+}
+
+// newShape returns an empty shape.
+func newShape() *shape {
+	return &shape{fields: make(map[string]*shape)}
+}
+
+// add records a value.
+func (s *shape) add(v any) {
+	switch val := v.(type) {
+	case nil:
+	case []any:
+		s.array = true
+		for _, elem := range val {
+			switch e := elem.(type) {
+			case []any:
+				// Nested arrays are written as text; see convertValue.
+				s.scalar = true
+			case map[string]any:
+				s.addObject(e)
+			default:
+				s.addScalar(e)
+			}
+		}
+	case map[string]any:
+		s.addObject(val)
+	default:
+		s.addScalar(val)
+	}
+}
+
+// addObject records the keys and values of an object.
+func (s *shape) addObject(m map[string]any) {
+	if len(m) == 0 {
+		return
+	}
+	s.object = true
+	for key, v := range m {
+		child, ok := s.fields[key]
+		if !ok {
+			child = newShape()
+			s.fields[key] = child
+		}
+		child.add(v)
+	}
+}
+
+// addScalar records a scalar value.
+func (s *shape) addScalar(v any) {
+	if v != nil {
+		s.scalar = true
+	}
+}
+
+// kind returns the field kind that represents every observed value:
+// objects take precedence over scalars, and arrays over single values.
+func (s *shape) kind() csvpp.FieldKind {
+	switch {
+	case s.object && s.array:
+		return csvpp.ArrayStructuredField
+	case s.object:
+		return csvpp.StructuredField
+	case s.array:
+		return csvpp.ArrayField
+	default:
+		return csvpp.SimpleField
+	}
+}
+
+// inferHeaders infers CSVPP headers from data using the provided key order.
+//
+// Header inference rules, applied to the values of each key across all records
+// and, for arrays, all elements:
+//   - scalars only → SimpleField
+//   - arrays of scalars, or a mix of arrays and scalars → ArrayField
+//   - objects → StructuredField, with the keys of all objects as components
+//   - arrays containing objects → ArrayStructuredField
+//
+// Keys missing from order are appended in sorted order. Delimiters are assigned
+// by csvpp.AssignDelimiters, so that each nesting level uses its own pair and
+// no delimiter occurs in the values of its field.
+func inferHeaders(data []map[string]any, order *keyOrderInfo) []*csvpp.ColumnHeader {
+	if len(data) == 0 {
+		return nil
+	}
+
+	root := newShape()
+	for _, record := range data {
+		root.addObject(record)
+	}
+	headers := shapeHeaders(root.fields, order)
+	assigned, err := csvpp.AssignDelimiters(headers, convertRecords(headers, data, nil))
+	if err != nil {
+		// Every candidate occurs in the data. Keep the level delimiters;
+		// values containing them are reported when converted.
+		assigned, _ = csvpp.AssignDelimiters(headers, nil)
+	}
+	return assigned
+}
+
+// shapeHeaders builds headers without delimiters for the fields of an object.
+func shapeHeaders(fields map[string]*shape, order *keyOrderInfo) []*csvpp.ColumnHeader {
+	keys := orderedKeys(fields, order)
+	headers := make([]*csvpp.ColumnHeader, 0, len(keys))
+	for _, key := range keys {
+		s := fields[key]
+		h := &csvpp.ColumnHeader{Name: key, Kind: s.kind()}
+		if h.Kind == csvpp.StructuredField || h.Kind == csvpp.ArrayStructuredField {
+			var nested *keyOrderInfo
+			if order != nil {
+				nested = order.nested[key]
+			}
+			h.Components = shapeHeaders(s.fields, nested)
+		}
+		headers = append(headers, h)
+	}
+	return headers
+}
+
+// orderedKeys returns the keys of fields in the order of order.keys, followed
+// by keys order does not list in sorted order.
+func orderedKeys(fields map[string]*shape, order *keyOrderInfo) []string {
+	keys := make([]string, 0, len(fields))
+	if order != nil {
+		for _, key := range order.keys {
+			if _, ok := fields[key]; ok && !slices.Contains(keys, key) {
+				keys = append(keys, key)
+			}
+		}
+	}
+	var rest []string
+	for key := range fields {
+		if !slices.Contains(keys, key) {
+			rest = append(rest, key)
+		}
+	}
+	slices.Sort(rest)
+	return append(keys, rest...)
+}
+
+// Warning describes a value that could not be converted to CSVPP without loss.
+type Warning struct {
+	Record  int    // 1-based record number
+	Field   string // dotted field path, such as "geo.lat"
+	Message string
+}
+
+// String formats the warning for display.
+func (w Warning) String() string {
+	return fmt.Sprintf("record %d: %s: %s", w.Record, w.Field, w.Message)
+}
@@ -0,0 +1,130 @@
+package converter
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"github.com/vmihailenco/msgpack/v5/msgpcode"
+
+	"github.com/osamingo/go-csvpp"
+)
+
+// FromMsgpack reads MessagePack data and converts it to CSVPP headers and
+// records. The input is either a stream of maps, one per record, or a single
+// array of maps. Key order follows the encoded maps.
+// Only WithWarnings applies.
+func FromMsgpack(r io.Reader, opts ...DecoderOption) ([]*csvpp.ColumnHeader, [][]*csvpp.Field, error) {
+	dec := msgpack.NewDecoder(r)
+
+	var values []any
+	var order *keyOrderInfo
+	for {
+		if _, err := dec.PeekCode(); errors.Is(err, io.EOF) {
+			break
+		}
+		v, o, err := decodeMsgpackValue(dec)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to decode MessagePack: %w", err)
+		}
+		if arr, ok := v.([]any); ok && len(values) == 0 {
+			values, order = arr, o
+			continue
+		}
+		values = append(values, v)
+		order = mergeKeyOrder(order, o)
+	}
+	if len(values) == 0 {
+		return nil, nil, nil
+	}
+
+	records := make([]map[string]any, len(values))
+	for i, v := range values {
+		record, ok := v.(map[string]any)
+		if !ok {
+			return nil, nil, fmt.Errorf("MessagePack record %d is not a map", i+1)
+		}
+		records[i] = record
+	}
+
+	headers := inferHeaders(records, order)
+	fields := convertRecords(headers, records, newDecoderConfig(opts).warn)
+
+	return headers, fields, nil
+}
+
+// decodeMsgpackValue decodes the next value, returning maps as map[string]any
+// along with their key order and binary data as strings.
+func decodeMsgpackValue(dec *msgpack.Decoder) (any, *keyOrderInfo, error) {
+	c, err := dec.PeekCode()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch {
+	case msgpcode.IsFixedMap(c) || c == msgpcode.Map16 || c == msgpcode.Map32:
+		n, err := dec.DecodeMapLen()
+		if err != nil {
+			return nil, nil, err
+		}
+		m := make(map[string]any, n)
+		order := &keyOrderInfo{nested: make(map[string]*keyOrderInfo)}
+		for range n {
+			k, err := dec.DecodeInterface()
+			if err != nil {
+				return nil, nil, err
+			}
+			v, o, err := decodeMsgpackValue(dec)
+			if err != nil {
+				return nil, nil, err
+			}
+			key := toString(normalizeMsgpack(k))
+			if _, ok := m[key]; !ok {
+				order.keys = append(order.keys, key)
+			}
+			m[key] = v
+			if o != nil {
+				order.nested[key] = o
+			}
+		}
+		return m, order, nil
+
+	case msgpcode.IsFixedArray(c) || c == msgpcode.Array16 || c == msgpcode.Array32:
+		n, err := dec.DecodeArrayLen()
+		if err != nil {
+			return nil, nil, err
+		}
+		var order *keyOrderInfo
+		values := make([]any, n)
+		for i := range n {
+			var o *keyOrderInfo
+			if values[i], o, err = decodeMsgpackValue(dec); err != nil {
+				return nil, nil, err
+			}
+			order = mergeKeyOrder(order, o)
+		}
+		return values, order, nil
+
+	default:
+		v, err := dec.DecodeInterface()
+		if err != nil {
+			return nil, nil, err
+		}
+		return normalizeMsgpack(v), nil, nil
+	}
+}
+
+// normalizeMsgpack converts binary data to a string so it is not formatted as
+// a byte list, and timestamps to RFC 3339.
+func normalizeMsgpack(v any) any {
+	switch val := v.(type) {
+	case []byte:
+		return string(val)
+	case time.Time:
+		return val.Format(time.RFC3339Nano)
+	default:
+		return v
+	}
+}
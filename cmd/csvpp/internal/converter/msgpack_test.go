@@ -0,0 +1,129 @@
+package converter_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/vmihailenco/msgpack/v5"
+
+	"github.com/osamingo/go-csvpp"
+	"github.com/osamingo/go-csvpp/cmd/csvpp/internal/converter"
+)
+
+// encodeMsgpackMap encodes kv as a MessagePack map of string keys and values, in order.
+func encodeMsgpackMap(t *testing.T, enc *msgpack.Encoder, kv ...string) {
+	t.Helper()
+
+	if err := enc.EncodeMapLen(len(kv) / 2); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range kv {
+		if err := enc.EncodeString(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestFromMsgpack(t *testing.T) {
+	t.Parallel()
+
+	wantHeaders := []*csvpp.ColumnHeader{
+		{Name: "name", Kind: csvpp.SimpleField, ArrayDelimiter: '~', ComponentDelimiter: '^'},
+		{Name: "age", Kind: csvpp.SimpleField, ArrayDelimiter: '~', ComponentDelimiter: '^'},
+		{Name: "city", Kind: csvpp.SimpleField, ArrayDelimiter: '~', ComponentDelimiter: '^'},
+	}
+	wantRecords := [][]*csvpp.Field{
+		{{Value: "Alice"}, {Value: "30"}, {}},
+		{{Value: "Bob"}, {}, {Value: "Tokyo"}},
+	}
+
+	tests := []struct {
+		name        string
+		encode      func(t *testing.T, enc *msgpack.Encoder)
+		wantHeaders []*csvpp.ColumnHeader
+		wantRecords [][]*csvpp.Field
+		wantErr     bool
+	}{
+		{
+			name: "success: stream of maps",
+			encode: func(t *testing.T, enc *msgpack.Encoder) {
+				encodeMsgpackMap(t, enc, "name", "Alice", "age", "30")
+				encodeMsgpackMap(t, enc, "name", "Bob", "city", "Tokyo")
+			},
+			wantHeaders: wantHeaders,
+			wantRecords: wantRecords,
+		},
+		{
+			name: "success: array of maps",
+			encode: func(t *testing.T, enc *msgpack.Encoder) {
+				if err := enc.EncodeArrayLen(2); err != nil {
+					t.Fatal(err)
+				}
+				encodeMsgpackMap(t, enc, "name", "Alice", "age", "30")
+				encodeMsgpackMap(t, enc, "name", "Bob", "city", "Tokyo")
+			},
+			wantHeaders: wantHeaders,
+			wantRecords: wantRecords,
+		},
+		{
+			name: "success: nested values",
+			encode: func(t *testing.T, enc *msgpack.Encoder) {
+				enc.SetSortMapKeys(true)
+				err := enc.Encode(map[string]any{
+					"tags": []string{"go", "rust"},
+					"geo":  map[string]any{"lat": 35.5},
+					"n":    int64(7),
+					"raw":  []byte("bin"),
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantHeaders: []*csvpp.ColumnHeader{
+				{Name: "geo", Kind: csvpp.StructuredField, ArrayDelimiter: '~', ComponentDelimiter: '^', Components: []*csvpp.ColumnHeader{
+					{Name: "lat", Kind: csvpp.SimpleField, ArrayDelimiter: ';', ComponentDelimiter: ':'},
+				}},
+				{Name: "n", Kind: csvpp.SimpleField, ArrayDelimiter: '~', ComponentDelimiter: '^'},
+				{Name: "raw", Kind: csvpp.SimpleField, ArrayDelimiter: '~', ComponentDelimiter: '^'},
+				{Name: "tags", Kind: csvpp.ArrayField, ArrayDelimiter: '~', ComponentDelimiter: '^'},
+			},
+			wantRecords: [][]*csvpp.Field{
+				{{Components: []*csvpp.Field{{Value: "35.5"}}}, {Value: "7"}, {Value: "bin"}, {Values: []string{"go", "rust"}}},
+			},
+		},
+		{
+			name:   "success: empty input",
+			encode: func(*testing.T, *msgpack.Encoder) {},
+		},
+		{
+			name: "error: record is not a map",
+			encode: func(t *testing.T, enc *msgpack.Encoder) {
+				if err := enc.EncodeString("Alice"); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			tt.encode(t, msgpack.NewEncoder(&buf))
+
+			headers, records, err := converter.FromMsgpack(&buf)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FromMsgpack() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.wantHeaders, headers); diff != "" {
+				t.Errorf("headers mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantRecords, records); diff != "" {
+				t.Errorf("records mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package converter
+
+import (
+	"encoding/json/jsontext"
+	"io"
+)
+
+// NDJSONDecoder reads newline-delimited JSON (JSON Lines) objects and converts
+// them to CSVPP records one at a time, without buffering the whole input.
+//
+// Headers are inferred from the sampled objects (see WithSampleSize) unless
+// supplied with WithHeaders; keys that first appear after the sample are ignored,
+// and missing keys produce empty fields.
+type NDJSONDecoder struct {
+	*streamDecoder
+}
+
+// NewNDJSONDecoder creates a new NDJSONDecoder that reads from r.
+func NewNDJSONDecoder(r io.Reader, opts ...DecoderOption) *NDJSONDecoder {
+	dec := jsontext.NewDecoder(r)
+	return &NDJSONDecoder{newStreamDecoder("NDJSON", dec.ReadValue, opts)}
+}
@@ -0,0 +1,154 @@
+package converter_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/osamingo/go-csvpp"
+	"github.com/osamingo/go-csvpp/cmd/csvpp/internal/converter"
+)
+
+// recordDecoder is implemented by the streaming decoders.
+type recordDecoder interface {
+	Headers() ([]*csvpp.ColumnHeader, error)
+	Read() ([]*csvpp.Field, error)
+}
+
+// decodeAll reads the headers and every record from dec.
+func decodeAll(dec recordDecoder) ([]*csvpp.ColumnHeader, [][]*csvpp.Field, error) {
+	headers, err := dec.Headers()
+	if err != nil {
+		return nil, nil, err
+	}
+	var records [][]*csvpp.Field
+	for {
+		record, err := dec.Read()
+		if errors.Is(err, io.EOF) {
+			return headers, records, nil
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		records = append(records, record)
+	}
+}
+
+func TestNDJSONDecoder(t *testing.T) {
+	t.Parallel()
+
+	input := `{"name":"Alice","age":30,"tags":["go","rust"]}
+{"name":"Bob","tags":[],"extra":"x"}
+
+{"age":41,"name":"Carol"}
+`
+
+	tests := []struct {
+		name        string
+		input       string
+		opts        []converter.DecoderOption
+		wantHeaders []*csvpp.ColumnHeader
+		wantRecords [][]*csvpp.Field
+		wantErr     bool
+	}{
+		{
+			name:  "success: headers from all sampled objects",
+			input: input,
+			wantHeaders: []*csvpp.ColumnHeader{
+				{Name: "name", Kind: csvpp.SimpleField, ArrayDelimiter: '~', ComponentDelimiter: '^'},
+				{Name: "age", Kind: csvpp.SimpleField, ArrayDelimiter: '~', ComponentDelimiter: '^'},
+				{Name: "tags", Kind: csvpp.ArrayField, ArrayDelimiter: '~', ComponentDelimiter: '^'},
+				{Name: "extra", Kind: csvpp.SimpleField, ArrayDelimiter: '~', ComponentDelimiter: '^'},
+			},
+			wantRecords: [][]*csvpp.Field{
+				{{Value: "Alice"}, {Value: "30"}, {Values: []string{"go", "rust"}}, {}},
+				{{Value: "Bob"}, {}, {Values: []string{}}, {Value: "x"}},
+				{{Value: "Carol"}, {Value: "41"}, {}, {}},
+			},
+		},
+		{
+			name:  "success: keys after the sample are ignored",
+			input: input,
+			opts:  []converter.DecoderOption{converter.WithSampleSize(1)},
+			wantHeaders: []*csvpp.ColumnHeader{
+				{Name: "name", Kind: csvpp.SimpleField, ArrayDelimiter: '~', ComponentDelimiter: '^'},
+				{Name: "age", Kind: csvpp.SimpleField, ArrayDelimiter: '~', ComponentDelimiter: '^'},
+				{Name: "tags", Kind: csvpp.ArrayField, ArrayDelimiter: '~', ComponentDelimiter: '^'},
+			},
+			wantRecords: [][]*csvpp.Field{
+				{{Value: "Alice"}, {Value: "30"}, {Values: []string{"go", "rust"}}},
+				{{Value: "Bob"}, {}, {Values: []string{}}},
+				{{Value: "Carol"}, {Value: "41"}, {}},
+			},
+		},
+		{
+			name:  "success: structured values",
+			input: `{"geo":{"lat":35.6,"lon":139.7},"address":[{"city":"Tokyo"}]}`,
+			wantHeaders: []*csvpp.ColumnHeader{
+				{Name: "geo", Kind: csvpp.StructuredField, ArrayDelimiter: '~', ComponentDelimiter: '^', Components: []*csvpp.ColumnHeader{
+					{Name: "lat", Kind: csvpp.SimpleField, ArrayDelimiter: ';', ComponentDelimiter: ':'},
+					{Name: "lon", Kind: csvpp.SimpleField, ArrayDelimiter: ';', ComponentDelimiter: ':'},
+				}},
+				{Name: "address", Kind: csvpp.ArrayStructuredField, ArrayDelimiter: '~', ComponentDelimiter: '^', Components: []*csvpp.ColumnHeader{
+					{Name: "city", Kind: csvpp.SimpleField, ArrayDelimiter: ';', ComponentDelimiter: ':'},
+				}},
+			},
+			wantRecords: [][]*csvpp.Field{
+				{
+					{Components: []*csvpp.Field{{Value: "35.6"}, {Value: "139.7"}}},
+					{Components: []*csvpp.Field{{Components: []*csvpp.Field{{Value: "Tokyo"}}}}},
+				},
+			},
+		},
+		{
+			name:    "error: not an object",
+			input:   "{\"name\":\"Alice\"}\n[1,2]\n",
+			wantErr: true,
+		},
+		{
+			name:    "error: invalid json after the sample",
+			input:   "{\"name\":\"Alice\"}\n{invalid}\n",
+			opts:    []converter.DecoderOption{converter.WithSampleSize(1)},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			headers, records, err := decodeAll(converter.NewNDJSONDecoder(strings.NewReader(tt.input), tt.opts...))
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error but got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+
+			if diff := cmp.Diff(tt.wantHeaders, headers); diff != "" {
+				t.Errorf("headers mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantRecords, records); diff != "" {
+				t.Errorf("records mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNDJSONDecoder_Empty(t *testing.T) {
+	t.Parallel()
+
+	dec := converter.NewNDJSONDecoder(strings.NewReader("\n"))
+	if _, err := dec.Headers(); !errors.Is(err, io.EOF) {
+		t.Errorf("Headers() error = %v, want %v", err, io.EOF)
+	}
+}
@@ -0,0 +1,221 @@
+package converter
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/json/jsontext"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/osamingo/go-csvpp"
+)
+
+// DefaultSampleSize is the default number of records the streaming decoders
+// read ahead to infer headers.
+const DefaultSampleSize = 1000
+
+// DecoderOption is a functional option for JSONDecoder, NDJSONDecoder and the
+// From functions.
+type DecoderOption func(*decoderConfig)
+
+// decoderConfig holds decoder settings.
+type decoderConfig struct {
+	sampleSize int
+	headers    []*csvpp.ColumnHeader
+	warn       func(Warning)
+}
+
+// newDecoderConfig returns the default settings with opts applied.
+func newDecoderConfig(opts []DecoderOption) decoderConfig {
+	cfg := decoderConfig{sampleSize: DefaultSampleSize}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// WithSampleSize sets the number of records read ahead to infer headers.
+// The sampled records are kept in memory; a size of zero or less samples the
+// whole input. The default is DefaultSampleSize.
+func WithSampleSize(n int) DecoderOption {
+	return func(c *decoderConfig) {
+		c.sampleSize = n
+	}
+}
+
+// WithHeaders sets the headers to convert records with instead of inferring them.
+// Object keys are matched to header names; other keys are ignored.
+func WithHeaders(headers []*csvpp.ColumnHeader) DecoderOption {
+	return func(c *decoderConfig) {
+		c.headers = headers
+	}
+}
+
+// WithWarnings sets a function called for each value that cannot be converted
+// without loss: values whose kind differs from the header's, such as a string
+// where objects were inferred, values containing a delimiter, and keys that are
+// not in the headers inferred from the sample.
+func WithWarnings(fn func(Warning)) DecoderOption {
+	return func(c *decoderConfig) {
+		c.warn = fn
+	}
+}
+
+// JSONDecoder reads a JSON array of objects and converts its elements to CSVPP
+// records one at a time. Only the header sample is held in memory; the remaining
+// elements are decoded at the token level as they are read.
+type JSONDecoder struct {
+	*streamDecoder
+}
+
+// NewJSONDecoder creates a new JSONDecoder that reads from r.
+func NewJSONDecoder(r io.Reader, opts ...DecoderOption) *JSONDecoder {
+	dec := jsontext.NewDecoder(r)
+	started, done := false, false
+	next := func() (jsontext.Value, error) {
+		if done {
+			return nil, io.EOF
+		}
+		if !started {
+			started = true
+			tok, err := dec.ReadToken()
+			if err != nil {
+				return nil, err
+			}
+			if tok.Kind() != '[' {
+				return nil, fmt.Errorf("expected JSON array, got %v", tok.Kind())
+			}
+		}
+		if dec.PeekKind() == ']' {
+			done = true
+			if _, err := dec.ReadToken(); err != nil {
+				return nil, err
+			}
+			return nil, io.EOF
+		}
+		return dec.ReadValue()
+	}
+	return &JSONDecoder{newStreamDecoder("JSON", next, opts)}
+}
+
+// streamDecoder converts a stream of JSON objects to CSVPP records.
+type streamDecoder struct {
+	format string                         // input format name for error messages
+	next   func() (jsontext.Value, error) // returns the next object, or io.EOF
+	cfg    decoderConfig
+	sample []map[string]any // records read ahead by Headers, returned first by Read
+	count  int              // objects read so far
+
+	headers []*csvpp.ColumnHeader
+	conv    *recordConverter
+}
+
+// newStreamDecoder creates a streamDecoder reading objects from next.
+func newStreamDecoder(format string, next func() (jsontext.Value, error), opts []DecoderOption) *streamDecoder {
+	return &streamDecoder{
+		format: format,
+		next:   next,
+		cfg:    newDecoderConfig(opts),
+	}
+}
+
+// Headers returns the headers supplied by WithHeaders, or infers them from the
+// sampled records. Keys are ordered by first appearance across the sample.
+// It returns io.EOF if headers must be inferred and the input has no objects.
+func (d *streamDecoder) Headers() ([]*csvpp.ColumnHeader, error) {
+	if d.headers != nil {
+		return d.headers, nil
+	}
+	if d.cfg.headers != nil {
+		d.headers = d.cfg.headers
+		d.conv = &recordConverter{headers: d.headers, warn: d.cfg.warn}
+		return d.headers, nil
+	}
+
+	var order *keyOrderInfo
+	for d.cfg.sampleSize <= 0 || len(d.sample) < d.cfg.sampleSize {
+		raw, record, err := d.readObject()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		o, err := readJSONObjectOrder(json.NewDecoder(bytes.NewReader(raw)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract JSON key order: %w", err)
+		}
+		order = mergeKeyOrder(order, o)
+		d.sample = append(d.sample, record)
+	}
+	if len(d.sample) == 0 {
+		return nil, io.EOF
+	}
+
+	d.headers = inferHeaders(d.sample, order)
+	d.conv = &recordConverter{headers: d.headers, warn: d.cfg.warn, unknown: true}
+	return d.headers, nil
+}
+
+// Read returns the next record, or io.EOF when the input is exhausted.
+func (d *streamDecoder) Read() ([]*csvpp.Field, error) {
+	if _, err := d.Headers(); err != nil {
+		return nil, err
+	}
+
+	var record map[string]any
+	if len(d.sample) > 0 {
+		record = d.sample[0]
+		d.sample[0] = nil
+		d.sample = d.sample[1:]
+	} else {
+		var err error
+		if _, record, err = d.readObject(); err != nil {
+			return nil, err
+		}
+	}
+	return d.conv.convert(record), nil
+}
+
+// readObject reads and decodes the next JSON object.
+func (d *streamDecoder) readObject() (jsontext.Value, map[string]any, error) {
+	raw, err := d.next()
+	if errors.Is(err, io.EOF) {
+		return nil, nil, io.EOF
+	}
+	d.count++
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decode %s record %d: %w", d.format, d.count, err)
+	}
+	if raw.Kind() != '{' {
+		return nil, nil, fmt.Errorf("%s record %d is not an object", d.format, d.count)
+	}
+
+	var record map[string]any
+	if err := decodeJSON(raw, &record); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode %s record %d: %w", d.format, d.count, err)
+	}
+	return raw, record, nil
+}
+
+// mergeKeyOrder appends the keys of b missing from a, recursively.
+// A nil a returns b.
+func mergeKeyOrder(a, b *keyOrderInfo) *keyOrderInfo {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	for _, key := range b.keys {
+		if !slices.Contains(a.keys, key) {
+			a.keys = append(a.keys, key)
+		}
+		if nested, ok := b.nested[key]; ok {
+			a.nested[key] = mergeKeyOrder(a.nested[key], nested)
+		}
+	}
+	return a
+}
@@ -0,0 +1,149 @@
+package converter_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/osamingo/go-csvpp"
+	"github.com/osamingo/go-csvpp/cmd/csvpp/internal/converter"
+)
+
+func TestJSONDecoder(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		input       string
+		opts        []converter.DecoderOption
+		wantHeaders []*csvpp.ColumnHeader
+		wantRecords [][]*csvpp.Field
+		wantErr     bool
+	}{
+		{
+			name:  "success: array of objects",
+			input: `[{"name":"Alice","tags":["go","rust"]}, {"name":"Bob","tags":["python"]}]`,
+			wantHeaders: []*csvpp.ColumnHeader{
+				{Name: "name", Kind: csvpp.SimpleField, ArrayDelimiter: '~', ComponentDelimiter: '^'},
+				{Name: "tags", Kind: csvpp.ArrayField, ArrayDelimiter: '~', ComponentDelimiter: '^'},
+			},
+			wantRecords: [][]*csvpp.Field{
+				{{Value: "Alice"}, {Values: []string{"go", "rust"}}},
+				{{Value: "Bob"}, {Values: []string{"python"}}},
+			},
+		},
+		{
+			name:  "success: keys merged across the sample",
+			input: `[{"name":"Alice"},{"name":"Bob","tags":["go"]}]`,
+			wantHeaders: []*csvpp.ColumnHeader{
+				{Name: "name", Kind: csvpp.SimpleField, ArrayDelimiter: '~', ComponentDelimiter: '^'},
+				{Name: "tags", Kind: csvpp.ArrayField, ArrayDelimiter: '~', ComponentDelimiter: '^'},
+			},
+			wantRecords: [][]*csvpp.Field{
+				{{Value: "Alice"}, {}},
+				{{Value: "Bob"}, {Values: []string{"go"}}},
+			},
+		},
+		{
+			name:  "success: supplied headers",
+			input: `[{"name":"Alice","age":30,"extra":"x"},{"name":"Bob"}]`,
+			opts: []converter.DecoderOption{converter.WithHeaders([]*csvpp.ColumnHeader{
+				{Name: "age", Kind: csvpp.SimpleField},
+				{Name: "name", Kind: csvpp.SimpleField},
+			})},
+			wantHeaders: []*csvpp.ColumnHeader{
+				{Name: "age", Kind: csvpp.SimpleField},
+				{Name: "name", Kind: csvpp.SimpleField},
+			},
+			wantRecords: [][]*csvpp.Field{
+				{{Value: "30"}, {Value: "Alice"}},
+				{{}, {Value: "Bob"}},
+			},
+		},
+		{
+			name:    "error: not an array",
+			input:   `{"name":"Alice"}`,
+			wantErr: true,
+		},
+		{
+			name:    "error: element is not an object",
+			input:   `[{"name":"Alice"}, "Bob"]`,
+			wantErr: true,
+		},
+		{
+			name:    "error: truncated array",
+			input:   `[{"name":"Alice"},`,
+			opts:    []converter.DecoderOption{converter.WithSampleSize(1)},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			headers, records, err := decodeAll(converter.NewJSONDecoder(strings.NewReader(tt.input), tt.opts...))
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error but got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+
+			if diff := cmp.Diff(tt.wantHeaders, headers); diff != "" {
+				t.Errorf("headers mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantRecords, records); diff != "" {
+				t.Errorf("records mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestJSONDecoder_Empty(t *testing.T) {
+	t.Parallel()
+
+	dec := converter.NewJSONDecoder(strings.NewReader("[]"))
+	if _, err := dec.Headers(); !errors.Is(err, io.EOF) {
+		t.Errorf("Headers() error = %v, want %v", err, io.EOF)
+	}
+}
+
+func TestJSONDecoder_Warnings(t *testing.T) {
+	t.Parallel()
+
+	input := `[{"name":"Alice","geo":{"lat":"1"}},{"name":"Bob","geo":{"lat":"2","lon":"3"},"age":30},{"name":"C~D","geo":"x"}]`
+
+	var warnings []string
+	dec := converter.NewJSONDecoder(strings.NewReader(input),
+		converter.WithSampleSize(1),
+		converter.WithWarnings(func(w converter.Warning) {
+			warnings = append(warnings, w.String())
+		}),
+	)
+	_, records, err := decodeAll(dec)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("got %d records, want 3", len(records))
+	}
+
+	want := []string{
+		`record 2: geo.lon: key not in headers, value dropped`,
+		`record 2: age: key not in headers, value dropped`,
+		`record 3: geo: value "x" dropped, expected an object`,
+	}
+	if diff := cmp.Diff(want, warnings); diff != "" {
+		t.Errorf("warnings mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,102 @@
+package converter
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/osamingo/go-csvpp"
+)
+
+// FromTOML reads a TOML document and converts its first array of tables to
+// CSVPP headers and records. Key order follows the document; keys missing from
+// a table produce empty fields.
+// Only WithWarnings applies.
+func FromTOML(r io.Reader, opts ...DecoderOption) ([]*csvpp.ColumnHeader, [][]*csvpp.Field, error) {
+	var doc map[string]any
+	md, err := toml.NewDecoder(r).Decode(&doc)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decode TOML: %w", err)
+	}
+
+	var (
+		table string
+		rows  []map[string]any
+	)
+	for _, key := range md.Keys() {
+		if len(key) != 1 {
+			continue
+		}
+		if v, ok := doc[key[0]].([]map[string]any); ok {
+			table, rows = key[0], v
+			break
+		}
+	}
+	if table == "" {
+		if len(doc) == 0 {
+			return nil, nil, nil
+		}
+		return nil, nil, errors.New("failed to decode TOML: no array of tables found")
+	}
+	if len(rows) == 0 {
+		return nil, nil, nil
+	}
+
+	order := &keyOrderInfo{nested: make(map[string]*keyOrderInfo)}
+	for _, key := range md.Keys() {
+		if len(key) > 1 && key[0] == table {
+			addKeyPath(order, key[1:])
+		}
+	}
+
+	records := make([]map[string]any, len(rows))
+	for i, row := range rows {
+		records[i] = normalizeTOML(row).(map[string]any)
+	}
+
+	headers := inferHeaders(records, order)
+	fields := convertRecords(headers, records, newDecoderConfig(opts).warn)
+
+	return headers, fields, nil
+}
+
+// addKeyPath adds a dotted key path to order, keeping first-appearance order.
+func addKeyPath(order *keyOrderInfo, path []string) {
+	for _, key := range path {
+		if _, ok := order.nested[key]; !ok {
+			order.keys = append(order.keys, key)
+			order.nested[key] = &keyOrderInfo{nested: make(map[string]*keyOrderInfo)}
+		}
+		order = order.nested[key]
+	}
+}
+
+// normalizeTOML converts decoded TOML values to the shapes inferHeaders expects:
+// arrays of tables become []any and date-times are formatted as RFC 3339.
+func normalizeTOML(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		for k, e := range val {
+			val[k] = normalizeTOML(e)
+		}
+		return val
+	case []map[string]any:
+		out := make([]any, len(val))
+		for i, e := range val {
+			out[i] = normalizeTOML(e)
+		}
+		return out
+	case []any:
+		for i, e := range val {
+			val[i] = normalizeTOML(e)
+		}
+		return val
+	case time.Time:
+		return val.Format(time.RFC3339Nano)
+	default:
+		return v
+	}
+}
@@ -0,0 +1,98 @@
+package converter_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/osamingo/go-csvpp"
+	"github.com/osamingo/go-csvpp/cmd/csvpp/internal/converter"
+)
+
+func TestFromTOML(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		input       string
+		wantHeaders []*csvpp.ColumnHeader
+		wantRecords [][]*csvpp.Field
+		wantErr     bool
+	}{
+		{
+			name: "success: array of tables",
+			input: `title = "people"
+
+[[records]]
+name = "Alice"
+age = 30
+tags = ["go", "rust"]
+geo = { lat = 35.6, lon = 139.7 }
+address = [{ city = "Tokyo" }, { city = "Osaka" }]
+
+[[records]]
+name = "Bob"
+joined = 2024-01-02T03:04:05Z
+`,
+			wantHeaders: []*csvpp.ColumnHeader{
+				{Name: "name", Kind: csvpp.SimpleField, ArrayDelimiter: '~', ComponentDelimiter: '^'},
+				{Name: "age", Kind: csvpp.SimpleField, ArrayDelimiter: '~', ComponentDelimiter: '^'},
+				{Name: "tags", Kind: csvpp.ArrayField, ArrayDelimiter: '~', ComponentDelimiter: '^'},
+				{Name: "geo", Kind: csvpp.StructuredField, ArrayDelimiter: '~', ComponentDelimiter: '^', Components: []*csvpp.ColumnHeader{
+					{Name: "lat", Kind: csvpp.SimpleField, ArrayDelimiter: ';', ComponentDelimiter: ':'},
+					{Name: "lon", Kind: csvpp.SimpleField, ArrayDelimiter: ';', ComponentDelimiter: ':'},
+				}},
+				{Name: "address", Kind: csvpp.ArrayStructuredField, ArrayDelimiter: '~', ComponentDelimiter: '^', Components: []*csvpp.ColumnHeader{
+					{Name: "city", Kind: csvpp.SimpleField, ArrayDelimiter: ';', ComponentDelimiter: ':'},
+				}},
+				{Name: "joined", Kind: csvpp.SimpleField, ArrayDelimiter: '~', ComponentDelimiter: '^'},
+			},
+			wantRecords: [][]*csvpp.Field{
+				{
+					{Value: "Alice"},
+					{Value: "30"},
+					{Values: []string{"go", "rust"}},
+					{Components: []*csvpp.Field{{Value: "35.6"}, {Value: "139.7"}}},
+					{Components: []*csvpp.Field{
+						{Components: []*csvpp.Field{{Value: "Tokyo"}}},
+						{Components: []*csvpp.Field{{Value: "Osaka"}}},
+					}},
+					{},
+				},
+				{{Value: "Bob"}, {}, {}, {}, {}, {Value: "2024-01-02T03:04:05Z"}},
+			},
+		},
+		{
+			name:  "success: empty document",
+			input: "",
+		},
+		{
+			name:    "error: no array of tables",
+			input:   `name = "Alice"`,
+			wantErr: true,
+		},
+		{
+			name:    "error: invalid TOML",
+			input:   `[[records]`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			headers, records, err := converter.FromTOML(strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FromTOML() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.wantHeaders, headers); diff != "" {
+				t.Errorf("headers mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantRecords, records); diff != "" {
+				t.Errorf("records mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
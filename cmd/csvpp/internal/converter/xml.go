@@ -0,0 +1,184 @@
+package converter
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/osamingo/go-csvpp"
+)
+
+// Element names with a special meaning, as written by csvpputil.XMLWriter.
+const (
+	xmlItemName  = "item"  // marks array elements
+	xmlFieldName = "field" // a field named by its name attribute, such as <field name="1st">
+)
+
+// xmlElement is a generic XML element tree.
+type xmlElement struct {
+	name     string
+	text     strings.Builder
+	children []*xmlElement
+}
+
+// FromXML reads an XML document and converts the child elements of its root
+// element to CSVPP headers and records, one record per child.
+//
+// Within a record, an element whose children are all <item> elements becomes
+// an array, and an element with other child elements becomes a structured value;
+// a child name repeated within one element is collected into an array. An
+// element with neither children nor text is an empty value. A <field>
+// element with a name attribute is named by the attribute, for names that are
+// not valid XML names; other attributes are ignored. Only WithWarnings applies.
+func FromXML(r io.Reader, opts ...DecoderOption) ([]*csvpp.ColumnHeader, [][]*csvpp.Field, error) {
+	root, err := parseXML(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decode XML: %w", err)
+	}
+	if root == nil || len(root.children) == 0 {
+		return nil, nil, nil
+	}
+
+	var order *keyOrderInfo
+	records := make([]map[string]any, 0, len(root.children))
+	for i, child := range root.children {
+		v, o := xmlValue(child)
+		record, ok := v.(map[string]any)
+		if !ok {
+			if v != nil {
+				return nil, nil, fmt.Errorf("XML record %d has no named child elements", i+1)
+			}
+			record, o = map[string]any{}, nil
+		}
+		order = mergeKeyOrder(order, o)
+		records = append(records, record)
+	}
+	if order == nil {
+		order = &keyOrderInfo{nested: make(map[string]*keyOrderInfo)}
+	}
+
+	headers := inferHeaders(records, order)
+	fields := convertRecords(headers, records, newDecoderConfig(opts).warn)
+
+	return headers, fields, nil
+}
+
+// parseXML reads the document element and its descendants.
+func parseXML(r io.Reader) (*xmlElement, error) {
+	dec := xml.NewDecoder(r)
+	var (
+		root  *xmlElement
+		stack []*xmlElement
+	)
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			elem := &xmlElement{name: t.Name.Local}
+			if elem.name == xmlFieldName {
+				for _, a := range t.Attr {
+					if a.Name.Local == "name" {
+						elem.name = a.Value
+					}
+				}
+			}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, elem)
+			} else if root == nil {
+				root = elem
+			} else {
+				return nil, errors.New("multiple root elements")
+			}
+			stack = append(stack, elem)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text.Write(t)
+			}
+		}
+	}
+	return root, nil
+}
+
+// xmlValue converts an element to a string, []any or map[string]any value and
+// returns the key order of map values.
+func xmlValue(e *xmlElement) (any, *keyOrderInfo) {
+	if len(e.children) == 0 {
+		if strings.TrimSpace(e.text.String()) == "" {
+			return nil, nil
+		}
+		return e.text.String(), nil
+	}
+
+	if isXMLArray(e.children) {
+		return xmlArray(e.children)
+	}
+
+	m := make(map[string]any, len(e.children))
+	order := &keyOrderInfo{nested: make(map[string]*keyOrderInfo)}
+	for _, child := range e.children {
+		if _, ok := m[child.name]; ok {
+			continue
+		}
+		var (
+			v any
+			o *keyOrderInfo
+		)
+		if repeated := xmlChildren(e.children, child.name); len(repeated) > 1 {
+			v, o = xmlArray(repeated)
+		} else {
+			v, o = xmlValue(child)
+		}
+		m[child.name] = v
+		order.keys = append(order.keys, child.name)
+		if o != nil {
+			order.nested[child.name] = o
+		}
+	}
+	return m, order
+}
+
+// xmlArray converts elements to a []any value and returns the merged key order
+// of its map elements.
+func xmlArray(elems []*xmlElement) (any, *keyOrderInfo) {
+	var order *keyOrderInfo
+	values := make([]any, len(elems))
+	for i, elem := range elems {
+		var o *keyOrderInfo
+		values[i], o = xmlValue(elem)
+		order = mergeKeyOrder(order, o)
+	}
+	return values, order
+}
+
+// isXMLArray reports whether all children are <item> elements.
+func isXMLArray(children []*xmlElement) bool {
+	for _, c := range children {
+		if c.name != xmlItemName {
+			return false
+		}
+	}
+	return true
+}
+
+// xmlChildren returns the children named name.
+func xmlChildren(children []*xmlElement, name string) []*xmlElement {
+	var out []*xmlElement
+	for _, c := range children {
+		if c.name == name {
+			out = append(out, c)
+		}
+	}
+	return out
+}
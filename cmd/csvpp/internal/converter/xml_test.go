@@ -0,0 +1,125 @@
+package converter_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/osamingo/go-csvpp"
+	"github.com/osamingo/go-csvpp/cmd/csvpp/internal/converter"
+)
+
+func TestFromXML(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		input       string
+		wantHeaders []*csvpp.ColumnHeader
+		wantRecords [][]*csvpp.Field
+		wantErr     bool
+	}{
+		{
+			name: "success: element per record",
+			input: `<?xml version="1.0" encoding="UTF-8"?>
+<records>
+  <record>
+    <name>Alice &amp; Bob</name>
+    <tags><item>go</item><item>rust</item></tags>
+    <geo><lat>35.6</lat><lon>139.7</lon></geo>
+    <address><item><city>Tokyo</city></item></address>
+  </record>
+  <record>
+    <name>Carol</name>
+    <tags></tags>
+    <geo><lat></lat><lon></lon></geo>
+    <address></address>
+    <note>new</note>
+  </record>
+</records>
+`,
+			wantHeaders: []*csvpp.ColumnHeader{
+				{Name: "name", Kind: csvpp.SimpleField, ArrayDelimiter: '~', ComponentDelimiter: '^'},
+				{Name: "tags", Kind: csvpp.ArrayField, ArrayDelimiter: '~', ComponentDelimiter: '^'},
+				{Name: "geo", Kind: csvpp.StructuredField, ArrayDelimiter: '~', ComponentDelimiter: '^', Components: []*csvpp.ColumnHeader{
+					{Name: "lat", Kind: csvpp.SimpleField, ArrayDelimiter: ';', ComponentDelimiter: ':'},
+					{Name: "lon", Kind: csvpp.SimpleField, ArrayDelimiter: ';', ComponentDelimiter: ':'},
+				}},
+				{Name: "address", Kind: csvpp.ArrayStructuredField, ArrayDelimiter: '~', ComponentDelimiter: '^', Components: []*csvpp.ColumnHeader{
+					{Name: "city", Kind: csvpp.SimpleField, ArrayDelimiter: ';', ComponentDelimiter: ':'},
+				}},
+				{Name: "note", Kind: csvpp.SimpleField, ArrayDelimiter: '~', ComponentDelimiter: '^'},
+			},
+			wantRecords: [][]*csvpp.Field{
+				{
+					{Value: "Alice & Bob"},
+					{Values: []string{"go", "rust"}},
+					{Components: []*csvpp.Field{{Value: "35.6"}, {Value: "139.7"}}},
+					{Components: []*csvpp.Field{{Components: []*csvpp.Field{{Value: "Tokyo"}}}}},
+					{},
+				},
+				{
+					{Value: "Carol"},
+					{},
+					{Components: []*csvpp.Field{{}, {}}},
+					{},
+					{Value: "new"},
+				},
+			},
+		},
+		{
+			name:  "success: repeated child names become an array",
+			input: `<people><person><tag>a</tag><tag>b</tag></person></people>`,
+			wantHeaders: []*csvpp.ColumnHeader{
+				{Name: "tag", Kind: csvpp.ArrayField, ArrayDelimiter: '~', ComponentDelimiter: '^'},
+			},
+			wantRecords: [][]*csvpp.Field{
+				{{Values: []string{"a", "b"}}},
+			},
+		},
+		{
+			name:  "success: field elements named by attribute",
+			input: `<records><record><field name="1st">gold</field><field name="-rank"><item>1</item></field><field>plain</field></record></records>`,
+			wantHeaders: []*csvpp.ColumnHeader{
+				{Name: "1st", Kind: csvpp.SimpleField, ArrayDelimiter: '~', ComponentDelimiter: '^'},
+				{Name: "-rank", Kind: csvpp.ArrayField, ArrayDelimiter: '~', ComponentDelimiter: '^'},
+				{Name: "field", Kind: csvpp.SimpleField, ArrayDelimiter: '~', ComponentDelimiter: '^'},
+			},
+			wantRecords: [][]*csvpp.Field{
+				{{Value: "gold"}, {Values: []string{"1"}}, {Value: "plain"}},
+			},
+		},
+		{
+			name:  "success: empty root",
+			input: `<records></records>`,
+		},
+		{
+			name:    "error: record without child elements",
+			input:   `<records><record>Alice</record></records>`,
+			wantErr: true,
+		},
+		{
+			name:    "error: malformed XML",
+			input:   `<records><record></records>`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			headers, records, err := converter.FromXML(strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FromXML() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.wantHeaders, headers); diff != "" {
+				t.Errorf("headers mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantRecords, records); diff != "" {
+				t.Errorf("records mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
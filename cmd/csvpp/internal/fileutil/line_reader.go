@@ -0,0 +1,78 @@
+package fileutil
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// LineReader reads complete lines from a file that is still being written.
+// At the end of the file it returns io.EOF but keeps a last line that has no
+// newline yet, so that reading can continue once the rest of the line is
+// appended. This includes the first line, so a header that is still being
+// written is not read as a shorter one; use WaitLine to wait for it.
+type LineReader struct {
+	r       io.Reader
+	pending []byte // read from r but not returned yet
+	ready   int    // leading bytes of pending that can be returned
+	buf     [32 * 1024]byte
+}
+
+// NewLineReader returns a LineReader that reads from r.
+func NewLineReader(r io.Reader) *LineReader {
+	return &LineReader{r: r}
+}
+
+// Read implements io.Reader.
+func (l *LineReader) Read(p []byte) (int, error) {
+	for l.ready == 0 {
+		n, err := l.fill()
+		switch {
+		case l.ready > 0, n > 0:
+		case err == nil:
+			return 0, nil // nothing read, as io.Reader allows
+		default:
+			return 0, err
+		}
+	}
+
+	n := copy(p, l.pending[:l.ready])
+	l.pending = l.pending[n:]
+	l.ready -= n
+	return n, nil
+}
+
+// WaitLine waits until a complete line can be read, checking the file for
+// appended data every interval. It returns early if ctx is done or reading
+// fails with an error other than io.EOF.
+func (l *LineReader) WaitLine(ctx context.Context, interval time.Duration) error {
+	for l.ready == 0 {
+		n, err := l.fill()
+		if l.ready > 0 || n > 0 {
+			continue
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+	return nil
+}
+
+// fill makes the complete lines in pending ready to be returned, reading once
+// from r if there are none.
+func (l *LineReader) fill() (int, error) {
+	if end := bytes.LastIndexByte(l.pending, '\n') + 1; end > 0 {
+		l.ready = end
+		return 0, nil
+	}
+	n, err := l.r.Read(l.buf[:])
+	l.pending = append(l.pending, l.buf[:n]...)
+	return n, err
+}
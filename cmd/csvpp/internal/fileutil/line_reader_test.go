@@ -0,0 +1,182 @@
+package fileutil_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/osamingo/go-csvpp/cmd/csvpp/internal/fileutil"
+)
+
+func TestLineReader(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		appends []string // data appended to the file before each read to the end
+		want    []string // data read after each append
+	}{
+		{
+			name:    "success: complete lines",
+			appends: []string{"name,age\nAlice,30\n"},
+			want:    []string{"name,age\nAlice,30\n"},
+		},
+		{
+			name:    "success: partial last line is held back",
+			appends: []string{"name,age\nAli", "ce,30\nBob", ",25\n"},
+			want:    []string{"name,age\n", "Alice,30\n", "Bob,25\n"},
+		},
+		{
+			name:    "success: partial header is held back",
+			appends: []string{"name,a", "ge\nAlice,30\n"},
+			want:    []string{"", "name,age\nAlice,30\n"},
+		},
+		{
+			name:    "success: nothing appended",
+			appends: []string{"name\n", ""},
+			want:    []string{"name\n", ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var file bytes.Buffer // returns io.EOF at its end, like a growing file
+			r := fileutil.NewLineReader(&file)
+			var got []string
+			for _, data := range tt.appends {
+				file.WriteString(data)
+				b, err := readToEOF(r)
+				if err != nil {
+					t.Fatalf("Read() error = %v", err)
+				}
+				got = append(got, string(b))
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("read mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLineReader_ZeroRead(t *testing.T) {
+	t.Parallel()
+
+	r := fileutil.NewLineReader(zeroReader{})
+	if n, err := r.Read(make([]byte, 8)); n != 0 || err != nil {
+		t.Errorf("Read() = %d, %v, want 0, nil", n, err)
+	}
+}
+
+func TestLineReader_WaitLine(t *testing.T) {
+	t.Parallel()
+
+	broken := errors.New("broken")
+
+	tests := []struct {
+		name    string
+		reads   []string // data returned by successive reads, then io.EOF
+		err     error    // error returned after the reads instead of io.EOF
+		cancel  bool
+		want    string // data read after waiting
+		wantErr error
+	}{
+		{
+			name:  "success: waits for the newline of the first line",
+			reads: []string{"name", "", ",age", "\nAli"},
+			want:  "name,age\n",
+		},
+		{
+			name:    "error: context canceled",
+			reads:   []string{"name,age"},
+			cancel:  true,
+			wantErr: context.Canceled,
+		},
+		{
+			name:    "error: read error",
+			reads:   []string{"name"},
+			err:     broken,
+			wantErr: broken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(t.Context())
+			if tt.cancel {
+				cancel()
+			}
+			defer cancel()
+
+			r := fileutil.NewLineReader(&chunkReader{chunks: tt.reads, err: tt.err})
+			err := r.WaitLine(ctx, time.Millisecond)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("WaitLine() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("WaitLine() error = %v", err)
+			}
+			b, err := readToEOF(r)
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if string(b) != tt.want {
+				t.Errorf("read %q, want %q", b, tt.want)
+			}
+		})
+	}
+}
+
+// readToEOF reads from r until io.EOF.
+func readToEOF(r io.Reader) ([]byte, error) {
+	var out []byte
+	buf := make([]byte, 4)
+	for {
+		n, err := r.Read(buf)
+		out = append(out, buf[:n]...)
+		if errors.Is(err, io.EOF) {
+			return out, nil
+		}
+		if err != nil {
+			return out, err
+		}
+	}
+}
+
+// zeroReader reads nothing without an error.
+type zeroReader struct{}
+
+func (zeroReader) Read([]byte) (int, error) { return 0, nil }
+
+// chunkReader returns each chunk from one read, with io.EOF after an empty
+// chunk as a file does before more is written, and then err or io.EOF.
+type chunkReader struct {
+	chunks []string
+	err    error
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	if len(r.chunks) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		return 0, io.EOF
+	}
+	chunk := r.chunks[0]
+	r.chunks = r.chunks[1:]
+	if chunk == "" {
+		return 0, io.EOF
+	}
+	return copy(p, chunk), nil
+}
@@ -0,0 +1,51 @@
+package query
+
+import "github.com/osamingo/go-csvpp/internal/expr"
+
+type (
+	tokenKind = expr.Kind
+	token     = expr.Token
+)
+
+const (
+	tokEOF    = expr.EOF
+	tokIdent  = expr.Ident  // field path or keyword
+	tokString = expr.String // "..." or '...'
+	tokNumber = expr.Number // 42, -1.5
+)
+
+const (
+	tokOp     = expr.Symbol + iota // == != < <= > >= =~ !~
+	tokNot                         // !
+	tokAnd                         // &&
+	tokOr                          // ||
+	tokLParen                      // (
+	tokRParen                      // )
+	tokComma                       // ,
+	tokAt                          // @ (current array element)
+)
+
+// syntax is the token syntax of filter expressions. Strings use backslash
+// escapes and field paths may contain indices.
+var syntax = &expr.Syntax{
+	Symbols: []expr.SymbolDef{
+		{Text: "&&", Kind: tokAnd},
+		{Text: "||", Kind: tokOr},
+		{Text: "==", Kind: tokOp},
+		{Text: "!=", Kind: tokOp},
+		{Text: "<=", Kind: tokOp},
+		{Text: ">=", Kind: tokOp},
+		{Text: "=~", Kind: tokOp},
+		{Text: "!~", Kind: tokOp},
+		{Text: "<", Kind: tokOp},
+		{Text: ">", Kind: tokOp},
+		{Text: "=", Kind: tokOp, As: "=="}, // a single "=" is equality for convenience
+		{Text: "!", Kind: tokNot},
+		{Text: "(", Kind: tokLParen},
+		{Text: ")", Kind: tokRParen},
+		{Text: ",", Kind: tokComma},
+		{Text: "@", Kind: tokAt},
+	},
+	Quotes:   map[rune]expr.Kind{'"': tokString, '\'': tokString},
+	Brackets: true,
+}
@@ -0,0 +1,348 @@
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/osamingo/go-csvpp"
+)
+
+// parser is a recursive descent parser over a token slice.
+//
+// Grammar:
+//
+//	expr     = and { ("or" | "||") and }
+//	and      = unary { ("and" | "&&") unary }
+//	unary    = ("not" | "!") unary | cond
+//	cond     = operand [ op operand | "in" list | "not" "in" list ]
+//	op       = "==" | "!=" | "<" | "<=" | ">" | ">=" | "=~" | "!~"
+//	         | "contains" | "startswith" | "endswith" | "matches"
+//	list     = "(" operand { "," operand } ")"
+//	operand  = string | number | "true" | "false" | path | "@" | "(" expr ")"
+//	         | "len" "(" path ")" | ("any" | "all") "(" path "," expr ")"
+type parser struct {
+	tokens []token
+	pos    int
+	scope  scopeInfo
+}
+
+// peek returns the current token without consuming it.
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+// next consumes and returns the current token.
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.Kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+// isKeyword reports whether t is the identifier keyword kw.
+func isKeyword(t token, kw string) bool {
+	return t.Kind == tokIdent && t.Text == kw
+}
+
+// expect consumes a token of the given kind or returns an error.
+func (p *parser) expect(kind tokenKind, what string) (token, error) {
+	t := p.next()
+	if t.Kind != kind {
+		return t, p.errorf(t, "expected %s, got %s", what, describe(t))
+	}
+	return t, nil
+}
+
+// errorf returns a syntax error positioned at t.
+func (p *parser) errorf(t token, format string, args ...any) error {
+	return fmt.Errorf("%w at position %d: %s", ErrSyntax, t.Pos, fmt.Sprintf(format, args...))
+}
+
+// describe returns a human-readable description of t for error messages.
+func describe(t token) string {
+	if t.Kind == tokEOF {
+		return "end of expression"
+	}
+	return fmt.Sprintf("%q", t.Text)
+}
+
+func (p *parser) parseExpr() (boolNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for t := p.peek(); t.Kind == tokOr || isKeyword(t, "or"); t = p.peek() {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &orNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (boolNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for t := p.peek(); t.Kind == tokAnd || isKeyword(t, "and"); t = p.peek() {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &andNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (boolNode, error) {
+	if t := p.peek(); t.Kind == tokNot || isKeyword(t, "not") {
+		p.next()
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{expr: expr}, nil
+	}
+	return p.parseCond()
+}
+
+func (p *parser) parseCond() (boolNode, error) {
+	start := p.peek()
+	left, cond, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	t := p.peek()
+	op := ""
+	switch {
+	case t.Kind == tokOp:
+		op = t.Text
+	case isKeyword(t, "contains"), isKeyword(t, "startswith"), isKeyword(t, "endswith"), isKeyword(t, "matches"), isKeyword(t, "in"):
+		op = t.Text
+	case isKeyword(t, "not") && isKeyword(p.tokens[p.pos+1], "in"):
+		op = "not in"
+		p.next()
+	}
+
+	if op == "" {
+		if cond != nil {
+			return cond, nil
+		}
+		return &truthyNode{value: left}, nil
+	}
+	if cond != nil {
+		return nil, p.errorf(t, "cannot apply %q to a boolean expression", op)
+	}
+	p.next()
+
+	switch op {
+	case "in", "not in":
+		list, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		var n boolNode = &inNode{value: left, list: list}
+		if op == "not in" {
+			n = &notNode{expr: n}
+		}
+		return n, nil
+
+	case "=~", "!~", "matches":
+		pat, err := p.expect(tokString, "regular expression string")
+		if err != nil {
+			return nil, err
+		}
+		re, err := regexp.Compile(pat.Text)
+		if err != nil {
+			return nil, p.errorf(pat, "invalid regular expression: %v", err)
+		}
+		return &regexNode{value: left, re: re, negate: op == "!~"}, nil
+	}
+
+	right, rcond, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	if rcond != nil {
+		return nil, p.errorf(t, "cannot apply %q to a boolean expression", op)
+	}
+
+	return &compareNode{op: op, left: left, right: right, list: p.isList(start)}, nil
+}
+
+// isList reports whether the operand starting at t is a path selecting a whole array field,
+// in which case "contains" tests element membership instead of substrings.
+func (p *parser) isList(t token) bool {
+	if t.Kind != tokIdent {
+		return false
+	}
+	path, err := csvpp.ParsePath(t.Text)
+	if err != nil {
+		return false
+	}
+	h, err := path.Header(p.scope.headers)
+	return err == nil && h.Kind == csvpp.ArrayField
+}
+
+// parseList parses a parenthesized, comma-separated list of operands.
+func (p *parser) parseList() ([]valueNode, error) {
+	if _, err := p.expect(tokLParen, `"("`); err != nil {
+		return nil, err
+	}
+	var list []valueNode
+	for {
+		v, cond, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		if cond != nil {
+			return nil, p.errorf(p.peek(), "list items must be values")
+		}
+		list = append(list, v)
+
+		t := p.next()
+		if t.Kind == tokRParen {
+			return list, nil
+		}
+		if t.Kind != tokComma {
+			return nil, p.errorf(t, `expected "," or ")", got %s`, describe(t))
+		}
+	}
+}
+
+// parseOperand parses a value operand or a parenthesized/quantified boolean expression.
+// Exactly one of the returned nodes is non-nil.
+func (p *parser) parseOperand() (valueNode, boolNode, error) {
+	t := p.next()
+
+	switch t.Kind {
+	case tokString, tokNumber:
+		return &literalNode{value: t.Text}, nil, nil
+
+	case tokAt:
+		if !p.scope.hasElem {
+			return nil, nil, p.errorf(t, `"@" is only valid inside any()/all() over an array field`)
+		}
+		return &elemNode{}, nil, nil
+
+	case tokLParen:
+		expr, err := p.parseExpr()
+		if err != nil {
+			return nil, nil, err
+		}
+		if _, err := p.expect(tokRParen, `")"`); err != nil {
+			return nil, nil, err
+		}
+		return nil, expr, nil
+
+	case tokIdent:
+		switch {
+		case t.Text == "true" || t.Text == "false":
+			return &literalNode{value: t.Text}, nil, nil
+		case (t.Text == "any" || t.Text == "all") && p.peek().Kind == tokLParen:
+			n, err := p.parseQuantifier(t.Text == "all")
+			return nil, n, err
+		case t.Text == "len" && p.peek().Kind == tokLParen:
+			n, err := p.parseLen()
+			return n, nil, err
+		}
+		path, h, err := p.parsePath(t)
+		if err != nil {
+			return nil, nil, err
+		}
+		if h.Kind == csvpp.StructuredField || h.Kind == csvpp.ArrayStructuredField {
+			// A structured field has no value of its own to compare.
+			return nil, nil, p.errorf(t, "%v", &csvpp.PathError{
+				Path: t.Text,
+				Err:  fmt.Errorf("%w: %q is structured; select a component", csvpp.ErrInvalidPath, h.Name),
+			})
+		}
+		return &pathNode{path: path}, nil, nil
+
+	default:
+		return nil, nil, p.errorf(t, "expected a value, got %s", describe(t))
+	}
+}
+
+// parsePath parses t as a field path and resolves its header in the current scope.
+func (p *parser) parsePath(t token) (*csvpp.Path, *csvpp.ColumnHeader, error) {
+	path, err := csvpp.ParsePath(t.Text)
+	if err != nil {
+		return nil, nil, p.errorf(t, "%v", err)
+	}
+	h, err := path.Header(p.scope.headers)
+	if err != nil {
+		return nil, nil, p.errorf(t, "%v", err)
+	}
+	return path, h, nil
+}
+
+// parseQuantifier parses the arguments of any(path, expr) or all(path, expr).
+func (p *parser) parseQuantifier(all bool) (boolNode, error) {
+	p.next() // "("
+	t, err := p.expect(tokIdent, "array field path")
+	if err != nil {
+		return nil, err
+	}
+	path, h, err := p.parsePath(t)
+	if err != nil {
+		return nil, err
+	}
+
+	inner := p.scope
+	kind := h.Kind
+	switch {
+	case kind == csvpp.ArrayField:
+		inner.hasElem = true
+	case kind == csvpp.SimpleField && strings.HasSuffix(t.Text, "[]"):
+		// The elements of a plain array ("tags[]").
+		inner.hasElem = true
+		kind = csvpp.ArrayField
+	case kind == csvpp.ArrayStructuredField, kind == csvpp.StructuredField:
+		// StructuredField is an already indexed element ("address[]").
+		inner = scopeInfo{headers: h.Components}
+	default:
+		return nil, p.errorf(t, "%q is not an array field", t.Text)
+	}
+	if _, err := p.expect(tokComma, `","`); err != nil {
+		return nil, err
+	}
+
+	outer := p.scope
+	p.scope = inner
+	expr, err := p.parseExpr()
+	p.scope = outer
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := p.expect(tokRParen, `")"`); err != nil {
+		return nil, err
+	}
+
+	return &quantNode{path: path, kind: kind, expr: expr, all: all}, nil
+}
+
+// parseLen parses the argument of len(path).
+func (p *parser) parseLen() (valueNode, error) {
+	p.next() // "("
+	t, err := p.expect(tokIdent, "field path")
+	if err != nil {
+		return nil, err
+	}
+	path, _, err := p.parsePath(t)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.expect(tokRParen, `")"`); err != nil {
+		return nil, err
+	}
+	return &lenNode{path: path}, nil
+}
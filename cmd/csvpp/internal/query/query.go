@@ -0,0 +1,256 @@
+// Package query implements the filter expression language used by the csvpp query command.
+//
+// An expression is evaluated against one CSV++ record at a time:
+//
+//	age >= 30 and geo.lat > 35
+//	name contains "li" or tags contains "go"
+//	any(address[], city == "Tokyo" and zip startswith "1")
+//	not (status in ("closed", "archived")) && len(tags) > 2
+//
+// Operands are field paths (see csvpp.Record), quoted strings, numbers, true/false,
+// and the functions len(path), any(path, expr) and all(path, expr).
+// Inside any/all, paths are relative to the array element; "@" refers to the
+// element itself when iterating a plain array field ("tags" or "tags[]").
+//
+// A path that selects several values (an array, or "[]" segments) matches if
+// any of its values satisfies the comparison. A structured field has no value
+// of its own, so only its components can be compared. Comparisons are numeric when both
+// sides parse as numbers and lexical otherwise; ordering a number against a
+// non-number (such as an empty field) is false.
+package query
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/osamingo/go-csvpp"
+	"github.com/osamingo/go-csvpp/internal/expr"
+)
+
+// ErrSyntax is returned when an expression cannot be parsed.
+var ErrSyntax = errors.New("query: syntax error")
+
+// Expr is a compiled filter expression.
+type Expr struct {
+	root boolNode
+}
+
+// Compile parses src and resolves its field paths against headers.
+func Compile(src string, headers []*csvpp.ColumnHeader) (*Expr, error) {
+	tokens, err := syntax.Lex(src)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSyntax, err)
+	}
+
+	p := &parser{tokens: tokens, scope: scopeInfo{headers: headers}}
+	root, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.Kind != tokEOF {
+		return nil, p.errorf(t, "unexpected %q", t.Text)
+	}
+
+	return &Expr{root: root}, nil
+}
+
+// Match reports whether rec satisfies the expression.
+func (e *Expr) Match(rec *csvpp.Record) bool {
+	return e.root.match(&scope{rec: rec})
+}
+
+// scope is the evaluation context: the current record, or the current element
+// inside any/all.
+type scope struct {
+	rec  *csvpp.Record
+	elem string // current element of a plain array (referenced by "@")
+}
+
+// scopeInfo is the compile-time counterpart of scope.
+type scopeInfo struct {
+	headers []*csvpp.ColumnHeader
+	hasElem bool // "@" is available
+}
+
+// boolNode is an expression node that yields a boolean.
+type boolNode interface {
+	match(s *scope) bool
+}
+
+// valueNode is an expression node that yields zero or more string values.
+type valueNode interface {
+	values(s *scope) []string
+}
+
+type (
+	andNode struct{ left, right boolNode }
+	orNode  struct{ left, right boolNode }
+	notNode struct{ expr boolNode }
+
+	// truthyNode treats a value as a boolean condition.
+	truthyNode struct{ value valueNode }
+
+	// compareNode compares two operands with op.
+	compareNode struct {
+		op          string
+		left, right valueNode
+		list        bool // left is a whole array: "contains" tests membership
+	}
+
+	// regexNode matches an operand against a regular expression.
+	regexNode struct {
+		value  valueNode
+		re     *regexp.Regexp
+		negate bool
+	}
+
+	// inNode tests whether an operand equals any of a list of operands.
+	inNode struct {
+		value valueNode
+		list  []valueNode
+	}
+
+	// quantNode evaluates a sub-expression for each element of an array (any/all).
+	quantNode struct {
+		path *csvpp.Path
+		kind csvpp.FieldKind
+		expr boolNode
+		all  bool
+	}
+
+	literalNode struct{ value string }
+	pathNode    struct{ path *csvpp.Path }
+	elemNode    struct{}
+	lenNode     struct{ path *csvpp.Path }
+)
+
+func (n *andNode) match(s *scope) bool { return n.left.match(s) && n.right.match(s) }
+func (n *orNode) match(s *scope) bool  { return n.left.match(s) || n.right.match(s) }
+func (n *notNode) match(s *scope) bool { return !n.expr.match(s) }
+
+func (n *truthyNode) match(s *scope) bool {
+	for _, v := range n.value.values(s) {
+		if b, err := strconv.ParseBool(v); err == nil {
+			if b {
+				return true
+			}
+			continue
+		}
+		if v != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *compareNode) match(s *scope) bool {
+	left := n.left.values(s)
+	right := n.right.values(s)
+
+	for _, r := range right {
+		if n.list && n.op == "contains" {
+			if slices.Contains(left, r) {
+				return true
+			}
+			continue
+		}
+		for _, l := range left {
+			if compare(n.op, l, r) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (n *regexNode) match(s *scope) bool {
+	matched := slices.ContainsFunc(n.value.values(s), n.re.MatchString)
+	return matched != n.negate
+}
+
+func (n *inNode) match(s *scope) bool {
+	values := n.value.values(s)
+	for _, item := range n.list {
+		for _, want := range item.values(s) {
+			for _, v := range values {
+				if compare("==", v, want) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func (n *quantNode) match(s *scope) bool {
+	for _, sub := range n.elements(s) {
+		ok := n.expr.match(sub)
+		if n.all && !ok {
+			return false
+		}
+		if !n.all && ok {
+			return true
+		}
+	}
+	return n.all
+}
+
+// elements returns one scope per array element selected by the quantifier path.
+func (n *quantNode) elements(s *scope) []*scope {
+	if n.kind == csvpp.ArrayField {
+		values, _ := s.rec.GetAllPath(n.path)
+		scopes := make([]*scope, len(values))
+		for i, v := range values {
+			scopes[i] = &scope{rec: s.rec, elem: v}
+		}
+		return scopes
+	}
+
+	elems, err := s.rec.ElementsPath(n.path)
+	if err != nil {
+		return nil
+	}
+	scopes := make([]*scope, len(elems))
+	for i, e := range elems {
+		scopes[i] = &scope{rec: e}
+	}
+	return scopes
+}
+
+func (n *literalNode) values(*scope) []string { return []string{n.value} }
+
+func (n *pathNode) values(s *scope) []string {
+	values, err := s.rec.GetAllPath(n.path)
+	if err != nil {
+		return nil // e.g. an index beyond the end of the array
+	}
+	return values
+}
+
+func (*elemNode) values(s *scope) []string { return []string{s.elem} }
+
+func (n *lenNode) values(s *scope) []string {
+	count, err := s.rec.LenPath(n.path)
+	if err != nil {
+		return []string{"0"}
+	}
+	return []string{strconv.Itoa(count)}
+}
+
+// compare applies op to l and r, numerically when both are numbers.
+func compare(op, l, r string) bool {
+	switch op {
+	case "contains":
+		return strings.Contains(l, r)
+	case "startswith":
+		return strings.HasPrefix(l, r)
+	case "endswith":
+		return strings.HasSuffix(l, r)
+	default:
+		return expr.Compare(op, l, r)
+	}
+}
@@ -0,0 +1,148 @@
+package query_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/osamingo/go-csvpp"
+	"github.com/osamingo/go-csvpp/cmd/csvpp/internal/query"
+)
+
+const testInput = `name,age,active,tags[],geo(lat^lon),address[](street^city^zip)
+Alice,30,true,go~rust,35.68^139.76,1-1 Chiyoda^Tokyo^100-0001~5th Ave^New York^10001
+Bob,25,false,,34.05^-118.24,Main St^Los Angeles^90001
+Carol,41,true,python,,
+`
+
+// readTestRecords reads testInput into records.
+func readTestRecords(t *testing.T) ([]*csvpp.ColumnHeader, []*csvpp.Record) {
+	t.Helper()
+
+	reader := csvpp.NewReader(strings.NewReader(testInput))
+	headers, err := reader.Headers()
+	if err != nil {
+		t.Fatalf("Headers() error = %v", err)
+	}
+	fields, err := reader.ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	records := make([]*csvpp.Record, len(fields))
+	for i, f := range fields {
+		records[i] = csvpp.NewRecord(headers, f)
+	}
+	return headers, records
+}
+
+func TestExpr_Match(t *testing.T) {
+	t.Parallel()
+
+	headers, records := readTestRecords(t)
+
+	tests := []struct {
+		name string
+		expr string
+		want []string // names of matching records
+	}{
+		{name: "success: string equality", expr: `name == "Alice"`, want: []string{"Alice"}},
+		{name: "success: single equals", expr: `name = 'Bob'`, want: []string{"Bob"}},
+		{name: "success: not equal", expr: `name != "Alice"`, want: []string{"Bob", "Carol"}},
+		{name: "success: numeric comparison", expr: `age >= 30`, want: []string{"Alice", "Carol"}},
+		{name: "success: numeric not lexical", expr: `age > 4`, want: []string{"Alice", "Bob", "Carol"}},
+		{name: "success: component path", expr: `geo.lat > 35`, want: []string{"Alice"}},
+		{name: "success: negative number", expr: `geo.lon < -100`, want: []string{"Bob"}},
+		{name: "success: substring contains", expr: `name contains "ro"`, want: []string{"Carol"}},
+		{name: "success: array membership contains", expr: `tags contains "go"`, want: []string{"Alice"}},
+		{name: "success: array element substring", expr: `tags[] contains "ust"`, want: []string{"Alice"}},
+		{name: "success: startswith", expr: `name startswith "B"`, want: []string{"Bob"}},
+		{name: "success: endswith", expr: `name endswith "ol"`, want: []string{"Carol"}},
+		{name: "success: regex", expr: `name =~ "^[AB]"`, want: []string{"Alice", "Bob"}},
+		{name: "success: negated regex", expr: `name !~ "^[AB]"`, want: []string{"Carol"}},
+		{name: "success: matches keyword", expr: `name matches "l$"`, want: []string{"Carol"}},
+		{name: "success: any over array structured", expr: `any(address[], city == "Tokyo")`, want: []string{"Alice"}},
+		{name: "success: any without brackets", expr: `any(address, zip startswith "9")`, want: []string{"Bob"}},
+		{name: "success: all over array structured", expr: `all(address, city contains "o")`, want: []string{"Alice", "Bob", "Carol"}},
+		{name: "success: any over plain array", expr: `any(tags, @ == "python")`, want: []string{"Carol"}},
+		{name: "success: any over plain array elements", expr: `any(tags[], @ == "go")`, want: []string{"Alice"}},
+		{name: "success: all over plain array elements", expr: `all(tags[], @ != "go")`, want: []string{"Bob", "Carol"}},
+		{name: "success: existential path", expr: `address[].city == "New York"`, want: []string{"Alice"}},
+		{name: "success: indexed path", expr: `address[1].city == "New York"`, want: []string{"Alice"}},
+		{name: "success: index out of range", expr: `address[5].city == "Tokyo"`, want: nil},
+		{name: "success: len", expr: `len(tags) >= 1 and len(address) == 0`, want: []string{"Carol"}},
+		{name: "success: boolean field", expr: `active`, want: []string{"Alice", "Carol"}},
+		{name: "success: boolean literal", expr: `active == true`, want: []string{"Alice", "Carol"}},
+		{name: "success: not", expr: `not active`, want: []string{"Bob"}},
+		{name: "success: bang", expr: `!(age < 30)`, want: []string{"Alice", "Carol"}},
+		{name: "success: and/or precedence", expr: `name == "Bob" or name == "Carol" and age > 40`, want: []string{"Bob", "Carol"}},
+		{name: "success: symbolic operators", expr: `(name == "Bob" || name == "Carol") && age > 40`, want: []string{"Carol"}},
+		{name: "success: in list", expr: `name in ("Alice", "Carol")`, want: []string{"Alice", "Carol"}},
+		{name: "success: not in list", expr: `name not in ("Alice", "Carol")`, want: []string{"Bob"}},
+		{name: "success: escaped quote", expr: `name != "a\"b"`, want: []string{"Alice", "Bob", "Carol"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			expr, err := query.Compile(tt.expr, headers)
+			if err != nil {
+				t.Fatalf("Compile(%q) unexpected error: %v", tt.expr, err)
+			}
+
+			var got []string
+			for _, rec := range records {
+				if expr.Match(rec) {
+					name, _ := rec.Get("name")
+					got = append(got, name)
+				}
+			}
+
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Match(%q) = %v, want %v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompile_Errors(t *testing.T) {
+	t.Parallel()
+
+	headers, _ := readTestRecords(t)
+
+	tests := []struct {
+		name string
+		expr string
+	}{
+		{name: "error: empty expression", expr: ``},
+		{name: "error: unknown field", expr: `email == "x"`},
+		{name: "error: unknown component", expr: `geo.alt > 1`},
+		{name: "error: unterminated string", expr: `name == "Alice`},
+		{name: "error: missing operand", expr: `name ==`},
+		{name: "error: trailing token", expr: `name == "a" "b"`},
+		{name: "error: unbalanced parenthesis", expr: `(name == "a"`},
+		{name: "error: invalid regex", expr: `name =~ "("`},
+		{name: "error: regex must be a string", expr: `name =~ age`},
+		{name: "error: any over simple field", expr: `any(name, @ == "a")`},
+		{name: "error: any over array element", expr: `any(tags[0], @ == "a")`},
+		{name: "error: comparing structured field", expr: `geo == "x"`},
+		{name: "error: comparing array-structured field", expr: `address != "x"`},
+		{name: "error: comparing array-structured element", expr: `address[] == "x"`},
+		{name: "error: structured field in list", expr: `name in (geo)`},
+		{name: "error: structured field as condition", expr: `geo`},
+		{name: "error: element outside any", expr: `@ == "a"`},
+		{name: "error: unexpected character", expr: `name # "a"`},
+		{name: "error: comparing boolean expression", expr: `(age > 1) == true`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := query.Compile(tt.expr, headers)
+			if !errors.Is(err, query.ErrSyntax) {
+				t.Errorf("Compile(%q) error = %v, want %v", tt.expr, err, query.ErrSyntax)
+			}
+		})
+	}
+}
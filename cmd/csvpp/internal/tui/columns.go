@@ -0,0 +1,211 @@
+package tui
+
+import (
+	"slices"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/osamingo/go-csvpp"
+)
+
+// Column layout constants.
+const (
+	markerColumnWidth = 2  // selection marker column
+	cellPadding       = 2  // Styles.Cell pads cells by one space on each side
+	titleMarkerWidth  = 2  // room column titles leave for the cursor and sort markers
+	minColumnWidth    = 3  // narrowest width a column can be resized to
+	maxColumnWidth    = 50 // widest width chosen by auto-fit
+	maxResizeWidth    = 200
+	resizeStep        = 2
+)
+
+// autoFitWidths returns the width of each data column that fits its title and
+// markers and its widest value, capped at maxColumnWidth.
+func autoFitWidths(headers []*csvpp.ColumnHeader, rows []table.Row) []int {
+	widths := make([]int, len(headers))
+	for i, h := range headers {
+		widths[i] = lipgloss.Width(formatHeaderTitle(h)) + titleMarkerWidth
+	}
+	for _, row := range rows {
+		// row[0] is the selection marker, data starts at index 1
+		for i := range min(len(row)-1, len(widths)) {
+			widths[i] = max(widths[i], lipgloss.Width(row[i+1]))
+		}
+	}
+	for i := range widths {
+		widths[i] = max(minColumnWidth, min(widths[i], maxColumnWidth))
+	}
+	return widths
+}
+
+// frozenColumn returns the first column that is not hidden, which stays in
+// place while scrolling horizontally, or -1 if every column is hidden.
+func (m *Model) frozenColumn() int {
+	return slices.Index(m.hidden, false)
+}
+
+// visibleColumns returns the data columns that fit in the terminal width: the
+// frozen column followed by the columns from colOffset on.
+// At least one column besides the frozen one is shown if there is any.
+func (m *Model) visibleColumns() []int {
+	frozen := m.frozenColumn()
+	if frozen < 0 {
+		return nil
+	}
+
+	cols := []int{frozen}
+	used := markerColumnWidth + cellPadding + m.widths[frozen] + cellPadding
+	for i := max(m.colOffset, frozen+1); i < len(m.headers); i++ {
+		if m.hidden[i] {
+			continue
+		}
+		w := m.widths[i] + cellPadding
+		if m.width > 0 && used+w > m.width && len(cols) > 1 {
+			break
+		}
+		cols = append(cols, i)
+		used += w
+	}
+	return cols
+}
+
+// scrollToColumn adjusts colOffset so that the current column is visible.
+func (m *Model) scrollToColumn() {
+	if m.column == m.frozenColumn() {
+		return
+	}
+	if m.column < m.colOffset {
+		m.colOffset = m.column
+		return
+	}
+	for m.colOffset < m.column && !slices.Contains(m.visibleColumns(), m.column) {
+		m.colOffset++
+	}
+}
+
+// moveColumn moves the column cursor by delta columns, skipping hidden ones.
+func (m *Model) moveColumn(delta int) {
+	step := 1
+	if delta < 0 {
+		step, delta = -1, -delta
+	}
+	for col := m.column + step; delta > 0 && col >= 0 && col < len(m.headers); col += step {
+		if !m.hidden[col] {
+			m.column = col
+			delta--
+		}
+	}
+	m.scrollToColumn()
+	m.refreshTable()
+}
+
+// resizeColumn changes the width of the current column by delta.
+func (m *Model) resizeColumn(delta int) {
+	if m.column < len(m.widths) {
+		m.widths[m.column] = max(minColumnWidth, min(m.widths[m.column]+delta, maxResizeWidth))
+		m.scrollToColumn()
+		m.refreshTable()
+	}
+}
+
+// fitColumn restores the auto-fit width of the current column.
+func (m *Model) fitColumn() {
+	if m.column < len(m.widths) {
+		m.widths[m.column] = m.fitWidths[m.column]
+		m.scrollToColumn()
+		m.refreshTable()
+	}
+}
+
+// hideColumn hides the current column and moves the cursor to the next
+// visible column. The last visible column cannot be hidden.
+func (m *Model) hideColumn() {
+	if m.hiddenCount() >= len(m.hidden)-1 {
+		return
+	}
+
+	col := m.column
+	m.hidden[col] = true
+	if m.moveColumn(1); m.column == col {
+		m.moveColumn(-1)
+	}
+}
+
+// hiddenCount returns the number of hidden columns.
+func (m *Model) hiddenCount() int {
+	n := 0
+	for _, h := range m.hidden {
+		if h {
+			n++
+		}
+	}
+	return n
+}
+
+// showAllColumns shows every hidden column.
+func (m *Model) showAllColumns() {
+	for i := range m.hidden {
+		m.hidden[i] = false
+	}
+	m.scrollToColumn()
+	m.refreshTable()
+}
+
+// refreshTable sets the table columns to the visible columns and the table
+// rows to the displayed records (filteredIdx) around the cursor, keeping the
+// cursor position.
+func (m *Model) refreshTable() {
+	m.showRows(m.cursor())
+}
+
+// showRows sets the table columns to the visible columns and the table rows
+// to the window of displayed records (filteredIdx) around position pos, with
+// selection markers, and moves the cursor to pos.
+// The current column title is marked with "›" and the sorted column with its
+// sort direction.
+func (m *Model) showRows(pos int) {
+	visible := m.visibleColumns()
+
+	columns := make([]table.Column, 0, len(visible)+1)
+	columns = append(columns, table.Column{Title: " ", Width: markerColumnWidth})
+	for _, i := range visible {
+		title := formatHeaderTitle(m.headers[i])
+		if m.sortOrder != sortNone && i == m.sortCol {
+			title += m.sortOrder.arrow()
+		}
+		if i == m.column {
+			title = "›" + title
+		}
+		columns = append(columns, table.Column{Title: title, Width: m.widths[i]})
+	}
+
+	pos = max(0, min(pos, len(m.filteredIdx)-1))
+	m.rowOffset = windowOffset(m.rowOffset, pos, len(m.filteredIdx), m.rowWindow(), m.table.Height())
+	window := m.filteredIdx[m.rowOffset:min(len(m.filteredIdx), m.rowOffset+m.rowWindow())]
+
+	rows := make([]table.Row, len(window))
+	for j, idx := range window {
+		src := m.allRows[idx]
+		row := make(table.Row, len(columns))
+		row[0] = " "
+		if m.selected[idx] {
+			row[0] = "✓"
+		}
+		for k, i := range visible {
+			if i+1 < len(src) {
+				row[k+1] = src[i+1]
+			}
+		}
+		rows[j] = row
+	}
+
+	// The table renders rows against the current columns, so clear the rows
+	// before the number of columns changes.
+	m.table.SetRows(nil)
+	m.table.SetColumns(columns)
+	m.table.SetRows(rows)
+	if len(rows) > 0 {
+		m.table.SetCursor(pos - m.rowOffset)
+	}
+}
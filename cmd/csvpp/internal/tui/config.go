@@ -0,0 +1,110 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/goccy/go-yaml"
+)
+
+// Config is the configuration file of the TUI, a YAML file such as:
+//
+//	theme: high-contrast
+//	keys:
+//	  quit: [q, ctrl+q]
+//	  select: [space, v]
+//	  details: [enter, tab]
+//
+// Keys maps action names to the keys that trigger them, replacing the
+// default keys of the action. An empty list disables the action.
+type Config struct {
+	Theme string              `yaml:"theme"`
+	Keys  map[string][]string `yaml:"keys"`
+}
+
+// ConfigPath returns the path of the configuration file:
+// $XDG_CONFIG_HOME/csvpp/config.yaml, or ~/.config/csvpp/config.yaml if
+// XDG_CONFIG_HOME is not set.
+func ConfigPath() (string, error) {
+	xdg := os.Getenv("XDG_CONFIG_HOME")
+	var home string
+	if !filepath.IsAbs(xdg) {
+		var err error
+		if home, err = os.UserHomeDir(); err != nil {
+			return "", err
+		}
+	}
+	return configPath(xdg, home), nil
+}
+
+// configPath returns the path of the configuration file for the given
+// XDG_CONFIG_HOME and home directory. A relative XDG_CONFIG_HOME is ignored,
+// as the XDG Base Directory Specification requires.
+func configPath(xdg, home string) string {
+	if !filepath.IsAbs(xdg) {
+		xdg = filepath.Join(home, ".config")
+	}
+	return filepath.Join(xdg, "csvpp", "config.yaml")
+}
+
+// LoadConfig reads and checks the configuration file at path. A missing file
+// is an empty configuration.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // the configuration file of the user
+	if errors.Is(err, fs.ErrNotExist) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var c Config
+	if err := yaml.UnmarshalWithOptions(data, &c, yaml.DisallowUnknownField()); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if _, err := ThemeStyles(c.Theme); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if _, err := c.KeyMap(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &c, nil
+}
+
+// KeyMap returns the default key bindings with the keys of the configuration
+// applied. A key may only trigger one action, and ctrl+c always quits.
+func (c *Config) KeyMap() (KeyMap, error) {
+	k := DefaultKeyMap()
+	actions := k.actions()
+
+	names := make([]string, 0, len(c.Keys))
+	for name := range c.Keys {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		i := slices.IndexFunc(actions, func(a keyAction) bool { return a.name == name })
+		if i < 0 {
+			return KeyMap{}, fmt.Errorf("unknown key action %q", name)
+		}
+		rebind(actions[i].binding, c.Keys[name])
+	}
+
+	owners := make(map[string]string)
+	for _, a := range actions {
+		for _, key := range a.binding.Keys() {
+			if key == "ctrl+c" {
+				return KeyMap{}, fmt.Errorf("key %q of %s is reserved for quitting", key, a.name)
+			}
+			if owner, ok := owners[key]; ok {
+				return KeyMap{}, fmt.Errorf("key %q is bound to both %s and %s", key, owner, a.name)
+			}
+			owners[key] = a.name
+		}
+	}
+	return k, nil
+}
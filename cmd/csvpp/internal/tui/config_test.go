@@ -0,0 +1,175 @@
+package tui_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/osamingo/go-csvpp"
+	"github.com/osamingo/go-csvpp/cmd/csvpp/internal/tui"
+)
+
+func TestConfigPath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		xdg  string
+		want string
+	}{
+		{name: "success: XDG_CONFIG_HOME", xdg: "/xdg", want: "/xdg/csvpp/config.yaml"},
+		{name: "success: XDG_CONFIG_HOME not set", xdg: "", want: "/home/user/.config/csvpp/config.yaml"},
+		{name: "success: relative XDG_CONFIG_HOME is ignored", xdg: "xdg", want: "/home/user/.config/csvpp/config.yaml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tui.ConfigPathFor(tt.xdg, "/home/user"); got != filepath.FromSlash(tt.want) {
+				t.Errorf("configPath(%q) = %q, want %q", tt.xdg, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		config    string // file content, no file if empty
+		wantTheme string
+		wantKeys  map[string][]string // keys of some actions after loading
+		wantErr   string
+	}{
+		{
+			name:     "success: missing file uses the defaults",
+			wantKeys: map[string][]string{"quit": {"q"}, "select": {" "}, "page-down": {"f", "pgdown"}},
+		},
+		{
+			name:      "success: theme and keys",
+			config:    "theme: high-contrast\nkeys:\n  quit: [x, ctrl+q]\n  hide: [H]\n  select: [space, v]\n",
+			wantTheme: "high-contrast",
+			wantKeys:  map[string][]string{"quit": {"x", "ctrl+q"}, "hide": {"H"}, "select": {" ", "v"}, "copy": {"y", "c"}},
+		},
+		{
+			name:     "success: empty list disables an action",
+			config:   "keys:\n  delete: []\n",
+			wantKeys: map[string][]string{"delete": nil},
+		},
+		{
+			name:    "error: unknown theme",
+			config:  "theme: neon\n",
+			wantErr: `unknown theme "neon"`,
+		},
+		{
+			name:    "error: unknown action",
+			config:  "keys:\n  jump: [J]\n",
+			wantErr: `unknown key action "jump"`,
+		},
+		{
+			name:    "error: key bound twice",
+			config:  "keys:\n  quit: [x]\n",
+			wantErr: `key "x" is bound to both hide and quit`,
+		},
+		{
+			name:    "error: ctrl+c is reserved",
+			config:  "keys:\n  help: [ctrl+c]\n",
+			wantErr: `key "ctrl+c" of help is reserved for quitting`,
+		},
+		{
+			name:    "error: unknown field",
+			config:  "colors: dark\n",
+			wantErr: "colors",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if tt.config != "" {
+				if err := os.WriteFile(path, []byte(tt.config), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			cfg, err := tui.LoadConfig(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadConfig() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadConfig() error = %v", err)
+			}
+			if cfg.Theme != tt.wantTheme {
+				t.Errorf("Theme = %q, want %q", cfg.Theme, tt.wantTheme)
+			}
+
+			k, err := cfg.KeyMap()
+			if err != nil {
+				t.Fatalf("KeyMap() error = %v", err)
+			}
+			got := tui.KeyMapKeys(k)
+			for action, want := range tt.wantKeys {
+				if diff := cmp.Diff(want, got[action]); diff != "" {
+					t.Errorf("keys of %s mismatch (-want +got):\n%s", action, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestModel_KeyMap(t *testing.T) {
+	t.Parallel()
+
+	headers := []*csvpp.ColumnHeader{{Name: "name", Kind: csvpp.SimpleField}}
+	records := [][]*csvpp.Field{{{Value: "Alice"}}, {{Value: "Bob"}}}
+
+	cfg := &tui.Config{Keys: map[string][]string{
+		"quit":   {"x"},
+		"hide":   {"H"},
+		"select": {"v"},
+	}}
+	k, err := cfg.KeyMap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	styles, err := tui.ThemeStyles(tui.ThemeNoColor)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var m tea.Model = tui.NewModel(headers, records, tui.WithKeyMap(k), tui.WithStyles(styles))
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 200, Height: 20})
+	if view := m.View(); !strings.Contains(view, "x quit") || strings.Contains(view, "column left") {
+		t.Errorf("View() does not show the short help with the configured keys:\n%s", view)
+	}
+
+	for _, key := range []string{" ", "v", "q"} {
+		var cmd tea.Cmd
+		if m, cmd = m.Update(keyMsg(key)); cmd != nil {
+			t.Fatalf("key %q returned a command", key)
+		}
+	}
+	if view := m.View(); !strings.Contains(view, "1 selected") {
+		t.Errorf("View() does not show the row selected with v:\n%s", view)
+	}
+
+	m, _ = m.Update(keyMsg("?"))
+	if view := m.View(); !strings.Contains(view, "column left") || !strings.Contains(view, "H   hide column") || !strings.Contains(view, "fewer keys") {
+		t.Errorf("View() does not show the full help:\n%s", view)
+	}
+
+	if _, cmd := m.Update(keyMsg("x")); cmd == nil {
+		t.Error("x did not quit")
+	}
+}
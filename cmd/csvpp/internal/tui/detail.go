@@ -0,0 +1,336 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/osamingo/go-csvpp"
+)
+
+// detailNode is a node of the record tree shown in the detail view.
+type detailNode struct {
+	label    string // field name or element index, such as "geo" or "[0]"
+	value    string // value of leaf nodes
+	path     string // field path in the syntax of csvpp.Record.Get, such as "address[0].city"
+	branch   bool   // structured value or array, even if it has no children
+	expanded bool
+	children []*detailNode
+
+	set func(value string) // sets the value of a leaf in the record
+}
+
+// detailNodes builds the tree nodes for the fields of a record or of a
+// structured value. prefix is the path of the enclosing value, and slot
+// returns field j of the value, creating it if it is missing.
+func detailNodes(headers []*csvpp.ColumnHeader, fields []*csvpp.Field, prefix string, slot func(j int) *csvpp.Field) []*detailNode {
+	nodes := make([]*detailNode, 0, len(headers))
+	for i, h := range headers {
+		var f *csvpp.Field
+		if i < len(fields) {
+			f = fields[i]
+		}
+		path := h.Name
+		if prefix != "" {
+			path = prefix + "." + h.Name
+		}
+		nodes = append(nodes, newDetailNode(h, f, path, func() *csvpp.Field { return slot(i) }))
+	}
+	return nodes
+}
+
+// fieldSlot returns (*fields)[j], growing the slice and replacing nil fields
+// with empty ones as needed.
+func fieldSlot(fields *[]*csvpp.Field, j int) *csvpp.Field {
+	for len(*fields) <= j {
+		*fields = append(*fields, &csvpp.Field{})
+	}
+	if (*fields)[j] == nil {
+		(*fields)[j] = &csvpp.Field{}
+	}
+	return (*fields)[j]
+}
+
+// newDetailNode builds the tree node of field f described by h. get returns
+// the field in the record, creating it if f is nil.
+// Structured values and arrays start expanded.
+func newDetailNode(h *csvpp.ColumnHeader, f *csvpp.Field, path string, get func() *csvpp.Field) *detailNode {
+	if f == nil {
+		f = &csvpp.Field{}
+	}
+
+	n := &detailNode{label: h.Name, path: path}
+	switch h.Kind {
+	case csvpp.ArrayField:
+		n.label += "[]"
+		n.branch, n.expanded = true, true
+		for i, v := range f.Values {
+			n.children = append(n.children, &detailNode{
+				label: fmt.Sprintf("[%d]", i),
+				value: v,
+				path:  fmt.Sprintf("%s[%d]", path, i),
+				set:   func(value string) { get().Values[i] = value },
+			})
+		}
+	case csvpp.StructuredField:
+		n.branch, n.expanded = true, true
+		n.children = detailNodes(h.Components, f.Components, path, func(j int) *csvpp.Field {
+			return fieldSlot(&get().Components, j)
+		})
+	case csvpp.ArrayStructuredField:
+		n.label += "[]"
+		n.branch, n.expanded = true, true
+		for i, elem := range f.Components {
+			var components []*csvpp.Field
+			if elem != nil {
+				components = elem.Components
+			}
+			elemPath := fmt.Sprintf("%s[%d]", path, i)
+			elem := func() *csvpp.Field { return fieldSlot(&get().Components, i) }
+			n.children = append(n.children, &detailNode{
+				label:    fmt.Sprintf("[%d]", i),
+				path:     elemPath,
+				branch:   true,
+				expanded: true,
+				children: detailNodes(h.Components, components, elemPath, func(j int) *csvpp.Field {
+					return fieldSlot(&elem().Components, j)
+				}),
+			})
+		}
+	default:
+		n.value = f.Value
+		n.set = func(value string) { get().Value = value }
+	}
+	return n
+}
+
+// detailLine is a visible line of the detail view.
+type detailLine struct {
+	node  *detailNode
+	depth int
+}
+
+// text returns the line without styling.
+func (l detailLine) text() string {
+	indent := strings.Repeat("  ", l.depth)
+	n := l.node
+	if !n.branch {
+		return fmt.Sprintf("%s  %s: %s", indent, n.label, n.value)
+	}
+
+	marker := "▸"
+	if n.expanded {
+		marker = "▾"
+	}
+	if strings.HasSuffix(n.label, "[]") {
+		return fmt.Sprintf("%s%s %s (%d)", indent, marker, n.label, len(n.children))
+	}
+	return fmt.Sprintf("%s%s %s", indent, marker, n.label)
+}
+
+// detailView shows one record as a tree of components and array elements.
+type detailView struct {
+	record int // original record index
+	nodes  []*detailNode
+	cursor int    // index into lines()
+	offset int    // first line shown
+	copied string // status message after copying
+}
+
+// newDetailView returns the detail view of records[index]. Edits of leaves
+// are made in records.
+func newDetailView(headers []*csvpp.ColumnHeader, records [][]*csvpp.Field, index int) *detailView {
+	slot := func(j int) *csvpp.Field { return fieldSlot(&records[index], j) }
+	return &detailView{record: index, nodes: detailNodes(headers, records[index], "", slot)}
+}
+
+// lines returns the visible lines: every node whose ancestors are expanded.
+func (d *detailView) lines() []detailLine {
+	var lines []detailLine
+	var walk func(nodes []*detailNode, depth int)
+	walk = func(nodes []*detailNode, depth int) {
+		for _, n := range nodes {
+			lines = append(lines, detailLine{node: n, depth: depth})
+			if n.expanded {
+				walk(n.children, depth+1)
+			}
+		}
+	}
+	walk(d.nodes, 0)
+	return lines
+}
+
+// current returns the line under the cursor.
+func (d *detailView) current() (detailLine, bool) {
+	lines := d.lines()
+	if d.cursor < 0 || d.cursor >= len(lines) {
+		return detailLine{}, false
+	}
+	return lines[d.cursor], true
+}
+
+// moveTo moves the cursor to line i, scrolling so that it stays within height lines.
+func (d *detailView) moveTo(i, height int) {
+	n := len(d.lines())
+	d.cursor = max(0, min(i, n-1))
+	if d.cursor < d.offset {
+		d.offset = d.cursor
+	}
+	if d.cursor >= d.offset+height {
+		d.offset = d.cursor - height + 1
+	}
+	d.offset = max(0, min(d.offset, n-height))
+}
+
+// expand expands the node under the cursor.
+func (d *detailView) expand() {
+	if l, ok := d.current(); ok && l.node.branch {
+		l.node.expanded = true
+	}
+}
+
+// collapse collapses the node under the cursor, or moves the cursor to the
+// parent if the node is a leaf or already collapsed.
+func (d *detailView) collapse(height int) {
+	l, ok := d.current()
+	if !ok {
+		return
+	}
+	if l.node.branch && l.node.expanded {
+		l.node.expanded = false
+		d.moveTo(d.cursor, height)
+		return
+	}
+	lines := d.lines()
+	for i := d.cursor - 1; i >= 0; i-- {
+		if lines[i].depth < l.depth {
+			d.moveTo(i, height)
+			return
+		}
+	}
+}
+
+// setExpanded expands or collapses every node.
+func (d *detailView) setExpanded(expanded bool, height int) {
+	var walk func(nodes []*detailNode)
+	walk = func(nodes []*detailNode) {
+		for _, n := range nodes {
+			if n.branch {
+				n.expanded = expanded
+			}
+			walk(n.children)
+		}
+	}
+	walk(d.nodes)
+	d.moveTo(d.cursor, height)
+}
+
+// detailHeight returns the number of tree lines that fit on the screen.
+func (m *Model) detailHeight() int {
+	if m.height <= 0 {
+		return 10 // same as the initial table height
+	}
+	return max(1, m.height-4) // leave room for status
+}
+
+// openDetail shows the detail view of the record under the cursor.
+func (m *Model) openDetail() {
+	origIdx := m.originalIndex()
+	if origIdx < 0 {
+		return
+	}
+	m.detail = newDetailView(m.headers, m.records, origIdx)
+}
+
+// updateDetailMode handles key events when the detail view is shown.
+func (m Model) updateDetailMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) { //nostyle:recvtype
+	d := m.detail
+	height := m.detailHeight()
+	d.copied = ""
+
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "q", "esc", "backspace":
+		m.detail = nil
+	case "up", "k":
+		d.moveTo(d.cursor-1, height)
+	case "down", "j":
+		d.moveTo(d.cursor+1, height)
+	case "pgup", "b":
+		d.moveTo(d.cursor-height, height)
+	case "pgdown", "f":
+		d.moveTo(d.cursor+height, height)
+	case "home", "g":
+		d.moveTo(0, height)
+	case "end", "G":
+		d.moveTo(len(d.lines())-1, height)
+	case "right", "l":
+		d.expand()
+	case "left", "h":
+		d.collapse(height)
+	case "enter", " ":
+		if l, ok := d.current(); ok && !l.node.branch && msg.String() == "enter" {
+			return m, m.startDetailEdit(l.node)
+		}
+		if l, ok := d.current(); ok && l.node.expanded {
+			d.collapse(height)
+		} else {
+			d.expand()
+		}
+	case "e":
+		d.setExpanded(true, height)
+	case "E":
+		d.setExpanded(false, height)
+	case "y":
+		if l, ok := d.current(); ok && m.writeClipboard([]byte(l.node.path)) {
+			d.copied = "Copied path " + l.node.path
+		}
+	case "c":
+		if l, ok := d.current(); ok && !l.node.branch && m.writeClipboard([]byte(l.node.value)) {
+			d.copied = "Copied value of " + l.node.path
+		}
+	}
+	return m, nil
+}
+
+// detailViewString renders the detail view.
+func (m Model) detailViewString() string { //nostyle:recvtype
+	d := m.detail
+	height := m.detailHeight()
+
+	var b strings.Builder
+	b.WriteString(m.styles.Header.Render(fmt.Sprintf("Record %d of %d", d.record+1, len(m.records))))
+	b.WriteString("\n")
+
+	lines := d.lines()
+	end := min(d.offset+height, len(lines))
+	for i := d.offset; i < end; i++ {
+		text := lines[i].text()
+		if i == d.cursor {
+			b.WriteString(m.styles.Selected.Render(text))
+		} else {
+			b.WriteString(m.styles.Cell.Render(text))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	status := ""
+	if l, ok := d.current(); ok {
+		status = l.node.path
+	}
+	if d.copied != "" {
+		status += " | " + d.copied + "!"
+	}
+	b.WriteString(m.styles.Status.Render(status))
+	b.WriteString("\n")
+	if m.editing {
+		b.WriteString(m.editView())
+		return b.String()
+	}
+	b.WriteString(m.styles.Help.Render("↑/↓: navigate • ←/→: collapse/expand • e/E: expand/collapse all • Enter: edit value • y: copy path • c: copy value • Esc: back"))
+
+	return b.String()
+}
@@ -0,0 +1,460 @@
+package tui
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/osamingo/go-csvpp"
+)
+
+// diffContext is the number of unchanged lines shown around each change in
+// the save confirmation.
+const diffContext = 2
+
+// editTarget is the value being edited.
+type editTarget struct {
+	record int         // original record index
+	column int         // column of a table cell edit
+	node   *detailNode // leaf of a detail view edit, nil for a table cell edit
+}
+
+// saveConfirm is a pending save waiting for confirmation.
+type saveConfirm struct {
+	diff   []string // unified diff of the file
+	offset int      // first diff line shown
+}
+
+// fieldText returns the text of field f described by h as it appears in a
+// CSV++ cell, such as "a~b" for an array.
+func fieldText(h *csvpp.ColumnHeader, f *csvpp.Field) (string, error) {
+	var buf bytes.Buffer
+	w := csvpp.NewWriter(&buf)
+	w.SetHeaders([]*csvpp.ColumnHeader{h})
+	if err := w.Write([]*csvpp.Field{f, nil}); err != nil {
+		return "", err
+	}
+	w.Flush()
+
+	// The empty second cell keeps the line from being empty.
+	row, err := csv.NewReader(&buf).Read()
+	if err != nil {
+		return "", err
+	}
+	return row[0], nil
+}
+
+// parseFieldText parses the text of a CSV++ cell described by h.
+func parseFieldText(h *csvpp.ColumnHeader, text string) (*csvpp.Field, error) {
+	var buf bytes.Buffer
+	w := csvpp.NewWriter(&buf)
+	w.SetHeaders([]*csvpp.ColumnHeader{h, {Name: h.Name + "_", Kind: csvpp.SimpleField}})
+	if err := w.WriteHeader(); err != nil {
+		return nil, err
+	}
+	w.Flush()
+	cw := csv.NewWriter(&buf)
+	if err := cw.Write([]string{text, ""}); err != nil {
+		return nil, err
+	}
+	cw.Flush()
+
+	record, err := csvpp.NewReader(&buf).Read()
+	if err != nil {
+		var perr *csvpp.ParseError
+		if errors.As(err, &perr) {
+			return nil, perr.Err
+		}
+		return nil, err
+	}
+	if err := checkComponents(h, record[0]); err != nil {
+		return nil, err
+	}
+	return record[0], nil
+}
+
+// checkComponents returns an error if a structured value of f has more
+// components than h describes.
+func checkComponents(h *csvpp.ColumnHeader, f *csvpp.Field) error {
+	var values []*csvpp.Field
+	switch h.Kind {
+	case csvpp.StructuredField:
+		values = []*csvpp.Field{f}
+	case csvpp.ArrayStructuredField:
+		values = f.Components
+	default:
+		return nil
+	}
+	for _, v := range values {
+		if len(v.Components) > len(h.Components) {
+			return fmt.Errorf("%s has %d components, got %d", h.Name, len(h.Components), len(v.Components))
+		}
+		for i, c := range v.Components {
+			if err := checkComponents(h.Components[i], c); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// cloneRecords returns a deep copy of records.
+func cloneRecords(records [][]*csvpp.Field) [][]*csvpp.Field {
+	out := make([][]*csvpp.Field, len(records))
+	for i, record := range records {
+		out[i] = cloneFields(record)
+	}
+	return out
+}
+
+// cloneFields returns a deep copy of fields.
+func cloneFields(fields []*csvpp.Field) []*csvpp.Field {
+	if fields == nil {
+		return nil
+	}
+	out := make([]*csvpp.Field, len(fields))
+	for i, f := range fields {
+		if f == nil {
+			continue
+		}
+		out[i] = &csvpp.Field{
+			Value:      f.Value,
+			Values:     slices.Clone(f.Values),
+			Components: cloneFields(f.Components),
+		}
+	}
+	return out
+}
+
+// formatCSVPP returns the lines of the CSV++ file holding headers and records.
+func formatCSVPP(headers []*csvpp.ColumnHeader, records [][]*csvpp.Field) ([]string, error) {
+	var buf bytes.Buffer
+	w := csvpp.NewWriter(&buf)
+	w.SetHeaders(headers)
+	if err := w.WriteAll(records); err != nil {
+		return nil, err
+	}
+	return strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n"), nil
+}
+
+// snapshot keeps a copy of the records before the first unsaved change, so
+// the changes can be shown before saving.
+func (m *Model) snapshot() {
+	if m.original == nil {
+		m.original = cloneRecords(m.records)
+	}
+}
+
+// recordRow returns the table row of record number idx.
+func (m *Model) recordRow(idx int) table.Row {
+	record := m.records[idx]
+	row := make(table.Row, len(record)+1)
+	row[0] = " " // Selection marker
+	for j, field := range record {
+		var header *csvpp.ColumnHeader
+		if j < len(m.headers) {
+			header = m.headers[j]
+		}
+		row[j+1] = formatFieldValue(header, field)
+	}
+	return row
+}
+
+// recordChanged updates the display after record idx was edited.
+func (m *Model) recordChanged(idx int) {
+	m.allRows[idx] = m.recordRow(idx)
+	m.dirty = true
+	m.refreshRows(idx)
+}
+
+// startEdit begins editing the current cell.
+func (m *Model) startEdit() tea.Cmd {
+	idx := m.originalIndex()
+	if idx < 0 || m.column >= len(m.headers) {
+		return nil
+	}
+	var f *csvpp.Field
+	if m.column < len(m.records[idx]) {
+		f = m.records[idx][m.column]
+	}
+	text, err := fieldText(m.headers[m.column], f)
+	if err != nil {
+		m.notice = fmt.Sprintf("cannot edit: %v", err)
+		return nil
+	}
+	m.edit = editTarget{record: idx, column: m.column}
+	return m.beginEditing(text)
+}
+
+// startDetailEdit begins editing a leaf of the detail view.
+func (m *Model) startDetailEdit(n *detailNode) tea.Cmd {
+	if n.set == nil {
+		return nil
+	}
+	m.edit = editTarget{record: m.detail.record, node: n}
+	return m.beginEditing(n.value)
+}
+
+// beginEditing shows the edit input with text.
+func (m *Model) beginEditing(text string) tea.Cmd {
+	m.editing = true
+	m.editInput.SetValue(text)
+	m.editInput.CursorEnd()
+	m.table.Blur()
+	return m.editInput.Focus()
+}
+
+// endEditing hides the edit input.
+func (m *Model) endEditing() {
+	m.editing = false
+	m.editInput.Blur()
+	m.table.Focus()
+}
+
+// updateEditMode handles key events when the edit input is active.
+func (m Model) updateEditMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) { //nostyle:recvtype
+	switch msg.Type {
+	case tea.KeyEnter:
+		if err := m.commitEdit(); err != nil {
+			m.notice = fmt.Sprintf("invalid value: %v", err)
+			return m, nil
+		}
+		m.endEditing()
+		return m, nil
+	case tea.KeyEsc:
+		m.endEditing()
+		return m, nil
+	default:
+		var cmd tea.Cmd
+		m.editInput, cmd = m.editInput.Update(msg)
+		return m, cmd
+	}
+}
+
+// commitEdit stores the edited value in the record.
+func (m *Model) commitEdit() error {
+	value := m.editInput.Value()
+	idx := m.edit.record
+
+	if n := m.edit.node; n != nil {
+		m.snapshot()
+		n.set(value)
+		n.value = value
+		m.recordChanged(idx)
+		return nil
+	}
+
+	f, err := parseFieldText(m.headers[m.edit.column], value)
+	if err != nil {
+		return err
+	}
+	m.snapshot()
+	*fieldSlot(&m.records[idx], m.edit.column) = *f
+	m.recordChanged(idx)
+	return nil
+}
+
+// insertRecord inserts an empty record after the record under the cursor.
+func (m *Model) insertRecord() {
+	m.snapshot()
+	pos := m.originalIndex() + 1 // 0 if there are no records
+	record := make([]*csvpp.Field, len(m.headers))
+	for i := range record {
+		record[i] = &csvpp.Field{}
+	}
+	m.records = slices.Insert(m.records, pos, record)
+	m.allRows = slices.Insert(m.allRows, pos, m.recordRow(pos))
+	m.shiftSelection(pos, 1)
+	m.dirty = true
+	m.refreshRows(pos)
+	m.notice = fmt.Sprintf("inserted record %d", pos+1)
+}
+
+// deleteRecord deletes the record under the cursor.
+func (m *Model) deleteRecord() {
+	idx := m.originalIndex()
+	if idx < 0 {
+		return
+	}
+	m.snapshot()
+	cursor := m.cursor()
+	m.records = slices.Delete(m.records, idx, idx+1)
+	m.allRows = slices.Delete(m.allRows, idx, idx+1)
+	delete(m.selected, idx)
+	m.shiftSelection(idx+1, -1)
+	m.dirty = true
+	m.refreshRows(-1)
+	if len(m.filteredIdx) > 0 {
+		m.setCursor(min(cursor, len(m.filteredIdx)-1))
+	}
+	m.notice = fmt.Sprintf("deleted record %d", idx+1)
+}
+
+// shiftSelection moves the selection of records from index from on by delta.
+func (m *Model) shiftSelection(from, delta int) {
+	selected := make(map[int]bool, len(m.selected))
+	for idx := range m.selected {
+		if idx >= from {
+			idx += delta
+		}
+		selected[idx] = true
+	}
+	m.selected = selected
+}
+
+// requestSave shows the diff of the unsaved changes for confirmation.
+func (m *Model) requestSave() {
+	switch {
+	case m.savePath == "":
+		m.notice = "cannot save: input was not read from a file"
+		return
+	case m.follow:
+		m.notice = "cannot save while following the file"
+		return
+	case m.loader != nil:
+		m.notice = "cannot save until the file is fully loaded"
+		return
+	case !m.dirty || m.original == nil:
+		m.notice = "no changes to save"
+		return
+	}
+
+	before, err := formatCSVPP(m.headers, m.original)
+	if err != nil {
+		m.notice = fmt.Sprintf("cannot save: %v", err)
+		return
+	}
+	after, err := formatCSVPP(m.headers, m.records)
+	if err != nil {
+		m.notice = fmt.Sprintf("cannot save: %v", err)
+		return
+	}
+	diff := unifiedDiff(diffLines(before, after), diffContext)
+	if len(diff) == 0 {
+		m.original = nil
+		m.dirty = false
+		m.notice = "no changes to save"
+		return
+	}
+	m.confirm = &saveConfirm{diff: diff}
+}
+
+// save writes the records to the save path with the original headers,
+// replacing the file only after the new content was written completely.
+func (m *Model) save() error {
+	info, err := os.Stat(m.savePath)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(m.savePath), "."+filepath.Base(m.savePath)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck // no-op after a successful rename
+
+	w := csvpp.NewWriter(tmp)
+	w.SetHeaders(m.headers)
+	if err := w.WriteAll(m.records); err != nil {
+		tmp.Close() //nolint:errcheck,gosec // the write error is returned
+		return err
+	}
+	if err := tmp.Chmod(info.Mode().Perm()); err != nil {
+		tmp.Close() //nolint:errcheck,gosec // the chmod error is returned
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), m.savePath); err != nil {
+		return err
+	}
+
+	m.original = nil
+	m.dirty = false
+	return nil
+}
+
+// updateConfirmMode handles key events while the save confirmation is shown.
+func (m Model) updateConfirmMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) { //nostyle:recvtype
+	c := m.confirm
+	height := m.detailHeight()
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "y", "Y":
+		m.confirm = nil
+		if err := m.save(); err != nil {
+			m.notice = fmt.Sprintf("save failed: %v", err)
+		} else {
+			m.notice = "saved " + m.savePath
+		}
+	case "n", "N", "esc", "q":
+		m.confirm = nil
+		m.notice = "save cancelled"
+	case "up", "k":
+		c.offset = max(0, c.offset-1)
+	case "down", "j":
+		c.offset = max(0, min(c.offset+1, len(c.diff)-height))
+	case "pgup", "b":
+		c.offset = max(0, c.offset-height)
+	case "pgdown", "f":
+		c.offset = max(0, min(c.offset+height, len(c.diff)-height))
+	}
+	return m, nil
+}
+
+// confirmView renders the save confirmation.
+func (m Model) confirmView() string { //nostyle:recvtype
+	c := m.confirm
+	height := m.detailHeight()
+
+	var b strings.Builder
+	b.WriteString(m.styles.Header.Render("Save changes to " + m.savePath + "?"))
+	b.WriteString("\n")
+	for _, line := range c.diff[c.offset:min(c.offset+height, len(c.diff))] {
+		switch line[0] {
+		case '+':
+			b.WriteString(m.styles.DiffAdded.Render(line))
+		case '-':
+			b.WriteString(m.styles.DiffRemoved.Render(line))
+		case '@':
+			b.WriteString(m.styles.Help.Render(line))
+		default:
+			b.WriteString(line)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(m.styles.Help.Render("y: save • n/Esc: cancel • ↑/↓: scroll"))
+	return b.String()
+}
+
+// editView renders the edit input.
+func (m Model) editView() string { //nostyle:recvtype
+	label := "edit"
+	if n := m.edit.node; n != nil {
+		label += " " + n.path
+	} else if m.edit.column < len(m.headers) {
+		label += " " + formatHeaderTitle(m.headers[m.edit.column])
+	}
+
+	var b strings.Builder
+	b.WriteString(m.styles.FilterPrompt.Render(label + ": "))
+	b.WriteString(m.editInput.View())
+	b.WriteString("\n")
+	if m.notice != "" {
+		b.WriteString(m.styles.Status.Render(m.notice))
+		b.WriteString("\n")
+	}
+	b.WriteString(m.styles.Help.Render("Enter: apply • Esc: cancel"))
+	return b.String()
+}
@@ -0,0 +1,276 @@
+package tui_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/osamingo/go-csvpp"
+	"github.com/osamingo/go-csvpp/cmd/csvpp/internal/tui"
+)
+
+func TestFieldText(t *testing.T) {
+	t.Parallel()
+
+	headers, err := csvpp.NewReader(strings.NewReader("name,tags[],geo(lat^lon),address[](city^zip)\n")).Headers()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		header  *csvpp.ColumnHeader
+		text    string
+		want    *csvpp.Field
+		wantErr bool
+	}{
+		{
+			name:   "success: simple value with comma and quote",
+			header: headers[0],
+			text:   `a,"b"`,
+			want:   &csvpp.Field{Value: `a,"b"`},
+		},
+		{
+			name:   "success: empty simple value",
+			header: headers[0],
+			text:   "",
+			want:   &csvpp.Field{},
+		},
+		{
+			name:   "success: array",
+			header: headers[1],
+			text:   "go~rust",
+			want:   &csvpp.Field{Values: []string{"go", "rust"}},
+		},
+		{
+			name:   "success: structured",
+			header: headers[2],
+			text:   "35.6^139.7",
+			want:   &csvpp.Field{Components: []*csvpp.Field{{Value: "35.6"}, {Value: "139.7"}}},
+		},
+		{
+			name:    "error: too many components",
+			header:  headers[2],
+			text:    "1^2^3",
+			wantErr: true,
+		},
+		{
+			name:   "success: array structured",
+			header: headers[3],
+			text:   "Tokyo^100~Osaka^530",
+			want: &csvpp.Field{Components: []*csvpp.Field{
+				{Components: []*csvpp.Field{{Value: "Tokyo"}, {Value: "100"}}},
+				{Components: []*csvpp.Field{{Value: "Osaka"}, {Value: "530"}}},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tui.ParseFieldText(tt.header, tt.text)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFieldText() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ParseFieldText() mismatch (-want +got):\n%s", diff)
+			}
+
+			text, err := tui.FieldText(tt.header, got)
+			if err != nil {
+				t.Fatalf("FieldText() error = %v", err)
+			}
+			if text != tt.text {
+				t.Errorf("FieldText() = %q, want %q", text, tt.text)
+			}
+		})
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		a, b []string
+		want []string
+	}{
+		{
+			name: "success: no changes",
+			a:    []string{"h", "1", "2"},
+			b:    []string{"h", "1", "2"},
+			want: nil,
+		},
+		{
+			name: "success: changed line with context",
+			a:    []string{"h", "1", "2", "3", "4", "5", "6"},
+			b:    []string{"h", "1", "2", "3", "four", "5", "6"},
+			want: []string{"@@ -4,3 +4,3 @@", " 3", "-4", "+four", " 5"},
+		},
+		{
+			name: "success: insert and delete in separate hunks",
+			a:    []string{"h", "1", "2", "3", "4", "5", "6", "7"},
+			b:    []string{"h", "0", "1", "2", "3", "4", "5", "7"},
+			want: []string{"@@ -1,2 +1,3 @@", " h", "+0", " 1", "@@ -6,3 +7,2 @@", " 5", "-6", " 7"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := tui.UnifiedDiff(tt.a, tt.b, 1)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("UnifiedDiff() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestModel_Edit(t *testing.T) {
+	t.Parallel()
+
+	const input = "name,tags[],geo(lat^lon)\nAlice,go~rust,35.6^139.7\nBob,,\n"
+
+	tests := []struct {
+		name     string
+		keys     []string
+		wantFile string
+		wantView string
+	}{
+		{
+			name:     "success: edit a simple cell",
+			keys:     []string{"e", "ctrl+u", "Carol", "enter", "w", "y"},
+			wantFile: "name,tags[],geo(lat^lon)\nCarol,go~rust,35.6^139.7\nBob,,\n",
+			wantView: "saved",
+		},
+		{
+			name:     "success: edit an array cell",
+			keys:     []string{"l", "e", "~zig", "enter", "w", "y"},
+			wantFile: "name,tags[],geo(lat^lon)\nAlice,go~rust~zig,35.6^139.7\nBob,,\n",
+		},
+		{
+			name:     "success: edit a component in the detail view",
+			keys:     []string{"j", "enter", "j", "j", "j", "enter", "1.5", "enter", "esc", "w", "y"},
+			wantFile: "name,tags[],geo(lat^lon)\nAlice,go~rust,35.6^139.7\nBob,,1.5\n",
+		},
+		{
+			name:     "success: add and delete rows",
+			keys:     []string{"D", "o", "e", "Dave", "enter", "w", "y"},
+			wantFile: "name,tags[],geo(lat^lon)\nBob,,\nDave,,\n",
+		},
+		{
+			name:     "success: save cancelled",
+			keys:     []string{"D", "w", "n"},
+			wantFile: input,
+			wantView: "save cancelled",
+		},
+		{
+			name:     "success: escape discards the edit",
+			keys:     []string{"e", "X", "esc", "w"},
+			wantFile: input,
+			wantView: "no changes to save",
+		},
+		{
+			name:     "error: invalid value keeps editing",
+			keys:     []string{"l", "l", "e", "^0", "enter"},
+			wantFile: input,
+			wantView: "invalid value",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "data.csvpp")
+			if err := os.WriteFile(path, []byte(input), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			r := csvpp.NewReader(strings.NewReader(input))
+			headers, err := r.Headers()
+			if err != nil {
+				t.Fatal(err)
+			}
+			records, err := r.ReadAll()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var m tea.Model = tui.NewModel(headers, records, tui.WithSavePath(path))
+			for _, k := range tt.keys {
+				m, _ = m.Update(editKeyMsg(k))
+			}
+
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.wantFile, string(got)); diff != "" {
+				t.Errorf("file mismatch (-want +got):\n%s", diff)
+			}
+			if view := m.View(); !strings.Contains(view, tt.wantView) {
+				t.Errorf("View() does not contain %q:\n%s", tt.wantView, view)
+			}
+		})
+	}
+}
+
+func TestModel_SaveConfirmDiff(t *testing.T) {
+	t.Parallel()
+
+	headers := []*csvpp.ColumnHeader{{Name: "name", Kind: csvpp.SimpleField}}
+	records := [][]*csvpp.Field{{{Value: "Alice"}}, {{Value: "Bob"}}}
+	path := filepath.Join(t.TempDir(), "data.csvpp")
+
+	var m tea.Model = tui.NewModel(headers, records, tui.WithSavePath(path))
+	for _, k := range []string{"j", "e", "ctrl+u", "Robert", "enter", "w"} {
+		m, _ = m.Update(editKeyMsg(k))
+	}
+
+	view := m.View()
+	for _, want := range []string{"Save changes to " + path, "-Bob", "+Robert"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View() does not contain %q:\n%s", want, view)
+		}
+	}
+}
+
+func TestModel_QuitWithUnsavedChanges(t *testing.T) {
+	t.Parallel()
+
+	headers := []*csvpp.ColumnHeader{{Name: "name", Kind: csvpp.SimpleField}}
+	records := [][]*csvpp.Field{{{Value: "Alice"}}}
+
+	var m tea.Model = tui.NewModel(headers, records)
+	m, _ = m.Update(editKeyMsg("D"))
+	m, cmd := m.Update(editKeyMsg("q"))
+	if cmd != nil {
+		t.Fatal("first q with unsaved changes quit")
+	}
+	if !strings.Contains(m.View(), "unsaved changes") {
+		t.Errorf("View() does not warn about unsaved changes:\n%s", m.View())
+	}
+	if _, cmd = m.Update(editKeyMsg("q")); cmd == nil {
+		t.Error("second q did not quit")
+	}
+	if len(tui.Records(m.(tui.Model))) != 0 {
+		t.Errorf("Records() = %v, want none", tui.Records(m.(tui.Model)))
+	}
+}
+
+// editKeyMsg returns the key message for a key name, or for text typed at once.
+func editKeyMsg(k string) tea.KeyMsg {
+	if k == "ctrl+u" {
+		return tea.KeyMsg{Type: tea.KeyCtrlU}
+	}
+	return keyMsg(k)
+}
@@ -0,0 +1,207 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/osamingo/go-csvpp"
+	"github.com/osamingo/go-csvpp/csvpputil"
+)
+
+// exportFormat is a format rows are copied or exported in.
+type exportFormat int
+
+const (
+	exportCSVPP exportFormat = iota
+	exportJSON
+	exportYAML
+	exportTSV
+	numExportFormats
+)
+
+// String returns the name of the format.
+func (f exportFormat) String() string {
+	switch f {
+	case exportJSON:
+		return "JSON"
+	case exportYAML:
+		return "YAML"
+	case exportTSV:
+		return "TSV"
+	default:
+		return "CSV++"
+	}
+}
+
+// ext returns the file extension of the format.
+func (f exportFormat) ext() string {
+	switch f {
+	case exportJSON:
+		return ".json"
+	case exportYAML:
+		return ".yaml"
+	case exportTSV:
+		return ".tsv"
+	default:
+		return ".csvpp"
+	}
+}
+
+// formatForPath returns the format for the extension of path, or fallback if
+// the extension is not one of the formats.
+func formatForPath(path string, fallback exportFormat) exportFormat {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csvpp":
+		return exportCSVPP
+	case ".json":
+		return exportJSON
+	case ".yaml", ".yml":
+		return exportYAML
+	case ".tsv":
+		return exportTSV
+	default:
+		return fallback
+	}
+}
+
+// writeRecords writes records to w in format. TSV flattens structured fields
+// into dotted columns, as csvpputil.CSVWriter does.
+func writeRecords(w io.Writer, format exportFormat, headers []*csvpp.ColumnHeader, records [][]*csvpp.Field) error {
+	switch format {
+	case exportJSON:
+		return csvpputil.WriteJSON(w, headers, records)
+	case exportYAML:
+		return csvpputil.WriteYAML(w, headers, records)
+	case exportTSV:
+		cw := csvpputil.NewCSVWriter(w, headers, csvpputil.WithCSVComma('\t'))
+		for _, record := range records {
+			if err := cw.Write(record); err != nil {
+				return err
+			}
+		}
+		return cw.Close()
+	default:
+		cw := csvpp.NewWriter(w)
+		cw.SetHeaders(headers)
+		return cw.WriteAll(records)
+	}
+}
+
+// exportedRecords returns the records to export: the selected records in
+// file order if any are selected, or else the displayed records in display order.
+func (m *Model) exportedRecords() [][]*csvpp.Field {
+	var records [][]*csvpp.Field
+	if len(m.selected) > 0 {
+		for i, record := range m.records {
+			if m.selected[i] {
+				records = append(records, record)
+			}
+		}
+		return records
+	}
+	for _, idx := range m.filteredIdx {
+		records = append(records, m.records[idx])
+	}
+	return records
+}
+
+// cycleCopyFormat switches to the next clipboard format.
+func (m *Model) cycleCopyFormat() {
+	m.copyFormat = (m.copyFormat + 1) % numExportFormats
+	m.copied = false
+	m.notice = "copy format: " + m.copyFormat.String()
+}
+
+// startExport shows the export input with a file name for the current format.
+func (m *Model) startExport() tea.Cmd {
+	if len(m.exportedRecords()) == 0 {
+		m.notice = "nothing to export"
+		return nil
+	}
+
+	name := "export"
+	if m.savePath != "" {
+		name = strings.TrimSuffix(m.savePath, filepath.Ext(m.savePath)) + "-export"
+	}
+	m.exporting = true
+	m.exportArmed = ""
+	m.exportInput.SetValue(name + m.copyFormat.ext())
+	m.exportInput.CursorEnd()
+	m.table.Blur()
+	return m.exportInput.Focus()
+}
+
+// endExport hides the export input.
+func (m *Model) endExport() {
+	m.exporting = false
+	m.exportInput.Blur()
+	m.table.Focus()
+}
+
+// updateExportMode handles key events when the export input is active.
+// An existing file is only overwritten after Enter is pressed a second time.
+func (m Model) updateExportMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) { //nostyle:recvtype
+	switch msg.Type {
+	case tea.KeyEnter:
+		path := strings.TrimSpace(m.exportInput.Value())
+		if path == "" {
+			m.notice = "enter a file name"
+			return m, nil
+		}
+		if _, err := os.Stat(path); err == nil && m.exportArmed != path {
+			m.exportArmed = path
+			m.notice = "file exists: press Enter again to overwrite"
+			return m, nil
+		}
+		m.endExport()
+		format := formatForPath(path, m.copyFormat)
+		records := m.exportedRecords()
+		if err := m.exportTo(path, format, records); err != nil {
+			m.notice = fmt.Sprintf("export failed: %v", err)
+		} else {
+			m.notice = fmt.Sprintf("exported %d rows to %s as %s", len(records), path, format)
+		}
+		return m, nil
+	case tea.KeyEsc:
+		m.endExport()
+		return m, nil
+	default:
+		m.exportArmed = ""
+		var cmd tea.Cmd
+		m.exportInput, cmd = m.exportInput.Update(msg)
+		return m, cmd
+	}
+}
+
+// exportTo writes records to the file at path in format.
+func (m *Model) exportTo(path string, format exportFormat, records [][]*csvpp.Field) (retErr error) {
+	f, err := os.Create(path) //nolint:gosec // the user chooses the file to write
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := f.Close(); err != nil && retErr == nil {
+			retErr = err
+		}
+	}()
+	return writeRecords(f, format, m.headers, records)
+}
+
+// exportView renders the export input.
+func (m Model) exportView() string { //nostyle:recvtype
+	var b strings.Builder
+	b.WriteString(m.styles.FilterPrompt.Render(fmt.Sprintf("export %d rows to: ", len(m.exportedRecords()))))
+	b.WriteString(m.exportInput.View())
+	b.WriteString("\n")
+	if m.notice != "" {
+		b.WriteString(m.styles.Status.Render(m.notice))
+		b.WriteString("\n")
+	}
+	b.WriteString(m.styles.Help.Render("Enter: export (format from .csvpp/.json/.yaml/.tsv extension) • Esc: cancel"))
+	return b.String()
+}
@@ -0,0 +1,120 @@
+package tui_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/osamingo/go-csvpp"
+	"github.com/osamingo/go-csvpp/cmd/csvpp/internal/tui"
+)
+
+func TestModel_Export(t *testing.T) {
+	t.Parallel()
+
+	const input = "name,tags[],geo(lat^lon)\nAlice,go~rust,35.6^139.7\nBob,,\n"
+
+	tests := []struct {
+		name     string
+		existing string // content of the export file before the keys are pressed
+		keys     []string
+		file     string
+		want     []string // substrings of the exported file, in order
+		wantView string
+	}{
+		{
+			name:     "success: default name and format",
+			keys:     []string{"E", "enter"},
+			file:     "data-export.csvpp",
+			want:     []string{input},
+			wantView: "exported 2 rows",
+		},
+		{
+			name: "success: selected rows as TSV by extension",
+			keys: []string{"j", " ", "E", "ctrl+u", "{dir}/out.tsv", "enter"},
+			file: "out.tsv",
+			want: []string{"name\ttags[]\tgeo.lat\tgeo.lon\nBob\t\t\t\n"},
+		},
+		{
+			name: "success: filtered rows in the copy format",
+			keys: []string{"F", "F", "/", "alice", "enter", "E", "ctrl+u", "{dir}/out", "enter"},
+			file: "out",
+			want: []string{"- name: Alice", "tags:", "- go", "geo:", "lat:"},
+		},
+		{
+			name: "success: format from extension overrides the copy format",
+			keys: []string{"F", "F", "E", "ctrl+u", "{dir}/out.json", "enter"},
+			file: "out.json",
+			want: []string{`"name":"Alice"`, `"tags":["go","rust"]`, `"name":"Bob"`},
+		},
+		{
+			name:     "success: existing file needs a second enter",
+			existing: "old",
+			keys:     []string{"E", "ctrl+u", "{dir}/out.csvpp", "enter"},
+			file:     "out.csvpp",
+			want:     []string{"old"},
+			wantView: "file exists",
+		},
+		{
+			name:     "success: existing file overwritten",
+			existing: "old",
+			keys:     []string{"E", "ctrl+u", "{dir}/out.csvpp", "enter", "enter"},
+			file:     "out.csvpp",
+			want:     []string{input},
+		},
+		{
+			name:     "success: escape cancels the export",
+			existing: "old",
+			keys:     []string{"E", "ctrl+u", "{dir}/out.csvpp", "esc"},
+			file:     "out.csvpp",
+			want:     []string{"old"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			file := filepath.Join(dir, tt.file)
+			if tt.existing != "" {
+				if err := os.WriteFile(file, []byte(tt.existing), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			r := csvpp.NewReader(strings.NewReader(input))
+			headers, err := r.Headers()
+			if err != nil {
+				t.Fatal(err)
+			}
+			records, err := r.ReadAll()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var m tea.Model = tui.NewModel(headers, records, tui.WithSavePath(filepath.Join(dir, "data.csvpp")))
+			for _, k := range tt.keys {
+				m, _ = m.Update(editKeyMsg(strings.ReplaceAll(k, "{dir}", dir)))
+			}
+
+			got, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rest := string(got)
+			for _, want := range tt.want {
+				i := strings.Index(rest, want)
+				if i < 0 {
+					t.Fatalf("exported file does not contain %q in order:\n%s", want, got)
+				}
+				rest = rest[i+len(want):]
+			}
+			if view := m.View(); !strings.Contains(view, tt.wantView) {
+				t.Errorf("View() does not contain %q:\n%s", tt.wantView, view)
+			}
+		})
+	}
+}
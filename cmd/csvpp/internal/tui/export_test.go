@@ -1,24 +1,176 @@
 package tui
 
 import (
+	"time"
+
 	"github.com/charmbracelet/bubbles/table"
 
 	"github.com/osamingo/go-csvpp"
 )
 
-// FilterQuery is an exported wrapper of filterQuery for testing.
-type FilterQuery struct {
+// FilterTerm is an exported view of filterTerm for testing.
+type FilterTerm struct {
+	Negate bool
 	Column string
+	Op     string
 	Value  string
 }
 
 // ParseFilterQuery exports parseFilterQuery for testing.
-func ParseFilterQuery(s string) FilterQuery {
-	q := parseFilterQuery(s)
-	return FilterQuery{Column: q.column, Value: q.value}
+func ParseFilterQuery(s string, headers []*csvpp.ColumnHeader) ([]FilterTerm, error) {
+	q, err := parseFilterQuery(s, headers)
+	if err != nil {
+		return nil, err
+	}
+	var terms []FilterTerm
+	for _, t := range q.terms {
+		terms = append(terms, FilterTerm{Negate: t.negate, Column: t.column, Op: t.op.String(), Value: t.value})
+	}
+	return terms, nil
+}
+
+// MatchesFilter reports whether a record matches the filter query s.
+func MatchesFilter(s string, headers []*csvpp.ColumnHeader, fields []*csvpp.Field) (bool, error) {
+	q, err := parseFilterQuery(s, headers)
+	if err != nil {
+		return false, err
+	}
+	row := table.Row{" "}
+	for i, h := range headers {
+		var f *csvpp.Field
+		if i < len(fields) {
+			f = fields[i]
+		}
+		row = append(row, formatFieldValue(h, f))
+	}
+	return matchesFilter(q, csvpp.NewRecord(headers, fields), row), nil
+}
+
+// FilterError returns the parse error of the filter input.
+func FilterError(m Model) error {
+	return m.filterErr
+}
+
+// SortedIndices exports sortedIndices for testing.
+func SortedIndices(rows []table.Row, col int, desc bool) []int {
+	order := sortAsc
+	if desc {
+		order = sortDesc
+	}
+	idx, _ := sortedIndices(rows, col, order)
+	return idx
+}
+
+// MergeIndices sorts rows[:start] with sortedIndices and merges rows[start:]
+// into the result with mergeIndices.
+func MergeIndices(rows []table.Row, start, col int, desc bool) []int {
+	order := sortAsc
+	if desc {
+		order = sortDesc
+	}
+	sorted, numeric := sortedIndices(rows[:start], col, order)
+	merged, _ := mergeIndices(sorted, rows, start, col, order, numeric)
+	return merged
+}
+
+// CompareNatural exports compareNatural for testing.
+func CompareNatural(a, b string) int {
+	return compareNatural(a, b)
+}
+
+// FilteredIndices returns the original record index of each displayed row.
+func FilteredIndices(m Model) []int {
+	return m.filteredIdx
+}
+
+// DetailLines returns the visible lines of the detail view without styling,
+// or nil if the table is shown.
+func DetailLines(m Model) []string {
+	if m.detail == nil {
+		return nil
+	}
+	var lines []string
+	for _, l := range m.detail.lines() {
+		lines = append(lines, l.text())
+	}
+	return lines
+}
+
+// DetailPath returns the path of the detail view line under the cursor.
+func DetailPath(m Model) string {
+	if m.detail == nil {
+		return ""
+	}
+	l, _ := m.detail.current()
+	if l.node == nil {
+		return ""
+	}
+	return l.node.path
+}
+
+// TableColumns returns the data columns shown by the table, without the
+// selection marker column.
+func TableColumns(m Model) []table.Column {
+	return m.table.Columns()[1:]
+}
+
+// FieldText exports fieldText for testing.
+func FieldText(h *csvpp.ColumnHeader, f *csvpp.Field) (string, error) {
+	return fieldText(h, f)
+}
+
+// ParseFieldText exports parseFieldText for testing.
+func ParseFieldText(h *csvpp.ColumnHeader, text string) (*csvpp.Field, error) {
+	return parseFieldText(h, text)
+}
+
+// UnifiedDiff returns the unified diff of a and b.
+func UnifiedDiff(a, b []string, context int) []string {
+	return unifiedDiff(diffLines(a, b), context)
+}
+
+// Records returns the records of the model, including unsaved edits.
+func Records(m Model) [][]*csvpp.Field {
+	return m.records
+}
+
+// WithReaderBatch is like WithReader but reads at most batch records per message.
+func WithReaderBatch(r RecordReader, batch int) ModelOption {
+	return func(m *Model) {
+		m.loader = &loader{r: r, batch: batch, interval: time.Millisecond}
+	}
+}
+
+// Loading reports whether records are still being loaded.
+func Loading(m Model) bool {
+	return m.loader != nil
+}
+
+// CurrentIndex returns the original index of the record under the cursor.
+func CurrentIndex(m Model) int {
+	return m.originalIndex()
+}
+
+// TableRowCount returns the number of rows the table holds.
+func TableRowCount(m Model) int {
+	return len(m.table.Rows())
+}
+
+// ColumnStats returns the statistics lines of column col over the records at indices idx.
+func ColumnStats(h *csvpp.ColumnHeader, records [][]*csvpp.Field, col int, idx []int) []string {
+	return computeColumnStats(h, records, col, idx).lines()
+}
+
+// ConfigPathFor exports configPath for testing.
+func ConfigPathFor(xdg, home string) string {
+	return configPath(xdg, home)
 }
 
-// MatchesFilter exports matchesFilter for testing.
-func MatchesFilter(query FilterQuery, headers []*csvpp.ColumnHeader, row table.Row) bool {
-	return matchesFilter(filterQuery{column: query.Column, value: query.Value}, headers, row)
+// KeyMapKeys returns the keys of each action of k by its name in the config file.
+func KeyMapKeys(k KeyMap) map[string][]string {
+	keys := make(map[string][]string)
+	for _, a := range k.actions() {
+		keys[a.name] = a.binding.Keys()
+	}
+	return keys
 }
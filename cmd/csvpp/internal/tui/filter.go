@@ -0,0 +1,308 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/table"
+
+	"github.com/osamingo/go-csvpp"
+)
+
+// filterOp is the comparison a filter term makes.
+type filterOp int
+
+const (
+	opContains     filterOp = iota // column:value, or a bare value
+	opEqual                        // column=value
+	opRegexp                       // /regexp/ or column:/regexp/
+	opLess                         // column<number
+	opLessEqual                    // column<=number
+	opGreater                      // column>number
+	opGreaterEqual                 // column>=number
+)
+
+// String returns the operator as written in a query.
+func (op filterOp) String() string {
+	switch op {
+	case opEqual:
+		return "="
+	case opRegexp:
+		return "/"
+	case opLess:
+		return "<"
+	case opLessEqual:
+		return "<="
+	case opGreater:
+		return ">"
+	case opGreaterEqual:
+		return ">="
+	default:
+		return ":"
+	}
+}
+
+// filterTerm is one whitespace-separated term of a filter query.
+type filterTerm struct {
+	negate bool
+	column string // column name or field path as written, empty for all columns
+	op     filterOp
+	value  string         // lowercased for opContains and opEqual
+	re     *regexp.Regexp // for opRegexp
+	num    float64        // for numeric comparisons
+
+	path  *csvpp.Path // field path whose values are compared, if column names one
+	index int         // column whose displayed value is compared, -1 for all columns
+}
+
+// filterQuery is a parsed filter query. A record matches if it matches every term.
+type filterQuery struct {
+	terms []filterTerm
+}
+
+// parseFilterQuery parses a filter query and resolves its columns against
+// headers. Terms are separated by whitespace:
+//
+//	alice            any column contains "alice" (ignoring case)
+//	name:alice       the name column contains "alice"
+//	name=alice       the name column is "alice"
+//	"new york"       any column contains "new york"
+//	/^a.*e$/         any column matches the regular expression
+//	name:/^a/        the name column matches the regular expression
+//	age>30           the age column is a number greater than 30 (also >=, <, <=)
+//	geo.lat>=35      a component, using the field paths of csvpp.Record
+//	!tags:go         negates any of the above
+//
+// A column that holds several values, such as an array, matches if any of
+// its values matches.
+func parseFilterQuery(s string, headers []*csvpp.ColumnHeader) (filterQuery, error) {
+	tokens, err := splitFilterTerms(s)
+	if err != nil {
+		return filterQuery{}, err
+	}
+
+	var q filterQuery
+	for _, tok := range tokens {
+		t, err := parseFilterTerm(tok)
+		if err != nil {
+			return filterQuery{}, err
+		}
+		if err := t.resolve(headers); err != nil {
+			return filterQuery{}, err
+		}
+		q.terms = append(q.terms, t)
+	}
+	return q, nil
+}
+
+// splitFilterTerms splits s at whitespace outside of quoted values and
+// regular expressions.
+func splitFilterTerms(s string) ([]string, error) {
+	var terms []string
+	var b strings.Builder
+	var quote rune // '"' or '/' inside a quoted value or regular expression
+	escaped := false
+	for _, r := range s {
+		switch {
+		case quote != 0:
+			b.WriteRune(r)
+			switch {
+			case escaped:
+				escaped = false
+			case r == '\\':
+				escaped = true
+			case r == quote:
+				quote = 0
+			}
+		case unicode.IsSpace(r):
+			if b.Len() > 0 {
+				terms = append(terms, b.String())
+				b.Reset()
+			}
+		case (r == '"' || r == '/') && startsValue(b.String()):
+			quote = r
+			b.WriteRune(r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	switch quote {
+	case '"':
+		return nil, errors.New("unterminated quoted value")
+	case '/':
+		return nil, errors.New("unterminated regular expression")
+	}
+	if b.Len() > 0 {
+		terms = append(terms, b.String())
+	}
+	return terms, nil
+}
+
+// startsValue reports whether the next character of a term written so far
+// starts its value.
+func startsValue(term string) bool {
+	if term == "" || term == "!" {
+		return true
+	}
+	return strings.ContainsAny(term[len(term)-1:], ":=<>")
+}
+
+// parseFilterTerm parses a single term of a filter query.
+func parseFilterTerm(tok string) (filterTerm, error) {
+	t := filterTerm{index: -1}
+	if rest, ok := strings.CutPrefix(tok, "!"); ok {
+		t.negate = true
+		tok = rest
+	}
+	if tok == "" {
+		return filterTerm{}, errors.New("nothing to negate after !")
+	}
+
+	value := tok
+	if !strings.HasPrefix(tok, `"`) && !strings.HasPrefix(tok, "/") {
+		if i := strings.IndexAny(tok, ":=<>"); i >= 0 {
+			t.column = tok[:i]
+			value = tok[i+1:]
+			switch tok[i] {
+			case '=':
+				t.op = opEqual
+			case '<', '>':
+				t.op = opLess
+				if tok[i] == '>' {
+					t.op = opGreater
+				}
+				if rest, ok := strings.CutPrefix(value, "="); ok {
+					t.op++ // opLessEqual or opGreaterEqual
+					value = rest
+				}
+			}
+		}
+	}
+
+	switch {
+	case len(value) >= 2 && strings.HasPrefix(value, "/") && strings.HasSuffix(value, "/"):
+		if t.op != opContains {
+			return filterTerm{}, fmt.Errorf("regular expression %s needs ':' after the column", value)
+		}
+		re, err := regexp.Compile(value[1 : len(value)-1])
+		if err != nil {
+			return filterTerm{}, err
+		}
+		t.op, t.re, t.value = opRegexp, re, re.String()
+		return t, nil
+	case strings.HasPrefix(value, `"`):
+		v, err := strconv.Unquote(value)
+		if err != nil {
+			return filterTerm{}, fmt.Errorf("invalid quoted value %s", value)
+		}
+		value = v
+	}
+
+	switch t.op {
+	case opContains, opEqual:
+		t.value = strings.ToLower(value)
+	default:
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return filterTerm{}, fmt.Errorf("%s%s needs a number, got %q", t.column, t.op, value)
+		}
+		t.value, t.num = value, n
+	}
+	return t, nil
+}
+
+// resolve finds the values the term compares: the values of a field path,
+// such as "geo.lat" or "tags", or else the displayed value of a column named
+// by its name or title, ignoring case.
+func (t *filterTerm) resolve(headers []*csvpp.ColumnHeader) error {
+	if t.column == "" {
+		return nil
+	}
+	if p, err := csvpp.ParsePath(t.column); err == nil {
+		if h, err := p.Header(headers); err == nil && h.Kind != csvpp.StructuredField && h.Kind != csvpp.ArrayStructuredField {
+			t.path = p
+			return nil
+		}
+	}
+	for i, h := range headers {
+		if strings.EqualFold(h.Name, t.column) || strings.EqualFold(formatHeaderTitle(h), t.column) {
+			t.index = i
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown column %q", t.column)
+}
+
+// matchesFilter reports whether a record matches every term of query.
+// row is the table row of the record.
+func matchesFilter(query filterQuery, record *csvpp.Record, row table.Row) bool {
+	for i := range query.terms {
+		if !query.terms[i].match(record, row) {
+			return false
+		}
+	}
+	return true
+}
+
+// match reports whether the term matches a record.
+func (t *filterTerm) match(record *csvpp.Record, row table.Row) bool {
+	if t.op == opContains && t.value == "" {
+		return !t.negate // "column:" matches every record
+	}
+	return slices.ContainsFunc(t.values(record, row), t.matchValue) != t.negate
+}
+
+// values returns the values of a record the term compares.
+func (t *filterTerm) values(record *csvpp.Record, row table.Row) []string {
+	switch {
+	case t.path != nil:
+		values, err := record.GetAllPath(t.path)
+		if err != nil {
+			return nil // e.g. an index beyond the end of the array
+		}
+		return values
+	case t.index >= 0:
+		// row[0] is the selection marker, data starts at index 1
+		if t.index+1 < len(row) {
+			return row[t.index+1 : t.index+2]
+		}
+		return nil
+	case len(row) > 1:
+		return row[1:]
+	default:
+		return nil
+	}
+}
+
+// matchValue reports whether a single value satisfies the term, ignoring negation.
+func (t *filterTerm) matchValue(v string) bool {
+	switch t.op {
+	case opContains:
+		return strings.Contains(strings.ToLower(v), t.value)
+	case opEqual:
+		return strings.ToLower(v) == t.value
+	case opRegexp:
+		return t.re.MatchString(v)
+	}
+
+	n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+	if err != nil {
+		return false // not a number, e.g. an empty value
+	}
+	switch t.op {
+	case opLess:
+		return n < t.num
+	case opLessEqual:
+		return n <= t.num
+	case opGreater:
+		return n > t.num
+	default:
+		return n >= t.num
+	}
+}
@@ -0,0 +1,176 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+)
+
+// KeyMap holds the key bindings of the table view. It satisfies the
+// help.KeyMap interface, which renders the help line.
+type KeyMap struct {
+	Table table.KeyMap // row navigation
+
+	ColumnLeft  key.Binding
+	ColumnRight key.Binding
+	Widen       key.Binding
+	Narrow      key.Binding
+	Fit         key.Binding
+	Hide        key.Binding
+	ShowAll     key.Binding
+	SortAsc     key.Binding
+	SortDesc    key.Binding
+
+	Details key.Binding
+	Stats   key.Binding
+	Edit    key.Binding
+	Insert  key.Binding
+	Delete  key.Binding
+	Save    key.Binding
+
+	Select     key.Binding
+	Copy       key.Binding
+	CopyFormat key.Binding
+	Export     key.Binding
+	Filter     key.Binding
+	Clear      key.Binding
+
+	Help key.Binding
+	Quit key.Binding
+}
+
+// DefaultKeyMap returns the default key bindings.
+func DefaultKeyMap() KeyMap {
+	t := table.DefaultKeyMap()
+	t.PageDown.SetKeys("f", "pgdown") // space selects rows
+	t.GotoTop.SetHelp("g/home", "first row")
+	t.GotoBottom.SetHelp("G/end", "last row")
+
+	return KeyMap{
+		Table: t,
+
+		ColumnLeft:  key.NewBinding(key.WithKeys("left", "h"), key.WithHelp("←/h", "column left")),
+		ColumnRight: key.NewBinding(key.WithKeys("right", "l"), key.WithHelp("→/l", "column right")),
+		Widen:       key.NewBinding(key.WithKeys("+"), key.WithHelp("+", "widen")),
+		Narrow:      key.NewBinding(key.WithKeys("-"), key.WithHelp("-", "narrow")),
+		Fit:         key.NewBinding(key.WithKeys("="), key.WithHelp("=", "fit width")),
+		Hide:        key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "hide column")),
+		ShowAll:     key.NewBinding(key.WithKeys("X"), key.WithHelp("X", "show all")),
+		SortAsc:     key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "sort asc")),
+		SortDesc:    key.NewBinding(key.WithKeys("S"), key.WithHelp("S", "sort desc")),
+
+		Details: key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "details")),
+		Stats:   key.NewBinding(key.WithKeys("i"), key.WithHelp("i", "stats")),
+		Edit:    key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "edit")),
+		Insert:  key.NewBinding(key.WithKeys("o"), key.WithHelp("o", "add row")),
+		Delete:  key.NewBinding(key.WithKeys("D"), key.WithHelp("D", "delete row")),
+		Save:    key.NewBinding(key.WithKeys("w"), key.WithHelp("w", "save")),
+
+		Select:     key.NewBinding(key.WithKeys(" "), key.WithHelp("space", "select")),
+		Copy:       key.NewBinding(key.WithKeys("y", "c"), key.WithHelp("y/c", "copy")),
+		CopyFormat: key.NewBinding(key.WithKeys("F"), key.WithHelp("F", "copy format")),
+		Export:     key.NewBinding(key.WithKeys("E"), key.WithHelp("E", "export")),
+		Filter:     key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "filter")),
+		Clear:      key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "clear")),
+
+		Help: key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "more keys")),
+		Quit: key.NewBinding(key.WithKeys("q"), key.WithHelp("q", "quit")),
+	}
+}
+
+// ShortHelp implements help.KeyMap.
+func (k KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{
+		k.Details, k.Filter, k.Select, k.Copy, k.Edit, k.Save, k.Clear, k.Help, k.Quit,
+	}
+}
+
+// FullHelp implements help.KeyMap.
+func (k KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Table.LineUp, k.Table.LineDown, k.Table.PageUp, k.Table.PageDown, k.Table.GotoTop, k.Table.GotoBottom},
+		{k.ColumnLeft, k.ColumnRight, k.Widen, k.Narrow, k.Fit, k.Hide, k.ShowAll},
+		{k.SortAsc, k.SortDesc, k.Details, k.Stats, k.Edit, k.Insert, k.Delete, k.Save},
+		{k.Select, k.Copy, k.CopyFormat, k.Export, k.Filter, k.Clear, k.Help, k.Quit},
+	}
+}
+
+// actions returns the bindings by the action names used in the config file,
+// in the order they are checked for conflicts.
+func (k *KeyMap) actions() []keyAction {
+	return []keyAction{
+		{"up", &k.Table.LineUp},
+		{"down", &k.Table.LineDown},
+		{"page-up", &k.Table.PageUp},
+		{"page-down", &k.Table.PageDown},
+		{"half-page-up", &k.Table.HalfPageUp},
+		{"half-page-down", &k.Table.HalfPageDown},
+		{"top", &k.Table.GotoTop},
+		{"bottom", &k.Table.GotoBottom},
+		{"column-left", &k.ColumnLeft},
+		{"column-right", &k.ColumnRight},
+		{"widen", &k.Widen},
+		{"narrow", &k.Narrow},
+		{"fit", &k.Fit},
+		{"hide", &k.Hide},
+		{"show-all", &k.ShowAll},
+		{"sort-asc", &k.SortAsc},
+		{"sort-desc", &k.SortDesc},
+		{"details", &k.Details},
+		{"stats", &k.Stats},
+		{"edit", &k.Edit},
+		{"insert", &k.Insert},
+		{"delete", &k.Delete},
+		{"save", &k.Save},
+		{"select", &k.Select},
+		{"copy", &k.Copy},
+		{"copy-format", &k.CopyFormat},
+		{"export", &k.Export},
+		{"filter", &k.Filter},
+		{"clear", &k.Clear},
+		{"help", &k.Help},
+		{"quit", &k.Quit},
+	}
+}
+
+// keyAction is a binding and its action name in the config file.
+type keyAction struct {
+	name    string
+	binding *key.Binding
+}
+
+// rebind replaces the keys of b, labelling it with the new keys. Without keys
+// the binding is disabled. "space" stands for the space bar.
+func rebind(b *key.Binding, keys []string) {
+	keys = append([]string(nil), keys...)
+	labels := make([]string, len(keys))
+	for i, k := range keys {
+		if k == "space" || k == " " {
+			keys[i], k = " ", "space"
+		}
+		labels[i] = k
+	}
+	if len(keys) == 0 {
+		b.Unbind()
+		return
+	}
+	b.SetKeys(keys...)
+	b.SetHelp(strings.Join(labels, "/"), b.Help().Desc)
+}
+
+// newHelp returns the help view, styled like the rest of the help text.
+func newHelp(s Styles) help.Model {
+	h := help.New()
+	h.Styles = help.Styles{
+		Ellipsis:       s.Help,
+		ShortKey:       s.HelpKey,
+		ShortDesc:      s.Help,
+		ShortSeparator: s.Help,
+		FullKey:        s.HelpKey,
+		FullDesc:       s.Help,
+		FullSeparator:  s.Help,
+	}
+	return h
+}
@@ -0,0 +1,199 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/osamingo/go-csvpp"
+)
+
+// Background loading limits. A batch ends when either limit is reached, so
+// that the screen is updated regularly while a large input loads.
+const (
+	loadBatchSize = 10000
+	loadBatchTime = 100 * time.Millisecond
+)
+
+// followInterval is how often a followed input is checked for new records.
+const followInterval = 500 * time.Millisecond
+
+// RecordReader reads records one at a time. *csvpp.Reader implements it.
+type RecordReader interface {
+	Read() ([]*csvpp.Field, error)
+}
+
+// loader reads records in the background, one batch per command.
+type loader struct {
+	r        RecordReader
+	progress func() float64 // fraction of the input read, nil if unknown
+	batch    int            // most records in a batch
+	interval time.Duration  // time between reads at the end of a followed input
+	caughtUp bool           // a followed input was read to its current end
+}
+
+// recordsMsg carries a batch of records read by the loader.
+type recordsMsg struct {
+	records [][]*csvpp.Field
+	err     error // io.EOF after the last record
+}
+
+// WithReader makes the model read more records from r in the background once
+// the program starts, so that rows can be browsed while a large input is
+// still loading. progress returns the fraction of the input read so far; it
+// may be nil if the size of the input is unknown.
+func WithReader(r RecordReader, progress func() float64) ModelOption {
+	return func(m *Model) {
+		m.loader = &loader{r: r, progress: progress, batch: loadBatchSize, interval: followInterval}
+	}
+}
+
+// WithFollow keeps reading records from the reader given to WithReader after
+// the end of the input, appending the records added to it, like tail -f.
+// Read errors are shown as notices and reading continues.
+func WithFollow() ModelOption {
+	return func(m *Model) {
+		m.follow = true
+	}
+}
+
+// next returns a command that reads the next batch of records.
+func (l *loader) next() tea.Cmd {
+	return l.read
+}
+
+// poll returns a command that reads the next batch of records after the
+// follow interval.
+func (l *loader) poll() tea.Cmd {
+	return tea.Tick(l.interval, func(time.Time) tea.Msg { return l.read() })
+}
+
+// read reads a batch of records.
+func (l *loader) read() tea.Msg {
+	var msg recordsMsg
+	deadline := time.Now().Add(loadBatchTime)
+	for len(msg.records) < l.batch && time.Now().Before(deadline) {
+		record, err := l.r.Read()
+		if err != nil {
+			msg.err = err
+			break
+		}
+		msg.records = append(msg.records, record)
+	}
+	return msg
+}
+
+// status returns the loading indicator shown in the status line.
+func (l *loader) status() string {
+	if l.caughtUp {
+		return "following"
+	}
+	if l.progress == nil {
+		return "loading…"
+	}
+	return fmt.Sprintf("loading %d%%", int(min(l.progress(), 1)*100))
+}
+
+// appendRecords adds a batch of loaded records to the end of the records
+// and returns the command that reads the next batch.
+func (m *Model) appendRecords(msg recordsMsg) tea.Cmd {
+	if len(msg.records) > 0 {
+		m.addRecords(msg.records)
+	}
+
+	l := m.loader
+	switch {
+	case msg.err == nil:
+		return l.next()
+	case m.follow:
+		if !errors.Is(msg.err, io.EOF) {
+			m.notice = fmt.Sprintf("read records: %v", msg.err)
+		}
+		l.caughtUp = true
+		return l.poll()
+	case !errors.Is(msg.err, io.EOF):
+		m.err = fmt.Errorf("read records: %w", msg.err)
+	}
+	m.loader = nil
+	return nil
+}
+
+// addRecords adds records to the end of the records, keeping the sort order,
+// filter, selection and cursor.
+func (m *Model) addRecords(records [][]*csvpp.Field) {
+	keep := m.originalIndex()
+	start := len(m.records)
+	m.records = append(m.records, records...)
+	if m.original != nil {
+		// Loaded records are unchanged until they are edited.
+		m.original = append(m.original, cloneRecords(records)...)
+	}
+	for i := start; i < len(m.records); i++ {
+		m.allRows = append(m.allRows, m.recordRow(i))
+	}
+	m.growWidths(start)
+
+	if m.sortOrder != sortNone {
+		m.order, m.numeric = mergeIndices(m.order, m.allRows, start, m.sortCol, m.sortOrder, m.numeric)
+	}
+	m.showAppended(start, keep)
+	if m.stats != nil {
+		m.refreshStats()
+	}
+}
+
+// showAppended shows the records from index start on that match the filter,
+// at the end of the displayed rows or at their place in the sort order, keeping
+// the cursor on record keep. Only the new records are filtered.
+func (m *Model) showAppended(start, keep int) {
+	var query filterQuery
+	if m.filtering || m.filterText != "" {
+		var err error
+		if query, err = parseFilterQuery(m.filterInput.Value(), m.headers); err != nil {
+			return // the rows are filtered again once the input parses
+		}
+	}
+
+	if m.order == nil {
+		for i := start; i < len(m.records); i++ {
+			if matchesFilter(query, csvpp.NewRecord(m.headers, m.records[i]), m.allRows[i]) {
+				m.filteredIdx = append(m.filteredIdx, i)
+			}
+		}
+		m.refreshTable()
+		return
+	}
+
+	shown := make([]bool, len(m.records))
+	for _, i := range m.filteredIdx {
+		shown[i] = true
+	}
+	for i := start; i < len(m.records); i++ {
+		shown[i] = matchesFilter(query, csvpp.NewRecord(m.headers, m.records[i]), m.allRows[i])
+	}
+	idx := make([]int, 0, len(m.filteredIdx)+len(m.records)-start)
+	for _, i := range m.order {
+		if shown[i] {
+			idx = append(idx, i)
+		}
+	}
+	m.filteredIdx = idx
+	m.setCursor(max(slices.Index(idx, keep), 0))
+}
+
+// growWidths widens the auto-fit width of the columns for the rows from index
+// start on. Columns that were not resized follow their auto-fit width.
+func (m *Model) growWidths(start int) {
+	for i, w := range autoFitWidths(m.headers, m.allRows[start:]) {
+		if w > m.fitWidths[i] {
+			if m.widths[i] == m.fitWidths[i] {
+				m.widths[i] = w
+			}
+			m.fitWidths[i] = w
+		}
+	}
+}
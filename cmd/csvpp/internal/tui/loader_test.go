@@ -0,0 +1,246 @@
+package tui_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/osamingo/go-csvpp"
+	"github.com/osamingo/go-csvpp/cmd/csvpp/internal/tui"
+)
+
+// fakeReader returns records named "r0", "r1", ... and then err.
+type fakeReader struct {
+	n, count int
+	err      error
+}
+
+func (r *fakeReader) Read() ([]*csvpp.Field, error) {
+	if r.n == r.count {
+		return nil, r.err
+	}
+	r.n++
+	return []*csvpp.Field{{Value: fmt.Sprintf("r%d", r.n-1)}, {Value: fmt.Sprint((r.n - 1) % 3)}}, nil
+}
+
+func TestModel_Load(t *testing.T) {
+	t.Parallel()
+
+	headers := []*csvpp.ColumnHeader{
+		{Name: "name", Kind: csvpp.SimpleField},
+		{Name: "mod", Kind: csvpp.SimpleField},
+	}
+
+	tests := []struct {
+		name      string
+		count     int
+		err       error
+		keys      []string // pressed after the first batch
+		want      []int
+		wantIndex int
+		wantErr   string
+	}{
+		{
+			name:      "success: every record is loaded",
+			count:     7,
+			err:       io.EOF,
+			want:      []int{0, 1, 2, 3, 4, 5, 6},
+			wantIndex: 0,
+		},
+		{
+			name:      "success: cursor stays on the record while loading",
+			count:     7,
+			err:       io.EOF,
+			keys:      []string{"down"},
+			want:      []int{0, 1, 2, 3, 4, 5, 6},
+			wantIndex: 1,
+		},
+		{
+			name:      "success: filter applies to loaded records",
+			count:     7,
+			err:       io.EOF,
+			keys:      []string{"/", "m", "o", "d", "=", "1", "enter"},
+			want:      []int{1, 4},
+			wantIndex: 1,
+		},
+		{
+			name:      "success: sort applies to loaded records",
+			count:     7,
+			err:       io.EOF,
+			keys:      []string{"l", "S"},
+			want:      []int{2, 5, 1, 4, 0, 3, 6},
+			wantIndex: 0,
+		},
+		{
+			name:      "success: sort and filter apply to loaded records",
+			count:     10,
+			err:       io.EOF,
+			keys:      []string{"l", "S", "/", "m", "o", "d", ">", "0", "enter", "down"},
+			want:      []int{2, 5, 8, 1, 4, 7},
+			wantIndex: 1,
+		},
+		{
+			name:      "error: read error is reported",
+			count:     4,
+			err:       errors.New("broken"),
+			want:      []int{0, 1, 2, 3},
+			wantIndex: 0,
+			wantErr:   "read records: broken",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var m tea.Model = tui.NewModel(headers, nil, tui.WithReaderBatch(&fakeReader{count: tt.count, err: tt.err}, 3))
+			cmd := m.Init()
+			m, cmd = m.Update(cmd())
+			if !tui.Loading(m.(tui.Model)) {
+				t.Fatal("Loading() = false after the first batch")
+			}
+			if view := m.View(); !strings.Contains(view, "loading…") {
+				t.Errorf("View() does not show loading:\n%s", view)
+			}
+			for _, k := range tt.keys {
+				m, _ = m.Update(keyMsg(k))
+			}
+			for cmd != nil {
+				m, cmd = m.Update(cmd())
+			}
+
+			got := m.(tui.Model)
+			if tui.Loading(got) {
+				t.Error("Loading() = true after the last batch")
+			}
+			if diff := cmp.Diff(tt.want, tui.FilteredIndices(got)); diff != "" {
+				t.Errorf("FilteredIndices() mismatch (-want +got):\n%s", diff)
+			}
+			if idx := tui.CurrentIndex(got); idx != tt.wantIndex {
+				t.Errorf("CurrentIndex() = %d, want %d", idx, tt.wantIndex)
+			}
+			if view := m.View(); tt.wantErr != "" && !strings.Contains(view, tt.wantErr) {
+				t.Errorf("View() does not contain %q:\n%s", tt.wantErr, view)
+			}
+		})
+	}
+}
+
+func TestModel_RowWindow(t *testing.T) {
+	t.Parallel()
+
+	headers := []*csvpp.ColumnHeader{{Name: "n", Kind: csvpp.SimpleField}}
+	records := make([][]*csvpp.Field, 3000)
+	for i := range records {
+		records[i] = []*csvpp.Field{{Value: fmt.Sprint(i)}}
+	}
+
+	tests := []struct {
+		name string
+		keys []string
+		want int
+	}{
+		{name: "success: start", want: 0},
+		{name: "success: moving down past the window", keys: repeatKeys("j", 700), want: 700},
+		{name: "success: paging down past the window", keys: repeatKeys("pgdown", 100), want: 900},
+		{name: "success: end", keys: []string{"G"}, want: 2999},
+		{name: "success: back to the start", keys: []string{"G", "k", "g"}, want: 0},
+		{name: "success: moving up from the end", keys: append([]string{"G"}, repeatKeys("up", 1000)...), want: 1999},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var m tea.Model = tui.NewModel(headers, records)
+			for _, k := range tt.keys {
+				m, _ = m.Update(keyMsg(k))
+			}
+			got := m.(tui.Model)
+			if idx := tui.CurrentIndex(got); idx != tt.want {
+				t.Errorf("CurrentIndex() = %d, want %d", idx, tt.want)
+			}
+			if n := tui.TableRowCount(got); n > 1000 {
+				t.Errorf("TableRowCount() = %d, want a window of the rows", n)
+			}
+		})
+	}
+}
+
+func repeatKeys(k string, n int) []string {
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = k
+	}
+	return keys
+}
+
+func TestModel_Follow(t *testing.T) {
+	t.Parallel()
+
+	headers := []*csvpp.ColumnHeader{
+		{Name: "name", Kind: csvpp.SimpleField},
+		{Name: "mod", Kind: csvpp.SimpleField},
+	}
+
+	tests := []struct {
+		name       string
+		err        error // returned at the end of the appended records
+		want       []int
+		wantStatus []string
+	}{
+		{
+			name:       "success: appended records keep cursor, filter and selection",
+			err:        io.EOF,
+			want:       []int{0, 3, 6},
+			wantStatus: []string{"7 records (3 shown)", "1 selected", "following"},
+		},
+		{
+			name:       "error: read error is shown and following continues",
+			err:        errors.New("broken"),
+			want:       []int{0, 3, 6},
+			wantStatus: []string{"following", "read records: broken"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := &fakeReader{count: 4, err: io.EOF}
+			var m tea.Model = tui.NewModel(headers, nil, tui.WithReaderBatch(r, 10), tui.WithFollow())
+			m, cmd := m.Update(m.Init()())
+			if cmd == nil {
+				t.Fatal("Update() stopped reading at the end of the input")
+			}
+			for _, k := range []string{" ", "/", "mod:0", "enter", "j"} {
+				m, _ = m.Update(keyMsg(k))
+			}
+
+			r.count, r.err = 7, tt.err
+			m, cmd = m.Update(cmd())
+			if cmd == nil {
+				t.Fatal("Update() stopped following")
+			}
+
+			got := m.(tui.Model)
+			if diff := cmp.Diff(tt.want, tui.FilteredIndices(got)); diff != "" {
+				t.Errorf("FilteredIndices() mismatch (-want +got):\n%s", diff)
+			}
+			if idx := tui.CurrentIndex(got); idx != 3 {
+				t.Errorf("CurrentIndex() = %d, want 3", idx)
+			}
+			view := m.View()
+			for _, want := range tt.wantStatus {
+				if !strings.Contains(view, want) {
+					t.Errorf("View() does not contain %q:\n%s", want, view)
+				}
+			}
+		})
+	}
+}
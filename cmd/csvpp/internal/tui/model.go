@@ -3,117 +3,107 @@ package tui
 import (
 	"bytes"
 	"fmt"
+	"slices"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/table"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"golang.design/x/clipboard"
 
 	"github.com/osamingo/go-csvpp"
 )
 
-// filterQuery represents a parsed filter query.
-type filterQuery struct {
-	column string // empty means search all columns
-	value  string // search string (lowercase)
-}
-
-// parseFilterQuery parses a filter query string.
-// Supports "column:value" syntax for column-specific filtering.
-// If the part before ":" is empty, it searches all columns.
-func parseFilterQuery(s string) filterQuery {
-	s = strings.TrimSpace(s)
-	if s == "" {
-		return filterQuery{}
-	}
-
-	if before, after, ok := strings.Cut(s, ":"); ok {
-		col := strings.TrimSpace(before)
-		val := strings.TrimSpace(after)
-		if col != "" {
-			return filterQuery{
-				column: strings.ToLower(col),
-				value:  strings.ToLower(val),
-			}
-		}
-		// empty column part means search all columns
-		return filterQuery{
-			value: strings.ToLower(val),
-		}
-	}
-
-	return filterQuery{
-		value: strings.ToLower(s),
-	}
-}
-
-// matchesFilter checks if a row matches the given filter query.
-func matchesFilter(query filterQuery, headers []*csvpp.ColumnHeader, row table.Row) bool {
-	if query.value == "" {
-		return true
-	}
-
-	if query.column != "" {
-		// Search specific column
-		for i, h := range headers {
-			if strings.ToLower(formatHeaderTitle(h)) == query.column {
-				// row[0] is the selection marker, data starts at index 1
-				if i+1 < len(row) && strings.Contains(strings.ToLower(row[i+1]), query.value) {
-					return true
-				}
-				return false
-			}
-		}
-		// Column not found
-		return false
-	}
-
-	// Search all data columns (skip index 0 which is selection marker)
-	for i := 1; i < len(row); i++ {
-		if strings.Contains(strings.ToLower(row[i]), query.value) {
-			return true
-		}
-	}
-	return false
-}
-
 // Model represents the TUI model for viewing CSV++ data.
 type Model struct {
 	table    table.Model
 	headers  []*csvpp.ColumnHeader
 	records  [][]*csvpp.Field
 	styles   Styles
+	keys     KeyMap
+	help     help.Model
 	width    int
 	height   int
 	err      error
 	selected map[int]bool // keyed by original record index
 	copied   bool
 
+	// Copy and export fields
+	copyFormat  exportFormat    // format rows are copied and exported in
+	exporting   bool            // true when the export input is active
+	exportInput textinput.Model // export file name input widget
+	exportArmed string          // existing file that Enter overwrites
+
 	// Filter fields
 	filtering   bool            // true when filter input is active
 	filterInput textinput.Model // text input widget
 	filterText  string          // committed filter text
+	filterErr   error           // parse error of the filter input
 	filteredIdx []int           // display position -> original record index
 	allRows     []table.Row     // cache of all rows
+	rowOffset   int             // position of the first row the table holds
+
+	// Column fields
+	column    int    // current column (index into headers)
+	colOffset int    // first scrollable column shown after the frozen one
+	widths    []int  // display width of each column
+	fitWidths []int  // auto-fit width of each column
+	hidden    []bool // hidden columns
+
+	// Sort fields
+	sortCol   int       // column the rows are sorted by
+	sortOrder sortOrder // sortNone keeps the file order
+	order     []int     // sorted original record indices, nil for file order
+	numeric   bool      // order compares the sort column numerically
+
+	detail *detailView // record detail view, nil when the table is shown
+	stats  *statsView  // column statistics, nil when the table is shown
+
+	// Edit fields
+	savePath  string           // file edits are saved to, empty if saving is disabled
+	original  [][]*csvpp.Field // records as last loaded or saved, nil before the first edit
+	dirty     bool             // true if records have unsaved changes
+	editing   bool             // true when the edit input is active
+	editInput textinput.Model  // edit input widget
+	edit      editTarget       // value being edited
+	confirm   *saveConfirm     // pending save, nil otherwise
+	notice    string           // status message until the next key press
+	quitArmed bool             // true after q was pressed with unsaved changes
+
+	loader *loader // reads records in the background, nil once every record is read
+	follow bool    // keep reading records appended to the input
 }
 
-// NewModel creates a new TUI model with the given data.
-func NewModel(headers []*csvpp.ColumnHeader, records [][]*csvpp.Field) Model {
-	styles := DefaultStyles()
+// ModelOption configures a Model.
+type ModelOption func(*Model)
 
-	// Build table columns (first column is selection marker)
-	columns := make([]table.Column, len(headers)+1)
-	columns[0] = table.Column{Title: " ", Width: 2}
-	for i, h := range headers {
-		title := formatHeaderTitle(h)
-		columns[i+1] = table.Column{
-			Title: title,
-			Width: max(len(title), 10),
-		}
+// WithSavePath enables saving edited records to the CSV++ file at path.
+func WithSavePath(path string) ModelOption {
+	return func(m *Model) {
+		m.savePath = path
+	}
+}
+
+// WithStyles sets the styles of the TUI, such as those of ThemeStyles.
+func WithStyles(s Styles) ModelOption {
+	return func(m *Model) {
+		m.styles = s
+	}
+}
+
+// WithKeyMap sets the key bindings of the table view.
+func WithKeyMap(k KeyMap) ModelOption {
+	return func(m *Model) {
+		m.keys = k
 	}
+}
 
-	// Build table rows
+// NewModel creates a new TUI model with the given data.
+func NewModel(headers []*csvpp.ColumnHeader, records [][]*csvpp.Field, opts ...ModelOption) Model {
+	// Build table rows (first column is selection marker)
 	rows := make([]table.Row, len(records))
 	for i, record := range records {
 		row := make(table.Row, len(record)+1)
@@ -123,43 +113,29 @@ func NewModel(headers []*csvpp.ColumnHeader, records [][]*csvpp.Field) Model {
 			if j < len(headers) {
 				header = headers[j]
 			}
-			value := formatFieldValue(header, field)
-			row[j+1] = value
-			// Adjust column width
-			if len(value) > columns[j+1].Width {
-				columns[j+1].Width = min(len(value), 50) // Cap at 50
-			}
+			row[j+1] = formatFieldValue(header, field)
 		}
 		rows[i] = row
 	}
 
 	// Create table
 	t := table.New( //nostyle:funcfmt
-		table.WithColumns(columns),
-		table.WithRows(rows),
 		table.WithFocused(true),
 		table.WithHeight(10),
 	)
 
-	// Apply styles
-	s := table.DefaultStyles()
-	s.Header = styles.Header
-	s.Selected = styles.Selected
-	s.Cell = styles.Cell
-	t.SetStyles(s)
-
 	// Initialize filter input
 	fi := textinput.New()
 	fi.Placeholder = "type to filter..."
 	fi.CharLimit = 256
 
-	// Build allRows cache
-	allRows := make([]table.Row, len(rows))
-	for i, row := range rows {
-		r := make(table.Row, len(row))
-		copy(r, row)
-		allRows[i] = r
-	}
+	// Initialize edit input
+	ei := textinput.New()
+	ei.Prompt = ""
+
+	// Initialize export input
+	xi := textinput.New()
+	xi.Prompt = ""
 
 	// Build initial filteredIdx (1:1 mapping)
 	filteredIdx := make([]int, len(rows))
@@ -167,20 +143,44 @@ func NewModel(headers []*csvpp.ColumnHeader, records [][]*csvpp.Field) Model {
 		filteredIdx[i] = i
 	}
 
-	return Model{
+	m := Model{
 		table:       t,
 		headers:     headers,
 		records:     records,
-		styles:      styles,
+		styles:      DefaultStyles(),
+		keys:        DefaultKeyMap(),
 		selected:    make(map[int]bool),
 		filterInput: fi,
 		filteredIdx: filteredIdx,
-		allRows:     allRows,
+		allRows:     rows,
+		widths:      autoFitWidths(headers, rows),
+		hidden:      make([]bool, len(headers)),
+		editInput:   ei,
+		exportInput: xi,
 	}
+	for _, opt := range opts {
+		opt(&m)
+	}
+
+	// Apply styles and key bindings
+	s := table.DefaultStyles()
+	s.Header = m.styles.Header
+	s.Selected = m.styles.Selected
+	s.Cell = m.styles.Cell
+	m.table.SetStyles(s)
+	m.table.KeyMap = m.keys.Table
+	m.help = newHelp(m.styles)
+
+	m.fitWidths = slices.Clone(m.widths)
+	m.refreshTable()
+	return m
 }
 
 // Init implements tea.Model.
 func (m Model) Init() tea.Cmd { //nostyle:recvtype
+	if m.loader != nil {
+		return m.loader.next()
+	}
 	return nil
 }
 
@@ -190,25 +190,58 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) { //nostyle:recvtype
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		m.notice = ""
+		if m.confirm != nil {
+			return m.updateConfirmMode(msg)
+		}
+		if m.editing {
+			return m.updateEditMode(msg)
+		}
+		if m.exporting {
+			return m.updateExportMode(msg)
+		}
+		if m.detail != nil {
+			return m.updateDetailMode(msg)
+		}
+		if m.stats != nil {
+			return m.updateStatsMode(msg)
+		}
 		if m.filtering {
 			return m.updateFilterMode(msg)
 		}
 		return m.updateNormalMode(msg)
+	case recordsMsg:
+		return m, m.appendRecords(msg)
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
 		m.table.SetWidth(msg.Width)
-		m.table.SetHeight(msg.Height - 4) // Leave room for status
+		m.help.Width = msg.Width
+		m.layout()
+		m.scrollToColumn()
+		m.refreshTable()
 	}
 
 	m.table, cmd = m.table.Update(msg)
 	return m, cmd
 }
 
+// layout sizes the table to leave room for the status and help lines.
+func (m *Model) layout() {
+	if m.height == 0 {
+		return // size not known yet
+	}
+	m.table.SetHeight(m.height - 3 - lipgloss.Height(m.help.View(m.keys)))
+}
+
 // updateFilterMode handles key events when filter input is active.
 func (m Model) updateFilterMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) { //nostyle:recvtype
 	switch msg.Type {
 	case tea.KeyEnter:
+		if m.filterErr != nil {
+			// Keep editing until the filter parses
+			return m, nil
+		}
 		// Commit filter
 		m.filterText = m.filterInput.Value()
 		m.filtering = false
@@ -220,6 +253,7 @@ func (m Model) updateFilterMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) { //nostyle
 		// Cancel filter
 		m.filtering = false
 		m.filterInput.SetValue("")
+		m.filterErr = nil
 		m.filterInput.Blur()
 		m.table.Focus()
 		m.clearFilter()
@@ -238,16 +272,33 @@ func (m Model) updateFilterMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) { //nostyle
 
 // updateNormalMode handles key events in normal navigation mode.
 func (m Model) updateNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) { //nostyle:recvtype
-	switch msg.String() {
-	case "q", "ctrl+c":
+	quitArmed := m.quitArmed
+	m.quitArmed = false
+
+	if msg.String() == "ctrl+c" {
+		return m, tea.Quit
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		if m.dirty && !quitArmed {
+			m.quitArmed = true
+			m.notice = fmt.Sprintf("unsaved changes: press %s again to quit, %s to save", m.keys.Quit.Help().Key, m.keys.Save.Help().Key)
+			return m, nil
+		}
 		return m, tea.Quit
-	case "/":
+	case key.Matches(msg, m.keys.Help):
+		m.help.ShowAll = !m.help.ShowAll
+		m.layout()
+		m.refreshTable()
+		return m, nil
+	case key.Matches(msg, m.keys.Filter):
 		// Enter filter mode
 		m.filtering = true
 		m.filterInput.SetValue("")
 		m.table.Blur()
 		return m, m.filterInput.Focus()
-	case "esc":
+	case key.Matches(msg, m.keys.Clear):
 		if m.filterText != "" {
 			// Clear active filter
 			m.clearFilter()
@@ -255,10 +306,10 @@ func (m Model) updateNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) { //nostyle
 			// Clear selection
 			m.selected = make(map[int]bool)
 			m.copied = false
-			m.rebuildRowMarkers()
+			m.refreshTable()
 		}
 		return m, nil
-	case " ":
+	case key.Matches(msg, m.keys.Select):
 		// Toggle selection using original index
 		origIdx := m.originalIndex()
 		if origIdx < 0 {
@@ -270,77 +321,160 @@ func (m Model) updateNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) { //nostyle
 			m.selected[origIdx] = true
 		}
 		m.copied = false
-		m.rebuildRowMarkers()
+		m.refreshTable()
 		return m, nil
-	case "y", "c":
+	case key.Matches(msg, m.keys.Copy):
 		// Copy selected rows
 		if len(m.selected) > 0 {
 			m.copyToClipboard()
 		}
 		return m, nil
+	case key.Matches(msg, m.keys.CopyFormat):
+		m.cycleCopyFormat()
+		return m, nil
+	case key.Matches(msg, m.keys.Export):
+		return m, m.startExport()
+	case key.Matches(msg, m.keys.ColumnLeft):
+		m.moveColumn(-1)
+		return m, nil
+	case key.Matches(msg, m.keys.ColumnRight):
+		m.moveColumn(1)
+		return m, nil
+	case key.Matches(msg, m.keys.Widen):
+		m.resizeColumn(resizeStep)
+		return m, nil
+	case key.Matches(msg, m.keys.Narrow):
+		m.resizeColumn(-resizeStep)
+		return m, nil
+	case key.Matches(msg, m.keys.Fit):
+		m.fitColumn()
+		return m, nil
+	case key.Matches(msg, m.keys.Hide):
+		m.hideColumn()
+		return m, nil
+	case key.Matches(msg, m.keys.ShowAll):
+		m.showAllColumns()
+		return m, nil
+	case key.Matches(msg, m.keys.Details):
+		m.openDetail()
+		return m, nil
+	case key.Matches(msg, m.keys.Stats):
+		m.openStats()
+		return m, nil
+	case key.Matches(msg, m.keys.SortAsc):
+		m.sortBy(m.column, sortAsc)
+		return m, nil
+	case key.Matches(msg, m.keys.SortDesc):
+		m.sortBy(m.column, sortDesc)
+		return m, nil
+	case key.Matches(msg, m.keys.Edit):
+		return m, m.startEdit()
+	case key.Matches(msg, m.keys.Insert):
+		m.insertRecord()
+		return m, nil
+	case key.Matches(msg, m.keys.Delete):
+		m.deleteRecord()
+		return m, nil
+	case key.Matches(msg, m.keys.Save):
+		m.requestSave()
+		return m, nil
+	case key.Matches(msg, m.keys.Table.GotoTop):
+		m.setCursor(0)
+		m.table.GotoTop()
+		return m, nil
+	case key.Matches(msg, m.keys.Table.GotoBottom):
+		m.setCursor(len(m.filteredIdx) - 1)
+		m.table.GotoBottom()
+		return m, nil
 	}
 
 	var cmd tea.Cmd
 	m.table, cmd = m.table.Update(msg)
+	m.followCursor()
 	return m, cmd
 }
 
 // originalIndex returns the original record index for the current cursor position.
 // Returns -1 if the cursor position is out of range.
 func (m *Model) originalIndex() int {
-	cursor := m.table.Cursor()
+	cursor := m.cursor()
 	if cursor < 0 || cursor >= len(m.filteredIdx) {
 		return -1
 	}
 	return m.filteredIdx[cursor]
 }
 
-// rebuildRowMarkers updates selection markers in the currently displayed rows.
-func (m *Model) rebuildRowMarkers() {
-	rows := m.table.Rows()
-	for i, origIdx := range m.filteredIdx {
-		if i >= len(rows) {
-			break
-		}
-		if m.selected[origIdx] {
-			rows[i][0] = "✓"
-		} else {
-			rows[i][0] = " "
-		}
+// sortBy sorts the rows by column col in the given order. Sorting again by the
+// same column and order restores the file order. The cursor stays on the
+// record it was on.
+func (m *Model) sortBy(col int, order sortOrder) {
+	if col < 0 || col >= len(m.headers) {
+		return
 	}
-	m.table.SetRows(rows)
+	if m.sortOrder == order && m.sortCol == col {
+		m.sortOrder = sortNone
+		m.order = nil
+	} else {
+		m.sortCol = col
+		m.sortOrder = order
+		m.order, m.numeric = sortedIndices(m.allRows, col, order)
+	}
+
+	m.refreshRows(m.originalIndex())
+}
+
+// refreshRows applies the sort order and filter to the records again, keeping
+// the cursor on record keep if it is shown.
+func (m *Model) refreshRows(keep int) {
+	if m.sortOrder != sortNone {
+		m.order, m.numeric = sortedIndices(m.allRows, m.sortCol, m.sortOrder)
+	}
+	if m.filterText != "" {
+		m.applyFilter()
+	} else {
+		m.restoreAllRows()
+	}
+	if pos := slices.Index(m.filteredIdx, keep); pos >= 0 {
+		m.setCursor(pos)
+	}
+}
+
+// recordOrder returns the original record indices in display order, before filtering.
+func (m *Model) recordOrder() []int {
+	if m.order != nil {
+		return m.order
+	}
+	order := make([]int, len(m.allRows))
+	for i := range order {
+		order[i] = i
+	}
+	return order
 }
 
 // applyFilter filters rows based on the current filter input value.
+// If the input does not parse, filterErr is set and the rows are left as they are.
 func (m *Model) applyFilter() {
-	query := parseFilterQuery(m.filterInput.Value())
+	query, err := parseFilterQuery(m.filterInput.Value(), m.headers)
+	m.filterErr = err
+	if err != nil {
+		return
+	}
 
-	if query.value == "" {
+	if len(query.terms) == 0 {
 		// Show all rows without clearing committed filter state
 		m.restoreAllRows()
 		return
 	}
 
-	var filtered []table.Row
 	var idx []int
-
-	for i, row := range m.allRows {
-		if matchesFilter(query, m.headers, row) {
-			r := make(table.Row, len(row))
-			copy(r, row)
-			// Set selection marker
-			if m.selected[i] {
-				r[0] = "✓"
-			} else {
-				r[0] = " "
-			}
-			filtered = append(filtered, r)
+	for _, i := range m.recordOrder() {
+		if matchesFilter(query, csvpp.NewRecord(m.headers, m.records[i]), m.allRows[i]) {
 			idx = append(idx, i)
 		}
 	}
 
 	m.filteredIdx = idx
-	m.table.SetRows(filtered)
+	m.setCursor(0)
 	m.table.GotoTop()
 }
 
@@ -352,57 +486,29 @@ func (m *Model) clearFilter() {
 
 // restoreAllRows restores all rows to the table without modifying filter state.
 func (m *Model) restoreAllRows() {
-	rows := make([]table.Row, len(m.allRows))
-	for i, row := range m.allRows {
-		r := make(table.Row, len(row))
-		copy(r, row)
-		if m.selected[i] {
-			r[0] = "✓"
-		} else {
-			r[0] = " "
-		}
-		rows[i] = r
-	}
-
-	filteredIdx := make([]int, len(m.allRows))
-	for i := range filteredIdx {
-		filteredIdx[i] = i
-	}
-
-	m.filteredIdx = filteredIdx
-	m.table.SetRows(rows)
+	m.filteredIdx = slices.Clone(m.recordOrder())
+	m.refreshTable()
 }
 
-// copyToClipboard copies selected rows to clipboard in CSVPP format.
-func (m *Model) copyToClipboard() {
+// writeClipboard writes text to the clipboard and reports whether it succeeded.
+// On failure m.err is set.
+func (m *Model) writeClipboard(text []byte) bool {
 	if err := clipboard.Init(); err != nil {
 		m.err = fmt.Errorf("clipboard init: %w", err)
-		return
+		return false
 	}
+	clipboard.Write(clipboard.FmtText, text)
+	return true
+}
 
+// copyToClipboard copies selected rows to clipboard in the copy format.
+func (m *Model) copyToClipboard() {
 	var buf bytes.Buffer
-	w := csvpp.NewWriter(&buf)
-	w.SetHeaders(m.headers)
-
-	// Write header
-	if err := w.WriteHeader(); err != nil {
-		m.err = fmt.Errorf("write header: %w", err)
+	if err := writeRecords(&buf, m.copyFormat, m.headers, m.exportedRecords()); err != nil {
+		m.err = fmt.Errorf("write records: %w", err)
 		return
 	}
-
-	// Write selected records in order
-	for i := 0; i < len(m.records); i++ {
-		if m.selected[i] {
-			if err := w.Write(m.records[i]); err != nil {
-				m.err = fmt.Errorf("write record: %w", err)
-				return
-			}
-		}
-	}
-	w.Flush()
-
-	clipboard.Write(clipboard.FmtText, buf.Bytes())
-	m.copied = true
+	m.copied = m.writeClipboard(buf.Bytes())
 }
 
 // View implements tea.Model.
@@ -410,6 +516,15 @@ func (m Model) View() string { //nostyle:recvtype
 	if m.err != nil {
 		return fmt.Sprintf("Error: %v\n", m.err)
 	}
+	if m.confirm != nil {
+		return m.confirmView()
+	}
+	if m.detail != nil {
+		return m.detailViewString()
+	}
+	if m.stats != nil {
+		return m.statsViewString()
+	}
 
 	var b strings.Builder
 
@@ -417,11 +532,23 @@ func (m Model) View() string { //nostyle:recvtype
 	b.WriteString(m.table.View())
 	b.WriteString("\n\n")
 
-	// Filter line
+	// Edit or filter line
+	if m.editing {
+		b.WriteString(m.editView())
+		return b.String()
+	}
+	if m.exporting {
+		b.WriteString(m.exportView())
+		return b.String()
+	}
 	if m.filtering {
 		b.WriteString(m.styles.FilterPrompt.Render("/"))
 		b.WriteString(m.filterInput.View())
 		b.WriteString("\n")
+		if m.filterErr != nil {
+			b.WriteString(m.styles.FilterError.Render(m.filterErr.Error()))
+			b.WriteString("\n")
+		}
 	} else if m.filterText != "" {
 		b.WriteString(m.styles.FilterActive.Render(fmt.Sprintf("Filter: %s", m.filterText)))
 		b.WriteString("\n")
@@ -435,22 +562,40 @@ func (m Model) View() string { //nostyle:recvtype
 	if len(m.selected) > 0 {
 		status += fmt.Sprintf(" | %d selected", len(m.selected))
 	}
+	if n := m.hiddenCount(); n > 0 {
+		status += fmt.Sprintf(" | %d hidden", n)
+	}
+	if m.loader != nil {
+		status += " | " + m.loader.status()
+	}
+	if m.sortOrder != sortNone {
+		status += fmt.Sprintf(" | sorted by %s %s", m.headers[m.sortCol].Name, m.sortOrder.arrow())
+	}
 	if m.copied {
-		status += " | Copied!"
+		status += fmt.Sprintf(" | Copied as %s!", m.copyFormat)
+	}
+	if m.dirty {
+		status += " | modified"
+	}
+	if m.notice != "" {
+		status += " | " + m.notice
 	}
 	b.WriteString(m.styles.Status.Render(status))
 	b.WriteString("\n")
 
 	// Help
-	var help string
 	if m.filtering {
-		help = "Enter: apply filter • Esc: cancel • type to filter"
-	} else if m.filterText != "" {
-		help = "↑/↓: navigate • Space: select • y/c: copy • /: filter • Esc: clear filter • q: quit"
-	} else {
-		help = "↑/↓: navigate • Space: select • y/c: copy • /: filter • Esc: clear • q: quit"
+		b.WriteString(m.styles.Help.Render("Enter: apply filter • Esc: cancel • terms: text col:text col=text /regexp/ col>n !term"))
+		return b.String()
+	}
+	keys := m.keys
+	if m.filterText != "" {
+		keys.Clear.SetHelp(keys.Clear.Help().Key, "clear filter")
+	}
+	if m.help.ShowAll {
+		keys.Help.SetHelp(keys.Help.Help().Key, "fewer keys")
 	}
-	b.WriteString(m.styles.Help.Render(help))
+	b.WriteString(m.help.View(keys))
 
 	return b.String()
 }
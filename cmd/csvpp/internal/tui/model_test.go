@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/google/go-cmp/cmp"
 
 	"github.com/osamingo/go-csvpp"
@@ -134,60 +135,142 @@ func TestNewModel(t *testing.T) {
 func TestParseFilterQuery(t *testing.T) {
 	t.Parallel()
 
+	headers := []*csvpp.ColumnHeader{
+		{Name: "name", Kind: csvpp.SimpleField},
+		{Name: "age", Kind: csvpp.SimpleField},
+		{Name: "geo", Kind: csvpp.StructuredField, Components: []*csvpp.ColumnHeader{
+			{Name: "lat", Kind: csvpp.SimpleField},
+			{Name: "lon", Kind: csvpp.SimpleField},
+		}},
+	}
+
 	tests := []struct {
-		name string
-		in   string
-		want tui.FilterQuery
+		name    string
+		in      string
+		want    []tui.FilterTerm
+		wantErr string
 	}{
 		{
 			name: "success: empty string",
 			in:   "",
-			want: tui.FilterQuery{},
 		},
 		{
 			name: "success: whitespace only",
 			in:   "   ",
-			want: tui.FilterQuery{},
 		},
 		{
 			name: "success: simple text searches all columns",
 			in:   "alice",
-			want: tui.FilterQuery{Column: "", Value: "alice"},
+			want: []tui.FilterTerm{{Op: ":", Value: "alice"}},
 		},
 		{
 			name: "success: preserves lowercase conversion",
 			in:   "ALICE",
-			want: tui.FilterQuery{Column: "", Value: "alice"},
+			want: []tui.FilterTerm{{Op: ":", Value: "alice"}},
 		},
 		{
 			name: "success: column specific search",
 			in:   "name:alice",
-			want: tui.FilterQuery{Column: "name", Value: "alice"},
-		},
-		{
-			name: "success: column specific with spaces trimmed",
-			in:   " name : alice ",
-			want: tui.FilterQuery{Column: "name", Value: "alice"},
+			want: []tui.FilterTerm{{Column: "name", Op: ":", Value: "alice"}},
 		},
 		{
-			name: "success: column name is lowercased",
+			name: "success: column name in other case",
 			in:   "Name:alice",
-			want: tui.FilterQuery{Column: "name", Value: "alice"},
+			want: []tui.FilterTerm{{Column: "Name", Op: ":", Value: "alice"}},
 		},
 		{
 			name: "success: empty column part searches all columns",
 			in:   ":alice",
-			want: tui.FilterQuery{Column: "", Value: "alice"},
+			want: []tui.FilterTerm{{Op: ":", Value: "alice"}},
 		},
 		{
 			name: "success: column with empty value",
 			in:   "name:",
-			want: tui.FilterQuery{Column: "name", Value: ""},
+			want: []tui.FilterTerm{{Column: "name", Op: ":"}},
 		},
 		{
 			name: "success: value with colon inside",
 			in:   "name:a:b",
-			want: tui.FilterQuery{Column: "name", Value: "a:b"},
+			want: []tui.FilterTerm{{Column: "name", Op: ":", Value: "a:b"}},
+		},
+		{
+			name: "success: several terms",
+			in:   "  alice  age>=30 ",
+			want: []tui.FilterTerm{{Op: ":", Value: "alice"}, {Column: "age", Op: ">=", Value: "30"}},
+		},
+		{
+			name: "success: quoted value keeps spaces",
+			in:   `name:"Alice Smith"`,
+			want: []tui.FilterTerm{{Column: "name", Op: ":", Value: "alice smith"}},
+		},
+		{
+			name: "success: negation and equality",
+			in:   "!name=Bob",
+			want: []tui.FilterTerm{{Negate: true, Column: "name", Op: "=", Value: "bob"}},
+		},
+		{
+			name: "success: regular expression keeps case and spaces",
+			in:   `/^A\/ b/ name:/e$/`,
+			want: []tui.FilterTerm{{Op: "/", Value: `^A\/ b`}, {Column: "name", Op: "/", Value: "e$"}},
+		},
+		{
+			name: "success: numeric comparisons on components",
+			in:   "geo.lat>35 geo.lon<=140.5 age<1e2",
+			want: []tui.FilterTerm{
+				{Column: "geo.lat", Op: ">", Value: "35"},
+				{Column: "geo.lon", Op: "<=", Value: "140.5"},
+				{Column: "age", Op: "<", Value: "1e2"},
+			},
+		},
+		{
+			name: "success: structured column by title",
+			in:   "geo(lat,lon):35",
+			want: []tui.FilterTerm{{Column: "geo(lat,lon)", Op: ":", Value: "35"}},
+		},
+		{
+			name:    "error: unknown column",
+			in:      "email:alice",
+			wantErr: `unknown column "email"`,
+		},
+		{
+			name:    "error: unknown component",
+			in:      "geo.alt>1",
+			wantErr: `unknown column "geo.alt"`,
+		},
+		{
+			name:    "error: comparison needs a number",
+			in:      "age>thirty",
+			wantErr: `age> needs a number, got "thirty"`,
+		},
+		{
+			name:    "error: incomplete comparison",
+			in:      "age>=",
+			wantErr: `age>= needs a number, got ""`,
+		},
+		{
+			name:    "error: unterminated regular expression",
+			in:      "name:/ab",
+			wantErr: "unterminated regular expression",
+		},
+		{
+			name:    "error: invalid regular expression",
+			in:      "/a(/",
+			wantErr: "missing closing )",
+		},
+		{
+			name:    "error: regular expression with equality",
+			in:      "name=/a/",
+			wantErr: "needs ':' after the column",
+		},
+		{
+			name:    "error: unterminated quote",
+			in:      `"new york`,
+			wantErr: "unterminated quoted value",
+		},
+		{
+			name:    "error: negation without term",
+			in:      "alice !",
+			wantErr: "nothing to negate",
 		},
 	}
 
@@ -195,7 +278,16 @@ func TestParseFilterQuery(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			got := tui.ParseFilterQuery(tt.in)
+			got, err := tui.ParseFilterQuery(tt.in, headers)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseFilterQuery(%q) error = %v, want containing %q", tt.in, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseFilterQuery(%q) unexpected error: %v", tt.in, err)
+			}
 			if diff := cmp.Diff(tt.want, got); diff != "" {
 				t.Errorf("ParseFilterQuery(%q) mismatch (-want +got):\n%s", tt.in, diff)
 			}
@@ -210,82 +302,430 @@ func TestMatchesFilter(t *testing.T) {
 		{Name: "name", Kind: csvpp.SimpleField},
 		{Name: "age", Kind: csvpp.SimpleField},
 		{Name: "city", Kind: csvpp.SimpleField},
+		{Name: "tags", Kind: csvpp.ArrayField},
+		{Name: "geo", Kind: csvpp.StructuredField, Components: []*csvpp.ColumnHeader{
+			{Name: "lat", Kind: csvpp.SimpleField},
+			{Name: "lon", Kind: csvpp.SimpleField},
+		}},
+		{Name: "address", Kind: csvpp.ArrayStructuredField, Components: []*csvpp.ColumnHeader{
+			{Name: "city", Kind: csvpp.SimpleField},
+			{Name: "zip", Kind: csvpp.SimpleField},
+		}},
 	}
 
-	// row[0] = selection marker, row[1..] = data columns
-	row := table.Row{" ", "Alice", "30", "Tokyo"}
+	fields := []*csvpp.Field{
+		{Value: "Alice"},
+		{Value: "30"},
+		{Value: "New York"},
+		{Values: []string{"go", "rust"}},
+		{Components: []*csvpp.Field{{Value: "40.7"}, {Value: "-74.0"}}},
+		{Components: []*csvpp.Field{
+			{Components: []*csvpp.Field{{Value: "Tokyo"}, {Value: "100"}}},
+			{Components: []*csvpp.Field{{Value: "Osaka"}, {Value: "530"}}},
+		}},
+	}
 
 	tests := []struct {
 		name  string
-		query tui.FilterQuery
-		row   table.Row
+		query string
 		want  bool
+	}{
+		{name: "success: empty query matches all", query: "", want: true},
+		{name: "success: all columns match by name", query: "alice", want: true},
+		{name: "success: all columns match by city", query: "york", want: true},
+		{name: "success: partial match", query: "ali", want: true},
+		{name: "error: no match in any column", query: "xyz", want: false},
+		{name: "success: column specific match", query: "name:alice", want: true},
+		{name: "error: column specific no match", query: "name:york", want: false},
+		{name: "success: column specific match by age", query: "age:30", want: true},
+		{name: "success: column with empty value matches all", query: "name:", want: true},
+		{name: "error: selection marker not searched", query: `"✓"`, want: false},
+		{name: "success: every term matches", query: "alice age:30", want: true},
+		{name: "error: one term does not match", query: "alice bob", want: false},
+		{name: "success: quoted value with space", query: `"new york"`, want: true},
+		{name: "success: exact match ignores case", query: "name=ALICE", want: true},
+		{name: "error: exact match is not partial", query: "name=ali", want: false},
+		{name: "success: negation", query: "!bob", want: true},
+		{name: "error: negated match", query: "!name:alice", want: false},
+		{name: "success: regular expression", query: "name:/^A.*e$/", want: true},
+		{name: "error: regular expression is case sensitive", query: "name:/^a/", want: false},
+		{name: "success: regular expression on all columns", query: `/^\d+$/`, want: true},
+		{name: "success: numeric greater", query: "age>29.5", want: true},
+		{name: "error: numeric greater on equal value", query: "age>30", want: false},
+		{name: "success: numeric greater or equal", query: "age>=30", want: true},
+		{name: "success: numeric less", query: "age<100", want: true},
+		{name: "error: numeric comparison on text", query: "name<100", want: false},
+		{name: "success: structured component", query: "geo.lat>40 geo.lon<0", want: true},
+		{name: "error: structured component out of range", query: "geo.lat>=41", want: false},
+		{name: "success: structured column by name searches display", query: "geo:40.7", want: true},
+		{name: "success: any array element", query: "tags=rust", want: true},
+		{name: "success: indexed array element", query: "tags[0]=go", want: true},
+		{name: "error: indexed array element no match", query: "tags[0]=rust", want: false},
+		{name: "error: array index beyond the end", query: "tags[5]:", want: true},
+		{name: "success: array-structured component", query: "address.city=osaka address.zip>500", want: true},
+		{name: "error: negated array-structured component", query: "!address[].city:kyo", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tui.MatchesFilter(tt.query, headers, fields)
+			if err != nil {
+				t.Fatalf("MatchesFilter(%q) unexpected error: %v", tt.query, err)
+			}
+			if got != tt.want {
+				t.Errorf("MatchesFilter(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestModel_Filter(t *testing.T) {
+	t.Parallel()
+
+	headers := []*csvpp.ColumnHeader{
+		{Name: "name", Kind: csvpp.SimpleField},
+		{Name: "age", Kind: csvpp.SimpleField},
+	}
+	records := [][]*csvpp.Field{
+		{{Value: "Alice"}, {Value: "30"}},
+		{{Value: "Bob"}, {Value: "25"}},
+		{{Value: "Carol"}, {Value: "41"}},
+	}
+
+	tests := []struct {
+		name    string
+		keys    []string
+		want    []int
+		wantErr string
+		wantIn  string // substring of View()
 	}{
 		{
-			name:  "success: empty query matches all",
-			query: tui.FilterQuery{Value: ""},
-			row:   row,
-			want:  true,
+			name: "success: typed query filters live",
+			keys: []string{"/", "a", "g", "e", ">", "2", "9"},
+			want: []int{0, 2},
 		},
 		{
-			name:  "success: all columns match by name",
-			query: tui.FilterQuery{Value: "alice"},
-			row:   row,
-			want:  true,
+			name: "success: committed query with several terms",
+			keys: []string{"/", "!", "b", "o", "b", " ", "a", "g", "e", "<", "4", "0", "enter"},
+			want: []int{0},
 		},
 		{
-			name:  "success: all columns match by city",
-			query: tui.FilterQuery{Value: "tokyo"},
-			row:   row,
-			want:  true,
+			name:    "error: parse error keeps the last rows and is shown",
+			keys:    []string{"/", "a", "g", "e", ">", "2", "9", " ", "!"},
+			want:    []int{0, 2},
+			wantErr: "nothing to negate",
+			wantIn:  "nothing to negate",
 		},
 		{
-			name:  "success: case insensitive match on row value",
-			query: tui.FilterQuery{Value: "alice"},
-			row:   table.Row{" ", "ALICE", "30", "Tokyo"},
-			want:  true,
+			name:    "error: enter does not commit a query that does not parse",
+			keys:    []string{"/", "b", "o", "b", " ", "!", "enter"},
+			want:    []int{1},
+			wantErr: "nothing to negate",
+			wantIn:  "Enter: apply filter",
 		},
 		{
-			name:  "success: partial match",
-			query: tui.FilterQuery{Value: "ali"},
-			row:   row,
-			want:  true,
+			name: "success: esc clears the parse error",
+			keys: []string{"/", "a", "g", "e", ">", "esc"},
+			want: []int{0, 1, 2},
 		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var m tea.Model = tui.NewModel(headers, records)
+			for _, k := range tt.keys {
+				m, _ = m.Update(keyMsg(k))
+			}
+			got := tui.FilteredIndices(m.(tui.Model))
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("FilteredIndices() mismatch (-want +got):\n%s", diff)
+			}
+			err := tui.FilterError(m.(tui.Model))
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("FilterError() = %v, want nil", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("FilterError() = %v, want containing %q", err, tt.wantErr)
+			}
+			if view := m.View(); !strings.Contains(view, tt.wantIn) {
+				t.Errorf("View() does not contain %q:\n%s", tt.wantIn, view)
+			}
+		})
+	}
+}
+
+func TestSortedIndices(t *testing.T) {
+	t.Parallel()
+
+	rows := []table.Row{
+		{" ", "item10", "10", "b"},
+		{" ", "Item2", "9.5", ""},
+		{" ", "item1", "", "a"},
+		{" ", "item03", "-3", "B"},
+	}
+
+	tests := []struct {
+		name string
+		col  int
+		desc bool
+		want []int
+	}{
+		{name: "success: natural order ascending", col: 0, want: []int{2, 1, 3, 0}},
+		{name: "success: natural order descending", col: 0, desc: true, want: []int{0, 3, 1, 2}},
+		{name: "success: numeric ascending with empty last", col: 1, want: []int{3, 1, 0, 2}},
+		{name: "success: numeric descending with empty last", col: 1, desc: true, want: []int{0, 1, 3, 2}},
+		{name: "success: equal values keep file order", col: 2, want: []int{2, 3, 0, 1}},
+		{name: "success: out of range column keeps file order", col: 5, want: []int{0, 1, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := tui.SortedIndices(rows, tt.col, tt.desc)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("SortedIndices() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMergeIndices(t *testing.T) {
+	t.Parallel()
+
+	rows := []table.Row{
+		{" ", "", "3"},
+		{" ", "b", "10"},
+		{" ", "", ""},
+		{" ", "a", "2"},
+		{" ", "c", "x"},
+		{" ", "b", "2"},
+	}
+
+	tests := []struct {
+		name  string
+		start int
+		col   int
+		desc  bool
+	}{
+		{name: "success: merge into natural order", start: 2, col: 0},
+		{name: "success: merge into descending order", start: 3, col: 0, desc: true},
+		{name: "success: equal values keep file order", start: 4, col: 0},
+		{name: "success: merge into numeric order", start: 2, col: 1},
+		{name: "success: non-number turns numeric into natural order", start: 4, col: 1},
+		{name: "success: only empty values so far", start: 1, col: 0},
+		{name: "success: nothing sorted yet", start: 0, col: 1, desc: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			want := tui.SortedIndices(rows, tt.col, tt.desc)
+			got := tui.MergeIndices(rows, tt.start, tt.col, tt.desc)
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("MergeIndices() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCompareNatural(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		a, b string
+		want int
+	}{
+		{name: "success: digits compared as numbers", a: "file2", b: "file10", want: -1},
+		{name: "success: case ignored", a: "B", b: "a", want: 1},
+		{name: "success: prefix first", a: "abc", b: "abcd", want: -1},
+		{name: "success: equal", a: "x1", b: "x1", want: 0},
+		{name: "success: leading zeros", a: "v007", b: "v8", want: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tui.CompareNatural(tt.a, tt.b); got != tt.want {
+				t.Errorf("CompareNatural(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestModel_Sort(t *testing.T) {
+	t.Parallel()
+
+	headers := []*csvpp.ColumnHeader{
+		{Name: "name", Kind: csvpp.SimpleField},
+		{Name: "age", Kind: csvpp.SimpleField},
+	}
+	records := [][]*csvpp.Field{
+		{{Value: "Carol"}, {Value: "41"}},
+		{{Value: "Alice"}, {Value: "9"}},
+		{{Value: "Bob"}, {Value: "30"}},
+		{{Value: "Alan"}, {Value: "25"}},
+	}
+
+	tests := []struct {
+		name string
+		keys []string
+		want []int
+	}{
+		{name: "success: ascending by first column", keys: []string{"s"}, want: []int{3, 1, 2, 0}},
+		{name: "success: descending by second column", keys: []string{"l", "S"}, want: []int{0, 2, 3, 1}},
+		{name: "success: same sort again restores file order", keys: []string{"s", "s"}, want: []int{0, 1, 2, 3}},
+		{name: "success: cursor stops at last column", keys: []string{"l", "l", "s"}, want: []int{1, 3, 2, 0}},
+		{name: "success: sort applies to filtered rows", keys: []string{"/", "a", "l", "enter", "l", "s"}, want: []int{1, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var m tea.Model = tui.NewModel(headers, records)
+			for _, k := range tt.keys {
+				m, _ = m.Update(keyMsg(k))
+			}
+			got := tui.FilteredIndices(m.(tui.Model))
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("FilteredIndices() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// keyMsg returns the key message for a key name such as "s", "enter" or "esc".
+func keyMsg(k string) tea.KeyMsg {
+	switch k {
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEsc}
+	case " ":
+		return tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+	default:
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+	}
+}
+
+func TestModel_Detail(t *testing.T) {
+	t.Parallel()
+
+	headers := []*csvpp.ColumnHeader{
+		{Name: "name", Kind: csvpp.SimpleField},
+		{Name: "tags", Kind: csvpp.ArrayField},
+		{Name: "geo", Kind: csvpp.StructuredField, Components: []*csvpp.ColumnHeader{
+			{Name: "lat", Kind: csvpp.SimpleField},
+			{Name: "lon", Kind: csvpp.SimpleField},
+		}},
+		{Name: "address", Kind: csvpp.ArrayStructuredField, Components: []*csvpp.ColumnHeader{
+			{Name: "city", Kind: csvpp.SimpleField},
+			{Name: "phones", Kind: csvpp.ArrayField},
+		}},
+	}
+	records := [][]*csvpp.Field{
+		{{Value: "Bob"}, {}, {}, {}},
 		{
-			name:  "error: no match in any column",
-			query: tui.FilterQuery{Value: "xyz"},
-			row:   row,
-			want:  false,
+			{Value: "Alice"},
+			{Values: []string{"go", "rust"}},
+			{Components: []*csvpp.Field{{Value: "35.6"}, {Value: "139.7"}}},
+			{Components: []*csvpp.Field{
+				{Components: []*csvpp.Field{{Value: "Tokyo"}, {Values: []string{"111"}}}},
+			}},
 		},
+	}
+
+	tests := []struct {
+		name      string
+		keys      []string
+		wantLines []string
+		wantPath  string
+	}{
 		{
-			name:  "success: column specific match",
-			query: tui.FilterQuery{Column: "name", Value: "alice"},
-			row:   row,
-			want:  true,
+			name: "success: expanded tree of the record under the cursor",
+			keys: []string{"j", "enter"},
+			wantLines: []string{
+				"  name: Alice",
+				"▾ tags[] (2)",
+				"    [0]: go",
+				"    [1]: rust",
+				"▾ geo",
+				"    lat: 35.6",
+				"    lon: 139.7",
+				"▾ address[] (1)",
+				"  ▾ [0]",
+				"      city: Tokyo",
+				"    ▾ phones[] (1)",
+				"        [0]: 111",
+			},
+			wantPath: "name",
 		},
 		{
-			name:  "error: column specific no match",
-			query: tui.FilterQuery{Column: "name", Value: "tokyo"},
-			row:   row,
-			want:  false,
+			name: "success: path of a nested array element",
+			keys: []string{"j", "enter", "G"},
+			wantLines: []string{
+				"  name: Alice",
+				"▾ tags[] (2)",
+				"    [0]: go",
+				"    [1]: rust",
+				"▾ geo",
+				"    lat: 35.6",
+				"    lon: 139.7",
+				"▾ address[] (1)",
+				"  ▾ [0]",
+				"      city: Tokyo",
+				"    ▾ phones[] (1)",
+				"        [0]: 111",
+			},
+			wantPath: "address[0].phones[0]",
 		},
 		{
-			name:  "error: nonexistent column",
-			query: tui.FilterQuery{Column: "email", Value: "alice"},
-			row:   row,
-			want:  false,
+			name: "success: left collapses and then moves to the parent",
+			keys: []string{"j", "enter", "j", "j", "h", "h"},
+			wantLines: []string{
+				"  name: Alice",
+				"▸ tags[] (2)",
+				"▾ geo",
+				"    lat: 35.6",
+				"    lon: 139.7",
+				"▾ address[] (1)",
+				"  ▾ [0]",
+				"      city: Tokyo",
+				"    ▾ phones[] (1)",
+				"        [0]: 111",
+			},
+			wantPath: "tags",
+		},
+		{
+			name:      "success: collapse all",
+			keys:      []string{"j", "enter", "G", "E"},
+			wantLines: []string{"  name: Alice", "▸ tags[] (2)", "▸ geo", "▸ address[] (1)"},
+			wantPath:  "address",
+		},
+		{
+			name:      "success: follows the sorted order",
+			keys:      []string{"s", "g", "enter", "E"},
+			wantLines: []string{"  name: Alice", "▸ tags[] (2)", "▸ geo", "▸ address[] (1)"},
+			wantPath:  "name",
 		},
 		{
-			name:  "success: column specific match by age",
-			query: tui.FilterQuery{Column: "age", Value: "30"},
-			row:   row,
-			want:  true,
+			name:      "success: empty values",
+			keys:      []string{"enter"},
+			wantLines: []string{"  name: Bob", "▾ tags[] (0)", "▾ geo", "    lat: ", "    lon: ", "▾ address[] (0)"},
+			wantPath:  "name",
 		},
 		{
-			name:  "error: selection marker not searched",
-			query: tui.FilterQuery{Value: "✓"},
-			row:   table.Row{"✓", "Alice", "30", "Tokyo"},
-			want:  false,
+			name:     "success: esc returns to the table",
+			keys:     []string{"enter", "esc"},
+			wantPath: "",
 		},
 	}
 
@@ -293,9 +733,130 @@ func TestMatchesFilter(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			got := tui.MatchesFilter(tt.query, headers, tt.row)
-			if got != tt.want {
-				t.Errorf("MatchesFilter() = %v, want %v", got, tt.want)
+			var m tea.Model = tui.NewModel(headers, records)
+			for _, k := range tt.keys {
+				m, _ = m.Update(keyMsg(k))
+			}
+			if diff := cmp.Diff(tt.wantLines, tui.DetailLines(m.(tui.Model))); diff != "" {
+				t.Errorf("DetailLines() mismatch (-want +got):\n%s", diff)
+			}
+			if got := tui.DetailPath(m.(tui.Model)); got != tt.wantPath {
+				t.Errorf("DetailPath() = %q, want %q", got, tt.wantPath)
+			}
+			if !strings.Contains(m.View(), "Record") && tt.wantLines != nil {
+				t.Errorf("View() does not show the detail view:\n%s", m.View())
+			}
+		})
+	}
+}
+
+func TestModel_Columns(t *testing.T) {
+	t.Parallel()
+
+	headers := []*csvpp.ColumnHeader{
+		{Name: "id", Kind: csvpp.SimpleField},
+		{Name: "name", Kind: csvpp.SimpleField},
+		{Name: "city", Kind: csvpp.SimpleField},
+		{Name: "note", Kind: csvpp.SimpleField},
+	}
+	records := [][]*csvpp.Field{
+		{{Value: "1"}, {Value: "Alice"}, {Value: "Tokyo"}, {Value: strings.Repeat("x", 80)}},
+	}
+
+	tests := []struct {
+		name  string
+		width int
+		keys  []string
+		want  []table.Column
+	}{
+		{
+			name: "success: auto-fit widths",
+			want: []table.Column{
+				{Title: "›id", Width: 4},
+				{Title: "name", Width: 6},
+				{Title: "city", Width: 6},
+				{Title: "note", Width: 50},
+			},
+		},
+		{
+			name:  "success: columns beyond the width are not shown",
+			width: 30,
+			want: []table.Column{
+				{Title: "›id", Width: 4},
+				{Title: "name", Width: 6},
+				{Title: "city", Width: 6},
+			},
+		},
+		{
+			name:  "success: first column stays while scrolling",
+			width: 30,
+			keys:  []string{"l", "l", "l"},
+			want: []table.Column{
+				{Title: "id", Width: 4},
+				{Title: "›note", Width: 50},
+			},
+		},
+		{
+			name:  "success: scrolling back",
+			width: 30,
+			keys:  []string{"l", "l", "l", "h", "h"},
+			want: []table.Column{
+				{Title: "id", Width: 4},
+				{Title: "›name", Width: 6},
+				{Title: "city", Width: 6},
+			},
+		},
+		{
+			name: "success: widen, narrow and fit",
+			keys: []string{"l", "+", "+", "l", "-", "l", "-", "=", "-"},
+			want: []table.Column{
+				{Title: "id", Width: 4},
+				{Title: "name", Width: 10},
+				{Title: "city", Width: 4},
+				{Title: "›note", Width: 48},
+			},
+		},
+		{
+			name: "success: hide moves the cursor to the next column",
+			keys: []string{"l", "x"},
+			want: []table.Column{
+				{Title: "id", Width: 4},
+				{Title: "›city", Width: 6},
+				{Title: "note", Width: 50},
+			},
+		},
+		{
+			name: "success: hidden first column unfreezes the next",
+			keys: []string{"x", "l", "l", "x", "x"},
+			want: []table.Column{
+				{Title: "›name", Width: 6},
+			},
+		},
+		{
+			name: "success: show all",
+			keys: []string{"x", "x", "X"},
+			want: []table.Column{
+				{Title: "id", Width: 4},
+				{Title: "name", Width: 6},
+				{Title: "›city", Width: 6},
+				{Title: "note", Width: 50},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var m tea.Model = tui.NewModel(headers, records)
+			if tt.width > 0 {
+				m, _ = m.Update(tea.WindowSizeMsg{Width: tt.width, Height: 20})
+			}
+			for _, k := range tt.keys {
+				m, _ = m.Update(keyMsg(k))
+			}
+			if diff := cmp.Diff(tt.want, tui.TableColumns(m.(tui.Model))); diff != "" {
+				t.Errorf("TableColumns() mismatch (-want +got):\n%s", diff)
 			}
 		})
 	}
@@ -0,0 +1,46 @@
+package tui
+
+// minRowWindow is the least number of rows the table holds at a time. The
+// table only holds the displayed rows around the cursor, so that refreshing
+// it takes the same time however many records there are.
+const minRowWindow = 512
+
+// rowWindow returns the number of rows the table holds at a time. It leaves
+// more than a screen of rows on both sides of the cursor, so that moving the
+// window does not change what the table shows.
+func (m *Model) rowWindow() int {
+	return max(minRowWindow, 4*m.table.Height())
+}
+
+// windowOffset returns the first of n displayed rows the table should hold
+// to show row pos, keeping offset if pos is at least a screen away from both
+// ends of the current window.
+func windowOffset(offset, pos, n, size, height int) int {
+	if n <= size {
+		return 0
+	}
+	local := pos - offset
+	if offset+size <= n && local >= height && local < size-height {
+		return offset
+	}
+	return max(0, min(pos-size/2, n-size))
+}
+
+// cursor returns the position of the cursor among the displayed rows (filteredIdx).
+func (m *Model) cursor() int {
+	return m.rowOffset + m.table.Cursor()
+}
+
+// setCursor moves the cursor to position pos among the displayed rows.
+func (m *Model) setCursor(pos int) {
+	m.showRows(pos)
+}
+
+// followCursor moves the window of rows the table holds after the table
+// moved its cursor, if the cursor came near an end of the window.
+func (m *Model) followCursor() {
+	pos := m.cursor()
+	if windowOffset(m.rowOffset, pos, len(m.filteredIdx), m.rowWindow(), m.table.Height()) != m.rowOffset {
+		m.showRows(pos)
+	}
+}
@@ -0,0 +1,226 @@
+package tui
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+)
+
+// sortOrder is the order rows are sorted in.
+type sortOrder int
+
+const (
+	sortNone sortOrder = iota // file order
+	sortAsc
+	sortDesc
+)
+
+// arrow returns the indicator shown next to the sorted column title.
+func (o sortOrder) arrow() string {
+	switch o {
+	case sortAsc:
+		return "▲"
+	case sortDesc:
+		return "▼"
+	default:
+		return ""
+	}
+}
+
+// sortedIndices returns the indices of rows sorted by the data column col
+// (row[col+1]). Columns whose non-empty values are all numbers are compared
+// numerically, other columns in natural order ("item2" before "item10"),
+// ignoring case. Empty values sort last in both orders, and rows with equal
+// values keep their file order. It also reports whether the column was
+// compared numerically.
+func sortedIndices(rows []table.Row, col int, order sortOrder) (idx []int, numeric bool) {
+	values := make([]string, len(rows))
+	for i, row := range rows {
+		values[i] = cellValue(row, col)
+	}
+	numeric = isNumericColumn(values)
+	compare := valueComparator(order, numeric)
+
+	idx = make([]int, len(rows))
+	for i := range idx {
+		idx[i] = i
+	}
+	slices.SortStableFunc(idx, func(a, b int) int {
+		return compare(values[a], values[b])
+	})
+	return idx, numeric
+}
+
+// mergeIndices returns the order sortedIndices gives for rows, given sorted,
+// the order it gave for rows[:start] with numeric comparison if numeric is
+// true. Only rows[start:] are sorted, then merged into sorted, unless they
+// turn a numeric column into a non-numeric one.
+func mergeIndices(sorted []int, rows []table.Row, start, col int, order sortOrder, numeric bool) ([]int, bool) {
+	// Non-empty values sort first, so sorted has one if it starts with one.
+	hasValue := len(sorted) > 0 && cellValue(rows[sorted[0]], col) != ""
+	added := make([]string, len(rows)-start)
+	for i := range added {
+		added[i] = cellValue(rows[start+i], col)
+	}
+	switch {
+	case !hasValue:
+		// Only empty values so far, whose file order holds either way.
+		numeric = isNumericColumn(added)
+	case numeric && slices.ContainsFunc(added, isNonNumber):
+		return sortedIndices(rows, col, order)
+	}
+	compare := valueComparator(order, numeric)
+
+	idx := make([]int, len(added))
+	for i := range idx {
+		idx[i] = start + i
+	}
+	slices.SortStableFunc(idx, func(a, b int) int {
+		return compare(added[a-start], added[b-start])
+	})
+
+	merged := make([]int, 0, len(rows))
+	i, j := 0, 0
+	for i < len(sorted) && j < len(idx) {
+		// On ties the earlier record, which is in sorted, comes first.
+		if compare(cellValue(rows[sorted[i]], col), added[idx[j]-start]) <= 0 {
+			merged = append(merged, sorted[i])
+			i++
+		} else {
+			merged = append(merged, idx[j])
+			j++
+		}
+	}
+	merged = append(merged, sorted[i:]...)
+	merged = append(merged, idx[j:]...)
+	return merged, numeric
+}
+
+// cellValue returns the value of the data column col of row (row[col+1]).
+func cellValue(row table.Row, col int) string {
+	if col+1 < len(row) {
+		return row[col+1]
+	}
+	return ""
+}
+
+// valueComparator returns the function comparing two values in order,
+// numerically if numeric is true and in natural order otherwise.
+func valueComparator(order sortOrder, numeric bool) func(a, b string) int {
+	compare := compareNatural
+	if numeric {
+		compare = compareNumeric
+	}
+	return func(a, b string) int {
+		switch {
+		case a == "" || b == "":
+			// Empty values last, regardless of the order.
+			return cmp.Compare(boolRank(a == ""), boolRank(b == ""))
+		case order == sortDesc:
+			return compare(b, a)
+		default:
+			return compare(a, b)
+		}
+	}
+}
+
+// boolRank returns 1 for true and 0 for false.
+func boolRank(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// isNumericColumn reports whether values has at least one non-empty value and
+// every non-empty value is a number.
+func isNumericColumn(values []string) bool {
+	found := false
+	for _, v := range values {
+		if v == "" {
+			continue
+		}
+		if _, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err != nil {
+			return false
+		}
+		found = true
+	}
+	return found
+}
+
+// isNonNumber reports whether v is a non-empty value that is not a number.
+func isNonNumber(v string) bool {
+	if v == "" {
+		return false
+	}
+	_, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+	return err != nil
+}
+
+// compareNumeric compares two numbers. Both must be valid for strconv.ParseFloat.
+func compareNumeric(a, b string) int {
+	fa, _ := strconv.ParseFloat(strings.TrimSpace(a), 64)
+	fb, _ := strconv.ParseFloat(strings.TrimSpace(b), 64)
+	return cmp.Compare(fa, fb)
+}
+
+// compareNatural compares strings ignoring case, treating runs of digits as
+// numbers so that "item2" sorts before "item10".
+func compareNatural(a, b string) int {
+	ra, rb := []rune(strings.ToLower(a)), []rune(strings.ToLower(b))
+	i, j := 0, 0
+	for i < len(ra) && j < len(rb) {
+		if isDigit(ra[i]) && isDigit(rb[j]) {
+			ei, ej := digitRunEnd(ra, i), digitRunEnd(rb, j)
+			if c := compareDigits(ra[i:ei], rb[j:ej]); c != 0 {
+				return c
+			}
+			i, j = ei, ej
+			continue
+		}
+		if c := cmp.Compare(ra[i], rb[j]); c != 0 {
+			return c
+		}
+		i++
+		j++
+	}
+	if c := cmp.Compare(len(ra)-i, len(rb)-j); c != 0 {
+		return c
+	}
+	// Equal apart from case or leading zeros: fall back to the original text.
+	return strings.Compare(a, b)
+}
+
+// digitRunEnd returns the index after the run of digits starting at i.
+func digitRunEnd(r []rune, i int) int {
+	for i < len(r) && isDigit(r[i]) {
+		i++
+	}
+	return i
+}
+
+// isDigit reports whether r is an ASCII digit.
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+// compareDigits compares two runs of digits by numeric value.
+func compareDigits(a, b []rune) int {
+	a = trimLeadingZeros(a)
+	b = trimLeadingZeros(b)
+	if c := cmp.Compare(len(a), len(b)); c != 0 {
+		return c
+	}
+	return slices.Compare(a, b)
+}
+
+// trimLeadingZeros removes leading '0' digits, keeping at least one digit.
+func trimLeadingZeros(r []rune) []rune {
+	for len(r) > 1 && r[0] == '0' {
+		r = r[1:]
+	}
+	return r
+}
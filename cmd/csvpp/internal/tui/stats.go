@@ -0,0 +1,301 @@
+package tui
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/osamingo/go-csvpp"
+)
+
+// Statistics panel constants.
+const (
+	topValueCount = 10 // most frequent values listed
+	statsBarWidth = 20 // width of the longest bar
+)
+
+// valueCount is a value and the number of times it occurs.
+type valueCount struct {
+	value string
+	count int
+}
+
+// columnStats summarizes the values of one column in a set of records.
+type columnStats struct {
+	rows     int          // records summarized
+	empty    int          // records with no value in the column
+	values   int          // values: array elements count one each
+	distinct int          // distinct values
+	top      []valueCount // most frequent values, most frequent first
+	numeric  bool         // every value is a number
+	min, max float64
+	mean     float64
+	lengths  []valueCount // records by array length, for array columns
+}
+
+// cellValues returns the non-empty values of field f of a column described
+// by h: the value of a simple field, the elements of an array, or the
+// displayed value of a structured value or of each element of an
+// array-structured field.
+func cellValues(h *csvpp.ColumnHeader, f *csvpp.Field) []string {
+	if f == nil {
+		return nil
+	}
+	var values []string
+	switch h.Kind {
+	case csvpp.ArrayField:
+		for _, v := range f.Values {
+			if v != "" {
+				values = append(values, v)
+			}
+		}
+	case csvpp.StructuredField:
+		if !isEmptyField(f) {
+			values = append(values, formatStructuredValue(h.Components, f.Components))
+		}
+	case csvpp.ArrayStructuredField:
+		for _, elem := range f.Components {
+			if elem != nil && !isEmptyField(elem) {
+				values = append(values, formatStructuredValue(h.Components, elem.Components))
+			}
+		}
+	default:
+		if f.Value != "" {
+			values = append(values, f.Value)
+		}
+	}
+	return values
+}
+
+// isEmptyField reports whether f and its components hold no value.
+func isEmptyField(f *csvpp.Field) bool {
+	if f.Value != "" || slices.ContainsFunc(f.Values, func(v string) bool { return v != "" }) {
+		return false
+	}
+	for _, c := range f.Components {
+		if c != nil && !isEmptyField(c) {
+			return false
+		}
+	}
+	return true
+}
+
+// arrayLength returns the number of elements of f, an array or
+// array-structured field.
+func arrayLength(h *csvpp.ColumnHeader, f *csvpp.Field) int {
+	switch {
+	case f == nil:
+		return 0
+	case h.Kind == csvpp.ArrayField:
+		return len(f.Values)
+	default:
+		return len(f.Components)
+	}
+}
+
+// computeColumnStats summarizes column col of the records at indices idx.
+func computeColumnStats(h *csvpp.ColumnHeader, records [][]*csvpp.Field, col int, idx []int) columnStats {
+	isArray := h.Kind == csvpp.ArrayField || h.Kind == csvpp.ArrayStructuredField
+	counts := make(map[string]int)
+	lengths := make(map[int]int)
+	s := columnStats{rows: len(idx), numeric: true}
+	numbers, sum := 0, 0.0
+	for _, i := range idx {
+		var f *csvpp.Field
+		if col < len(records[i]) {
+			f = records[i][col]
+		}
+		if isArray {
+			lengths[arrayLength(h, f)]++
+		}
+
+		values := cellValues(h, f)
+		if len(values) == 0 {
+			s.empty++
+		}
+		for _, v := range values {
+			counts[v]++
+			if !s.numeric {
+				continue
+			}
+			n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil {
+				s.numeric = false
+				continue
+			}
+			if numbers == 0 || n < s.min {
+				s.min = n
+			}
+			if numbers == 0 || n > s.max {
+				s.max = n
+			}
+			sum += n
+			numbers++
+		}
+	}
+
+	for v, c := range counts {
+		s.values += c
+		s.top = append(s.top, valueCount{v, c})
+	}
+	s.distinct = len(s.top)
+	slices.SortFunc(s.top, func(a, b valueCount) int {
+		if c := cmp.Compare(b.count, a.count); c != 0 {
+			return c
+		}
+		return compareNatural(a.value, b.value)
+	})
+	s.top = s.top[:min(len(s.top), topValueCount)]
+	if s.numeric = s.numeric && numbers > 0; s.numeric {
+		s.mean = sum / float64(numbers)
+	}
+
+	for n, c := range lengths {
+		s.lengths = append(s.lengths, valueCount{strconv.Itoa(n), c})
+	}
+	slices.SortFunc(s.lengths, func(a, b valueCount) int { return compareNatural(a.value, b.value) })
+	return s
+}
+
+// lines returns the statistics as lines of text.
+func (s columnStats) lines() []string {
+	lines := []string{
+		fmt.Sprintf("Rows      %d", s.rows),
+		fmt.Sprintf("Empty     %d%s", s.empty, percent(s.empty, s.rows)),
+		fmt.Sprintf("Values    %d", s.values),
+		fmt.Sprintf("Distinct  %d", s.distinct),
+	}
+	if s.numeric {
+		lines = append(lines,
+			"",
+			"Numeric",
+			"  Min   "+formatNumber(s.min),
+			"  Max   "+formatNumber(s.max),
+			"  Mean  "+formatNumber(s.mean),
+		)
+	}
+	if len(s.top) > 0 {
+		lines = append(lines, "", fmt.Sprintf("Top values (of %d)", s.values))
+		lines = append(lines, countLines(s.top, s.values)...)
+	}
+	if len(s.lengths) > 0 {
+		lines = append(lines, "", "Array lengths (records)")
+		lines = append(lines, countLines(s.lengths, s.rows)...)
+	}
+	return lines
+}
+
+// countLines formats counts as aligned lines with a bar and the share of total.
+func countLines(counts []valueCount, total int) []string {
+	width, most := 0, 0
+	for _, c := range counts {
+		width = max(width, len([]rune(c.value)))
+		most = max(most, c.count)
+	}
+	width = min(width, maxColumnWidth)
+
+	lines := make([]string, 0, len(counts))
+	for _, c := range counts {
+		value := []rune(c.value)
+		if len(value) > width {
+			value = append(value[:width-1], '…')
+		}
+		bar := strings.Repeat("█", max(1, c.count*statsBarWidth/most))
+		lines = append(lines, fmt.Sprintf("  %-*s %6d%s %s", width, string(value), c.count, percent(c.count, total), bar))
+	}
+	return lines
+}
+
+// percent formats n as a share of total, such as " (25.0%)".
+func percent(n, total int) string {
+	if total == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (%.1f%%)", float64(n)*100/float64(total))
+}
+
+// formatNumber formats a statistic without trailing zeros.
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'g', 10, 64)
+}
+
+// statsView shows the statistics of a column over the displayed rows.
+type statsView struct {
+	lines  []string
+	offset int // first line shown
+}
+
+// openStats shows the statistics of the current column.
+func (m *Model) openStats() {
+	if m.column >= len(m.headers) {
+		return
+	}
+	m.stats = &statsView{}
+	m.refreshStats()
+}
+
+// refreshStats computes the statistics of the current column again.
+func (m *Model) refreshStats() {
+	s := computeColumnStats(m.headers[m.column], m.records, m.column, m.filteredIdx)
+	m.stats.lines = s.lines()
+	m.stats.offset = max(0, min(m.stats.offset, len(m.stats.lines)-m.detailHeight()))
+}
+
+// updateStatsMode handles key events when the statistics are shown.
+func (m Model) updateStatsMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) { //nostyle:recvtype
+	s := m.stats
+	height := m.detailHeight()
+	last := max(0, len(s.lines)-height)
+
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "q", "esc", "i":
+		m.stats = nil
+	case "left", "h":
+		m.moveColumn(-1)
+		m.refreshStats()
+	case "right", "l":
+		m.moveColumn(1)
+		m.refreshStats()
+	case "up", "k":
+		s.offset = max(0, s.offset-1)
+	case "down", "j":
+		s.offset = min(last, s.offset+1)
+	case "pgup", "b":
+		s.offset = max(0, s.offset-height)
+	case "pgdown", "f":
+		s.offset = min(last, s.offset+height)
+	}
+	return m, nil
+}
+
+// statsViewString renders the statistics.
+func (m Model) statsViewString() string { //nostyle:recvtype
+	s := m.stats
+	height := m.detailHeight()
+
+	title := fmt.Sprintf("Column %s", formatHeaderTitle(m.headers[m.column]))
+	if m.filterText != "" {
+		title += fmt.Sprintf(" (%d of %d records, filtered)", len(m.filteredIdx), len(m.records))
+	}
+
+	var b strings.Builder
+	b.WriteString(m.styles.Header.Render(title))
+	b.WriteString("\n")
+	for _, line := range s.lines[s.offset:min(s.offset+height, len(s.lines))] {
+		b.WriteString(m.styles.Cell.Render(line))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	if m.loader != nil {
+		b.WriteString(m.styles.Status.Render(m.loader.status()))
+		b.WriteString("\n")
+	}
+	b.WriteString(m.styles.Help.Render("←/→: column • ↑/↓: scroll • Esc: back"))
+	return b.String()
+}
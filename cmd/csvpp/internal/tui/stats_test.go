@@ -0,0 +1,198 @@
+package tui_test
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/osamingo/go-csvpp"
+	"github.com/osamingo/go-csvpp/cmd/csvpp/internal/tui"
+)
+
+func TestColumnStats(t *testing.T) {
+	t.Parallel()
+
+	headers := []*csvpp.ColumnHeader{
+		{Name: "name", Kind: csvpp.SimpleField},
+		{Name: "age", Kind: csvpp.SimpleField},
+		{Name: "tags", Kind: csvpp.ArrayField},
+		{Name: "geo", Kind: csvpp.StructuredField, Components: []*csvpp.ColumnHeader{
+			{Name: "lat", Kind: csvpp.SimpleField},
+			{Name: "lon", Kind: csvpp.SimpleField},
+		}},
+	}
+	records := [][]*csvpp.Field{
+		{{Value: "Alice"}, {Value: "30"}, {Values: []string{"go", "rust"}}, {Components: []*csvpp.Field{{Value: "1"}, {Value: "2"}}}},
+		{{Value: "Bob"}, {Value: "20"}, {Values: []string{"go"}}, {Components: []*csvpp.Field{{Value: ""}, {Value: ""}}}},
+		{{Value: "Alice"}, {Value: ""}, {}, nil},
+		{{Value: "Carol"}, {Value: "25.5"}, {Values: []string{"go", "zig"}}},
+	}
+
+	tests := []struct {
+		name string
+		col  int
+		idx  []int
+		want []string
+	}{
+		{
+			name: "success: text column",
+			col:  0,
+			idx:  []int{0, 1, 2, 3},
+			want: []string{
+				"Rows      4",
+				"Empty     0 (0.0%)",
+				"Values    4",
+				"Distinct  3",
+				"",
+				"Top values (of 4)",
+				"  Alice      2 (50.0%) ████████████████████",
+				"  Bob        1 (25.0%) ██████████",
+				"  Carol      1 (25.0%) ██████████",
+			},
+		},
+		{
+			name: "success: numeric column",
+			col:  1,
+			idx:  []int{0, 1, 2, 3},
+			want: []string{
+				"Rows      4",
+				"Empty     1 (25.0%)",
+				"Values    3",
+				"Distinct  3",
+				"",
+				"Numeric",
+				"  Min   20",
+				"  Max   30",
+				"  Mean  25.16666667",
+				"",
+				"Top values (of 3)",
+				"  20        1 (33.3%) ████████████████████",
+				"  25.5      1 (33.3%) ████████████████████",
+				"  30        1 (33.3%) ████████████████████",
+			},
+		},
+		{
+			name: "success: array column over filtered rows",
+			col:  2,
+			idx:  []int{3, 1, 2},
+			want: []string{
+				"Rows      3",
+				"Empty     1 (33.3%)",
+				"Values    3",
+				"Distinct  2",
+				"",
+				"Top values (of 3)",
+				"  go       2 (66.7%) ████████████████████",
+				"  zig      1 (33.3%) ██████████",
+				"",
+				"Array lengths (records)",
+				"  0      1 (33.3%) ████████████████████",
+				"  1      1 (33.3%) ████████████████████",
+				"  2      1 (33.3%) ████████████████████",
+			},
+		},
+		{
+			name: "success: structured column",
+			col:  3,
+			idx:  []int{0, 1, 2, 3},
+			want: []string{
+				"Rows      4",
+				"Empty     3 (75.0%)",
+				"Values    1",
+				"Distinct  1",
+				"",
+				"Top values (of 1)",
+				"  {lat:1, lon:2}      1 (100.0%) ████████████████████",
+			},
+		},
+		{
+			name: "success: no rows",
+			col:  0,
+			want: []string{
+				"Rows      0",
+				"Empty     0",
+				"Values    0",
+				"Distinct  0",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := tui.ColumnStats(headers[tt.col], records, tt.col, tt.idx)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ColumnStats() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestModel_Stats(t *testing.T) {
+	t.Parallel()
+
+	headers := []*csvpp.ColumnHeader{
+		{Name: "name", Kind: csvpp.SimpleField},
+		{Name: "age", Kind: csvpp.SimpleField},
+	}
+	records := [][]*csvpp.Field{
+		{{Value: "Alice"}, {Value: "30"}},
+		{{Value: "Bob"}, {Value: "20"}},
+		{{Value: "Carol"}, {Value: "40"}},
+	}
+
+	tests := []struct {
+		name    string
+		keys    []string
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "success: current column",
+			keys:    []string{"i"},
+			want:    []string{"Column name", "Rows      3", "Distinct  3"},
+			notWant: []string{"Numeric"},
+		},
+		{
+			name: "success: next column",
+			keys: []string{"i", "l"},
+			want: []string{"Column age", "Mean  30"},
+		},
+		{
+			name: "success: filtered rows",
+			keys: []string{"/", "age>25", "enter", "l", "i"},
+			want: []string{"Column age (2 of 3 records, filtered)", "Rows      2", "Mean  35"},
+		},
+		{
+			name:    "success: back to the table",
+			keys:    []string{"i", "esc"},
+			want:    []string{"3 records"},
+			notWant: []string{"Distinct"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var m tea.Model = tui.NewModel(headers, records)
+			for _, k := range tt.keys {
+				m, _ = m.Update(keyMsg(k))
+			}
+			view := m.View()
+			for _, want := range tt.want {
+				if !strings.Contains(view, want) {
+					t.Errorf("View() does not contain %q:\n%s", want, view)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(view, notWant) {
+					t.Errorf("View() contains %q:\n%s", notWant, view)
+				}
+			}
+		})
+	}
+}
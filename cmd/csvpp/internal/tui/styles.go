@@ -2,6 +2,8 @@
 package tui
 
 import (
+	"fmt"
+
 	"github.com/charmbracelet/lipgloss"
 )
 
@@ -10,7 +12,17 @@ var (
 	colorAccent  = lipgloss.Color("229") // yellow – header/selected foreground, filter prompt
 	colorPrimary = lipgloss.Color("57")  // purple – header/selected background
 	colorMuted   = lipgloss.Color("241") // gray   – help/status text
+	colorKey     = lipgloss.Color("246") // gray   – keys in the help line
 	colorFilter  = lipgloss.Color("86")  // green  – active filter indicator
+	colorAdded   = lipgloss.Color("42")  // green  – added diff lines
+	colorRemoved = lipgloss.Color("203") // red    – removed diff lines, filter errors
+)
+
+// Theme names accepted by ThemeStyles.
+const (
+	ThemeDefault      = "default"
+	ThemeHighContrast = "high-contrast"
+	ThemeNoColor      = "no-color"
 )
 
 // Styles holds the styles for the TUI components.
@@ -19,9 +31,13 @@ type Styles struct {
 	Cell         lipgloss.Style
 	Selected     lipgloss.Style
 	Help         lipgloss.Style
+	HelpKey      lipgloss.Style
 	Status       lipgloss.Style
 	FilterPrompt lipgloss.Style
 	FilterActive lipgloss.Style
+	FilterError  lipgloss.Style
+	DiffAdded    lipgloss.Style
+	DiffRemoved  lipgloss.Style
 }
 
 // DefaultStyles returns the default styles for the TUI.
@@ -31,8 +47,71 @@ func DefaultStyles() Styles {
 		Cell:         lipgloss.NewStyle().Padding(0, 1),
 		Selected:     lipgloss.NewStyle().Foreground(colorAccent).Background(colorPrimary).Padding(0, 1),
 		Help:         lipgloss.NewStyle().Foreground(colorMuted),
+		HelpKey:      lipgloss.NewStyle().Foreground(colorKey),
 		Status:       lipgloss.NewStyle().Foreground(colorMuted).Padding(0, 1),
 		FilterPrompt: lipgloss.NewStyle().Bold(true).Foreground(colorAccent),
 		FilterActive: lipgloss.NewStyle().Foreground(colorFilter),
+		FilterError:  lipgloss.NewStyle().Foreground(colorRemoved),
+		DiffAdded:    lipgloss.NewStyle().Foreground(colorAdded),
+		DiffRemoved:  lipgloss.NewStyle().Foreground(colorRemoved),
+	}
+}
+
+// HighContrastStyles returns styles using only the bright colors of the basic
+// 16-color palette, on the terminal's own background.
+func HighContrastStyles() Styles {
+	var (
+		black  = lipgloss.Color("0")
+		red    = lipgloss.Color("9")
+		green  = lipgloss.Color("10")
+		yellow = lipgloss.Color("11")
+		cyan   = lipgloss.Color("14")
+		white  = lipgloss.Color("15")
+	)
+	return Styles{
+		Header:       lipgloss.NewStyle().Bold(true).Foreground(black).Background(white).Padding(0, 1),
+		Cell:         lipgloss.NewStyle().Padding(0, 1),
+		Selected:     lipgloss.NewStyle().Bold(true).Foreground(black).Background(yellow).Padding(0, 1),
+		Help:         lipgloss.NewStyle().Foreground(white),
+		HelpKey:      lipgloss.NewStyle().Bold(true).Foreground(yellow),
+		Status:       lipgloss.NewStyle().Foreground(white).Padding(0, 1),
+		FilterPrompt: lipgloss.NewStyle().Bold(true).Foreground(yellow),
+		FilterActive: lipgloss.NewStyle().Bold(true).Foreground(cyan),
+		FilterError:  lipgloss.NewStyle().Bold(true).Foreground(red),
+		DiffAdded:    lipgloss.NewStyle().Foreground(green),
+		DiffRemoved:  lipgloss.NewStyle().Foreground(red),
+	}
+}
+
+// NoColorStyles returns styles without colors, telling the header and the
+// selected row apart by bold, underlined and reverse video text.
+func NoColorStyles() Styles {
+	return Styles{
+		Header:       lipgloss.NewStyle().Bold(true).Underline(true).Padding(0, 1),
+		Cell:         lipgloss.NewStyle().Padding(0, 1),
+		Selected:     lipgloss.NewStyle().Reverse(true).Padding(0, 1),
+		Help:         lipgloss.NewStyle(),
+		HelpKey:      lipgloss.NewStyle().Bold(true),
+		Status:       lipgloss.NewStyle().Padding(0, 1),
+		FilterPrompt: lipgloss.NewStyle().Bold(true),
+		FilterActive: lipgloss.NewStyle().Bold(true),
+		FilterError:  lipgloss.NewStyle().Bold(true),
+		DiffAdded:    lipgloss.NewStyle(),
+		DiffRemoved:  lipgloss.NewStyle(),
+	}
+}
+
+// ThemeStyles returns the styles of the named theme. An empty name is the
+// default theme.
+func ThemeStyles(name string) (Styles, error) {
+	switch name {
+	case "", ThemeDefault:
+		return DefaultStyles(), nil
+	case ThemeHighContrast:
+		return HighContrastStyles(), nil
+	case ThemeNoColor:
+		return NoColorStyles(), nil
+	default:
+		return Styles{}, fmt.Errorf("unknown theme %q (want %s, %s or %s)", name, ThemeDefault, ThemeHighContrast, ThemeNoColor)
 	}
 }
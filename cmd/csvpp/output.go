@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/osamingo/go-csvpp"
+	"github.com/osamingo/go-csvpp/csvpputil"
+	"github.com/osamingo/go-csvpp/csvpputil/parquet"
+)
+
+// recordWriter writes records one at a time in some output format.
+type recordWriter interface {
+	Write(record []*csvpp.Field) error
+	Close() error
+}
+
+// newRecordWriter returns a recordWriter for format that writes to w.
+func newRecordWriter(w io.Writer, format Format, headers []*csvpp.ColumnHeader) (recordWriter, error) {
+	switch format {
+	case FormatJSON:
+		return csvpputil.NewJSONArrayWriter(w, headers), nil
+	case FormatYAML:
+		return csvpputil.NewYAMLArrayWriter(w, headers), nil
+	case FormatNDJSON:
+		return csvpputil.NewNDJSONWriter(w, headers), nil
+	case FormatCSV:
+		return csvpputil.NewCSVWriter(w, headers), nil
+	case FormatTOML:
+		return csvpputil.NewTOMLWriter(w, headers), nil
+	case FormatXML:
+		return csvpputil.NewXMLWriter(w, headers), nil
+	case FormatMsgpack:
+		return csvpputil.NewMsgpackWriter(w, headers), nil
+	case FormatParquet:
+		return parquet.NewWriter(w, headers), nil
+	case FormatCSVPP:
+		writer := csvpp.NewWriter(w)
+		writer.SetHeaders(headers)
+		if err := writer.WriteHeader(); err != nil {
+			return nil, err
+		}
+		return &csvppRecordWriter{writer}, nil
+	default:
+		return nil, fmt.Errorf("unsupported output format: %s", format)
+	}
+}
+
+// csvppRecordWriter adapts csvpp.Writer to recordWriter.
+type csvppRecordWriter struct {
+	*csvpp.Writer
+}
+
+// Close flushes buffered records.
+func (w *csvppRecordWriter) Close() error {
+	w.Flush()
+	return w.Error()
+}
+
+// outputFormat determines the output format from the --to flag or the output file extension,
+// defaulting to CSV++.
+func outputFormat(to, outputFile string) Format {
+	if to != "" {
+		return Format(strings.ToLower(to))
+	}
+	if f := detectFormat(outputFile); f != "" {
+		return f
+	}
+	return FormatCSVPP
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/osamingo/go-csvpp"
+	"github.com/osamingo/go-csvpp/cmd/csvpp/internal/fileutil"
+	"github.com/osamingo/go-csvpp/cmd/csvpp/internal/query"
+)
+
+var queryCmd = &cobra.Command{
+	Use:   "query EXPR [file]",
+	Short: "Filter CSV++ records with an expression",
+	Long: `Filter CSV++ records with an expression and write the matching records.
+Reads from file or stdin if no file is specified.
+
+Expressions compare field paths with values and combine them with boolean logic:
+  name == "Alice"                      equality (also !=, <, <=, >, >=)
+  age >= 30                            numeric when both sides are numbers
+  geo.lat > 35                         structured component
+  tags contains "go"                   array membership (substring for plain fields)
+  name startswith "A"                  also endswith
+  email =~ "@example\.com$"            regular expression (also !~)
+  status in ("open", "pending")        list membership (also not in)
+  any(address[], city == "Tokyo")      some array element matches (also all)
+  any(tags, @ startswith "go")         "@" is the current element of a plain array
+  len(tags) > 2                        number of array elements
+  not active or (a == 1 && b != 2)     boolean logic
+
+Examples:
+  csvpp query 'age >= 30' people.csvpp
+  csvpp query 'any(address[], city == "Tokyo")' people.csvpp --to json
+  cat people.csvpp | csvpp query 'tags contains "go"' -o gophers.yaml`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runQuery,
+}
+
+func init() {
+	queryCmd.Flags().StringP("output", "o", "", "output file (writes to stdout if not specified)")
+	queryCmd.Flags().String("to", "", "output format (csvpp, json, ndjson, yaml, csv, toml, xml, msgpack, parquet) - defaults to the output file extension or csvpp")
+
+	rootCmd.AddCommand(queryCmd)
+}
+
+func runQuery(cmd *cobra.Command, args []string) (retErr error) {
+	outputFile, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return err
+	}
+	toFormat, err := cmd.Flags().GetString("to")
+	if err != nil {
+		return err
+	}
+
+	r, err := fileutil.OpenInputFromArgs(args[1:])
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := r.Close(); cerr != nil && retErr == nil {
+			retErr = fmt.Errorf("failed to close input: %w", cerr)
+		}
+	}()
+
+	reader := csvpp.NewReader(r)
+	headers, err := reader.Headers()
+	if err != nil {
+		return fmt.Errorf("failed to read headers: %w", err)
+	}
+
+	expr, err := query.Compile(args[0], headers)
+	if err != nil {
+		return err
+	}
+
+	w, err := fileutil.OpenOutput(outputFile, cmd.OutOrStdout())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := w.Close(); cerr != nil && retErr == nil {
+			retErr = fmt.Errorf("failed to close output: %w", cerr)
+		}
+	}()
+
+	out, err := newRecordWriter(w, outputFormat(toFormat, outputFile), headers)
+	if err != nil {
+		return err
+	}
+
+	for {
+		rec, err := reader.ReadRecord()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read record: %w", err)
+		}
+		if !expr.Match(rec) {
+			continue
+		}
+		if err := out.Write(rec.Fields); err != nil {
+			return fmt.Errorf("failed to write record: %w", err)
+		}
+	}
+
+	return out.Close()
+}
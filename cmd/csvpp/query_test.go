@@ -0,0 +1,95 @@
+package main_test
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestQueryCommand(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantErr    bool
+		wantOutput string
+	}{
+		{
+			name: "success: numeric comparison",
+			args: []string{"query", "age >= 30", "testdata/query/people.csvpp"},
+			wantOutput: "name,age,tags[],address[](street^city)\n" +
+				"Alice,30,go~rust,1-1 Chiyoda^Tokyo~5th Ave^New York\n" +
+				"Carol,41,,2-2 Umeda^Osaka\n",
+		},
+		{
+			name: "success: any over array structured field",
+			args: []string{"query", `any(address[], city == "Tokyo")`, "testdata/query/people.csvpp"},
+			wantOutput: "name,age,tags[],address[](street^city)\n" +
+				"Alice,30,go~rust,1-1 Chiyoda^Tokyo~5th Ave^New York\n",
+		},
+		{
+			name:       "success: json output",
+			args:       []string{"query", `tags contains "python"`, "testdata/query/people.csvpp", "--to", "json"},
+			wantOutput: `[{"name":"Bob","age":"25","tags":["python"],"address":[{"street":"Main St","city":"Los Angeles"}]}]`,
+		},
+		{
+			name: "success: yaml output",
+			args: []string{"query", `name == "Carol"`, "testdata/query/people.csvpp", "--to", "yaml"},
+			wantOutput: "- name: Carol\n" +
+				"  age: \"41\"\n" +
+				"  tags: []\n" +
+				"  address:\n" +
+				"  - street: 2-2 Umeda\n" +
+				"    city: Osaka\n",
+		},
+		{
+			name:       "success: no matches",
+			args:       []string{"query", `name == "Dave"`, "testdata/query/people.csvpp"},
+			wantOutput: "name,age,tags[],address[](street^city)\n",
+		},
+		{
+			name:    "error: unknown field",
+			args:    []string{"query", `email == "x"`, "testdata/query/people.csvpp"},
+			wantErr: true,
+		},
+		{
+			name:    "error: syntax error",
+			args:    []string{"query", `name ==`, "testdata/query/people.csvpp"},
+			wantErr: true,
+		},
+		{
+			name:    "error: unsupported format",
+			args:    []string{"query", `age > 1`, "testdata/query/people.csvpp", "--to", "html"},
+			wantErr: true,
+		},
+		{
+			name:    "error: missing expression",
+			args:    []string{"query"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			stdout, _, err := runCommand(t, tt.args...)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error but got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+
+			if strings.TrimSpace(stdout) != strings.TrimSpace(tt.wantOutput) {
+				t.Errorf("output = %q, want %q", stdout, tt.wantOutput)
+			}
+		})
+	}
+}
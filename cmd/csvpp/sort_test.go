@@ -0,0 +1,74 @@
+package main_test
+
+import (
+	"testing"
+)
+
+func TestSortCommand(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantErr    bool
+		wantOutput string
+	}{
+		{
+			name: "success: multiple keys",
+			args: []string{"sort", "-k", "age:num:desc", "-k", "name", "testdata/sort/people.csvpp"},
+			wantOutput: "name,age,tags[],geo(lat^lon)\n" +
+				"Carol,41,python,34.69^135.50\n" +
+				"Alice,30,go~rust,35.68^139.76\n" +
+				"Dave,30,go~rust~zig,51.51^-0.13\n" +
+				"Bob,25,,34.05^-118.24\n",
+		},
+		{
+			name: "success: component path and array length",
+			args: []string{"sort", "-k", "tags:len:desc", "-k", "geo.lat:num", "testdata/sort/people.csvpp"},
+			wantOutput: "name,age,tags[],geo(lat^lon)\n" +
+				"Dave,30,go~rust~zig,51.51^-0.13\n" +
+				"Alice,30,go~rust,35.68^139.76\n" +
+				"Carol,41,python,34.69^135.50\n" +
+				"Bob,25,,34.05^-118.24\n",
+		},
+		{
+			name:    "error: missing key",
+			args:    []string{"sort", "testdata/sort/people.csvpp"},
+			wantErr: true,
+		},
+		{
+			name:    "error: invalid key",
+			args:    []string{"sort", "-k", "name:sideways", "testdata/sort/people.csvpp"},
+			wantErr: true,
+		},
+		{
+			name:    "error: unknown field",
+			args:    []string{"sort", "-k", "email", "testdata/sort/people.csvpp"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			stdout, _, err := runCommand(t, tt.args...)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error but got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+
+			if stdout != tt.wantOutput {
+				t.Errorf("output = %q, want %q", stdout, tt.wantOutput)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+
+	"github.com/osamingo/go-csvpp"
+	"github.com/osamingo/go-csvpp/cmd/csvpp/internal/fileutil"
+	"github.com/osamingo/go-csvpp/csvppsql"
+)
+
+var sqlCmd = &cobra.Command{
+	Use:   "sql QUERY",
+	Short: "Run a SQL SELECT over CSV++ files",
+	Long: `Run a SQL SELECT statement over a CSV++ file and write the result.
+
+Each file is a table. Simple fields are columns, structured fields become dotted
+columns (geo.lat) and array fields, or anything inside them, are repeated columns
+written as CSV++ array fields. Dots in result column names are replaced with "_";
+result column names must be unique, so rename clashing columns with AS.
+
+Examples:
+  csvpp sql "SELECT name, geo.lat FROM 'people.csvpp' WHERE age >= 30 ORDER BY name"
+  csvpp sql "SELECT name, address.city FROM 'people.csvpp' WHERE address.city = 'Tokyo'" --to json
+  csvpp sql "SELECT * FROM 'people.csvpp' WHERE name LIKE 'A%' LIMIT 10" -o result.csvpp`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSQL,
+}
+
+func init() {
+	sqlCmd.Flags().StringP("output", "o", "", "output file (writes to stdout if not specified)")
+	sqlCmd.Flags().String("to", "", "output format (csvpp, json, ndjson, yaml, csv, toml, xml, msgpack, parquet) - defaults to the output file extension or csvpp")
+
+	rootCmd.AddCommand(sqlCmd)
+}
+
+func runSQL(cmd *cobra.Command, args []string) (retErr error) {
+	outputFile, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return err
+	}
+	toFormat, err := cmd.Flags().GetString("to")
+	if err != nil {
+		return err
+	}
+
+	db, err := sql.Open(csvppsql.DriverName, "")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := db.Close(); cerr != nil && retErr == nil {
+			retErr = cerr
+		}
+	}()
+
+	rows, err := db.QueryContext(cmd.Context(), args[0])
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := rows.Close(); cerr != nil && retErr == nil {
+			retErr = cerr
+		}
+	}()
+
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		return err
+	}
+	headers := make([]*csvpp.ColumnHeader, len(types))
+	seen := make(map[string]bool, len(types))
+	for i, ct := range types {
+		name := strings.ReplaceAll(ct.Name(), ".", "_")
+		if seen[name] {
+			return fmt.Errorf("output column %q appears more than once; rename it with AS", name)
+		}
+		seen[name] = true
+		headers[i] = &csvpp.ColumnHeader{
+			Name: name,
+			Kind: csvpp.SimpleField,
+		}
+		// Repeated columns are typed "TEXT[d]" and hold values joined with d.
+		if delim, ok := strings.CutPrefix(ct.DatabaseTypeName(), "TEXT["); ok {
+			headers[i].Kind = csvpp.ArrayField
+			headers[i].ArrayDelimiter, _ = utf8.DecodeRuneInString(delim)
+		}
+	}
+
+	w, err := fileutil.OpenOutput(outputFile, cmd.OutOrStdout())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := w.Close(); cerr != nil && retErr == nil {
+			retErr = fmt.Errorf("failed to close output: %w", cerr)
+		}
+	}()
+
+	out, err := newRecordWriter(w, outputFormat(toFormat, outputFile), headers)
+	if err != nil {
+		return err
+	}
+
+	for rows.Next() {
+		values := make([]string, len(headers))
+		dest := make([]any, len(headers))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return err
+		}
+		fields := make([]*csvpp.Field, len(headers))
+		for i, h := range headers {
+			switch {
+			case h.Kind != csvpp.ArrayField:
+				fields[i] = &csvpp.Field{Value: values[i]}
+			case values[i] == "":
+				fields[i] = &csvpp.Field{Values: []string{}}
+			default:
+				fields[i] = &csvpp.Field{Values: strings.Split(values[i], string(h.ArrayDelimiter))}
+			}
+		}
+		if err := out.Write(fields); err != nil {
+			return fmt.Errorf("failed to write record: %w", err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	return out.Close()
+}
@@ -0,0 +1,74 @@
+package main_test
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSQLCommand(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantErr    bool
+		wantOutput string
+	}{
+		{
+			name: "success: dotted and repeated columns",
+			args: []string{"sql", "SELECT name, address.city FROM 'testdata/sql/people.csvpp' WHERE age >= 30 ORDER BY age DESC"},
+			wantOutput: "name,address_city[]\n" +
+				"Carol,Osaka\n" +
+				"Alice,Tokyo~New York\n",
+		},
+		{
+			name:       "success: json output",
+			args:       []string{"sql", "SELECT name AS who, tags FROM 'testdata/sql/people.csvpp' WHERE tags = 'python'", "--to", "json"},
+			wantOutput: `[{"who":"Bob","tags":["python"]}]`,
+		},
+		{
+			name:    "error: syntax error",
+			args:    []string{"sql", "SELECT FROM"},
+			wantErr: true,
+		},
+		{
+			name:    "error: duplicate column",
+			args:    []string{"sql", "SELECT name, name FROM 'testdata/sql/people.csvpp'"},
+			wantErr: true,
+		},
+		{
+			name:    "error: dotted column clashes with alias",
+			args:    []string{"sql", "SELECT address.city, name AS address_city FROM 'testdata/sql/people.csvpp'"},
+			wantErr: true,
+		},
+		{
+			name:    "error: unknown column",
+			args:    []string{"sql", "SELECT email FROM 'testdata/sql/people.csvpp'"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			stdout, _, err := runCommand(t, tt.args...)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error but got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+
+			if strings.TrimSpace(stdout) != strings.TrimSpace(tt.wantOutput) {
+				t.Errorf("output = %q, want %q", stdout, tt.wantOutput)
+			}
+		})
+	}
+}
@@ -1,8 +1,12 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"sync/atomic"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
@@ -13,22 +17,54 @@ import (
 	"github.com/osamingo/go-csvpp/cmd/csvpp/internal/tui"
 )
 
+// headerWaitInterval is how often --follow checks a file for its header line.
+const headerWaitInterval = 500 * time.Millisecond
+
 var viewCmd = &cobra.Command{
 	Use:   "view [file]",
 	Short: "View CSV++ file in a table",
 	Long: `View CSV++ file contents in an interactive table.
 
 Uses a TUI when running in a terminal, falls back to plain text output
-when piped or not in a TTY.`,
+when piped or not in a TTY. The TUI shows the first records while the rest
+of the input is still loading. Records of a file can be edited in the TUI and
+saved back to it after reviewing a diff of the changes.
+
+With --follow, records appended to the file are added to the table as they
+are written, like tail -f.
+
+Key bindings and the color theme of the TUI are read from
+$XDG_CONFIG_HOME/csvpp/config.yaml (~/.config/csvpp/config.yaml by default).
+Setting NO_COLOR selects the no-color theme.`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runView,
 }
 
 func init() {
+	viewCmd.Flags().BoolP("follow", "f", false, "keep reading records appended to the file")
 	rootCmd.AddCommand(viewCmd)
 }
 
 func runView(cmd *cobra.Command, args []string) (retErr error) {
+	follow, err := cmd.Flags().GetBool("follow")
+	if err != nil {
+		return err
+	}
+	interactive := term.IsTerminal(int(os.Stdout.Fd()))
+	switch {
+	case follow && len(args) == 0:
+		return errors.New("--follow requires a file")
+	case follow && !interactive:
+		return errors.New("--follow requires a terminal")
+	}
+
+	var opts []tui.ModelOption
+	if interactive {
+		if opts, err = configOptions(); err != nil {
+			return err
+		}
+	}
+
 	r, err := fileutil.OpenInputFromArgs(args)
 	if err != nil {
 		return err
@@ -39,27 +75,47 @@ func runView(cmd *cobra.Command, args []string) (retErr error) {
 		}
 	}()
 
-	reader := csvpp.NewReader(r)
+	counter := &countingReader{r: r}
+	if follow {
+		// Hold back a partly written last line until the rest is appended,
+		// and wait for the header line if it is still being written.
+		lines := fileutil.NewLineReader(r)
+		if err := lines.WaitLine(cmd.Context(), headerWaitInterval); err != nil {
+			return fmt.Errorf("failed to read headers: %w", err)
+		}
+		counter.r = lines
+	}
+	reader := csvpp.NewReader(counter)
 
 	headers, err := reader.Headers()
 	if err != nil {
 		return fmt.Errorf("failed to read headers: %w", err)
 	}
 
-	records, err := reader.ReadAll()
-	if err != nil {
-		return fmt.Errorf("failed to read records: %w", err)
-	}
-
 	// Check if stdout is a terminal
-	if !term.IsTerminal(int(os.Stdout.Fd())) {
+	if !interactive {
+		records, err := reader.ReadAll()
+		if err != nil {
+			return fmt.Errorf("failed to read records: %w", err)
+		}
 		// Plain text output for pipes
 		fmt.Fprint(cmd.OutOrStdout(), tui.PlainView(headers, records)) //nolint:errcheck // stdout write error is not actionable
 		return nil
 	}
 
-	// Interactive TUI
-	model := tui.NewModel(headers, records)
+	// Interactive TUI: records are loaded in the background while browsing.
+	var progress func() float64
+	if size := inputSize(r); size > 0 {
+		progress = func() float64 { return float64(counter.n.Load()) / float64(size) }
+	}
+	opts = append(opts, tui.WithReader(reader, progress))
+	if len(args) > 0 {
+		opts = append(opts, tui.WithSavePath(args[0]))
+	}
+	if follow {
+		opts = append(opts, tui.WithFollow())
+	}
+	model := tui.NewModel(headers, nil, opts...)
 	p := tea.NewProgram(model, tea.WithAltScreen())
 
 	if _, err := p.Run(); err != nil {
@@ -68,3 +124,56 @@ func runView(cmd *cobra.Command, args []string) (retErr error) {
 
 	return nil
 }
+
+// configOptions returns the key bindings and styles of the configuration
+// file, if there is a home directory to find it in. NO_COLOR
+// (https://no-color.org/) overrides the theme of the file.
+func configOptions() ([]tui.ModelOption, error) {
+	cfg := &tui.Config{}
+	if path, err := tui.ConfigPath(); err == nil {
+		if cfg, err = tui.LoadConfig(path); err != nil {
+			return nil, fmt.Errorf("failed to load config: %w", err)
+		}
+	}
+
+	keys, err := cfg.KeyMap()
+	if err != nil {
+		return nil, err
+	}
+	theme := cfg.Theme
+	if os.Getenv("NO_COLOR") != "" {
+		theme = tui.ThemeNoColor
+	}
+	styles, err := tui.ThemeStyles(theme)
+	if err != nil {
+		return nil, err
+	}
+	return []tui.ModelOption{tui.WithKeyMap(keys), tui.WithStyles(styles)}, nil
+}
+
+// countingReader counts the bytes read from r. The count is read while the
+// records are loaded in the background.
+type countingReader struct {
+	r io.Reader
+	n atomic.Int64
+}
+
+// Read implements io.Reader.
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n.Add(int64(n))
+	return n, err
+}
+
+// inputSize returns the size of r if it is a regular file, or 0.
+func inputSize(r io.Reader) int64 {
+	f, ok := r.(*os.File)
+	if !ok {
+		return 0
+	}
+	info, err := f.Stat()
+	if err != nil || !info.Mode().IsRegular() {
+		return 0
+	}
+	return info.Size()
+}
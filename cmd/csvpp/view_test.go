@@ -25,6 +25,16 @@ func TestViewCommand(t *testing.T) {
 			args:    []string{"view", "nonexistent.csvpp"},
 			wantErr: true,
 		},
+		{
+			name:    "error: follow without a file",
+			args:    []string{"view", "--follow"},
+			wantErr: true,
+		},
+		{
+			name:    "error: follow without a terminal",
+			args:    []string{"view", "-f", "testdata/validate/valid.csvpp"},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -73,9 +73,10 @@ type Field struct {
 
 // Error definitions.
 var (
-	ErrNoHeader       = errors.New("csvpp: header record is required")
-	ErrInvalidHeader  = errors.New("csvpp: invalid column header format")
-	ErrNestingTooDeep = errors.New("csvpp: nesting level exceeds limit")
+	ErrNoHeader        = errors.New("csvpp: header record is required")
+	ErrInvalidHeader   = errors.New("csvpp: invalid column header format")
+	ErrNestingTooDeep  = errors.New("csvpp: nesting level exceeds limit")
+	ErrNoFreeDelimiter = errors.New("csvpp: every delimiter candidate occurs in the data")
 )
 
 // ParseError holds detailed information about an error that occurred during parsing.
@@ -0,0 +1,54 @@
+// Package csvppsql provides a read-only database/sql driver for CSV++ files.
+//
+// Importing the package registers the driver as "csvpp". The data source name is
+// the directory that relative file names in FROM clauses are resolved against:
+//
+//	import _ "github.com/osamingo/go-csvpp/csvppsql"
+//
+//	db, err := sql.Open("csvpp", "./data")
+//	rows, err := db.Query(`SELECT name, geo.lat FROM 'people.csvpp' WHERE age >= ? ORDER BY name`, 30)
+//
+// # Column Mapping
+//
+// Each CSV++ file is a table whose columns are derived from its headers:
+//
+//	name                    simple field       -> column "name"
+//	tags[]                  array field        -> column "tags" (repeated)
+//	geo(lat^lon)            structured field   -> columns "geo.lat", "geo.lon"
+//	address[](street^city)  array-structured   -> columns "address.street", "address.city" (repeated)
+//
+// Anything nested inside an array is a repeated column. Every column scans into
+// string. A repeated column holds its values as CSV++ array text, joined with
+// the delimiter of the innermost array it is in, which cannot occur in the
+// values. Its database type name is "TEXT[d]" for the delimiter d (for example
+// "TEXT[~]"), so the values can be split again:
+//
+//	var tags string
+//	err := rows.Scan(&tags)
+//	values := strings.Split(tags, "~") // tags is "" for an empty array
+//
+// All other columns have the type name "TEXT".
+//
+// # Supported SQL
+//
+// Only SELECT statements are supported:
+//
+//	SELECT * | column [[AS] alias], ...
+//	FROM 'file.csvpp'
+//	[WHERE condition]
+//	[ORDER BY column [ASC|DESC], ...]
+//	[LIMIT n [OFFSET m]]
+//
+// Conditions combine comparisons (=, <>, !=, <, <=, >, >=), LIKE with "%" and "_",
+// IN lists, NOT, AND, OR and parentheses. Operands are columns, 'strings',
+// numbers and "?" placeholders. Column names containing "-" or keywords can be
+// quoted with double quotes or backticks.
+//
+// Comparisons are numeric when both sides are numbers and lexical otherwise;
+// ordering a number against a non-number is false. A comparison on a repeated
+// column matches if any of its values satisfies it. ORDER BY on a repeated column
+// uses its first value.
+//
+// Without ORDER BY, rows are streamed from the file; with ORDER BY, the matching
+// rows are held in memory to be sorted.
+package csvppsql
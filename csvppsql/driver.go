@@ -0,0 +1,338 @@
+package csvppsql
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/osamingo/go-csvpp"
+	"github.com/osamingo/go-csvpp/internal/expr"
+)
+
+// DriverName is the name the driver is registered under with database/sql.
+const DriverName = "csvpp"
+
+// Error definitions.
+var (
+	ErrSyntax        = errors.New("csvppsql: syntax error")
+	ErrUnknownColumn = errors.New("csvppsql: unknown column")
+	ErrNotSupported  = errors.New("csvppsql: operation not supported")
+)
+
+func init() {
+	sql.Register(DriverName, &Driver{})
+}
+
+// Driver is a read-only database/sql driver that queries CSV++ files.
+// The data source name is the directory relative file names in FROM clauses
+// are resolved against; an empty name means the current directory.
+type Driver struct{}
+
+// Open returns a new connection rooted at the directory name.
+func (*Driver) Open(name string) (driver.Conn, error) {
+	return &conn{dir: name}, nil
+}
+
+// conn is a driver connection. It holds no resources; files are opened per query.
+type conn struct {
+	dir string
+}
+
+// Prepare parses query.
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	parsed, err := parse(query)
+	if err != nil {
+		return nil, err
+	}
+	return &stmt{conn: c, stmt: parsed}, nil
+}
+
+// Close closes the connection.
+func (*conn) Close() error { return nil }
+
+// Begin is not supported: CSV++ tables are read-only.
+func (*conn) Begin() (driver.Tx, error) {
+	return nil, fmt.Errorf("%w: transactions", ErrNotSupported)
+}
+
+// stmt is a prepared SELECT statement.
+type stmt struct {
+	conn *conn
+	stmt *selectStmt
+}
+
+// Close closes the statement.
+func (*stmt) Close() error { return nil }
+
+// NumInput returns the number of "?" placeholders.
+func (s *stmt) NumInput() int { return s.stmt.params }
+
+// Exec is not supported: CSV++ tables are read-only.
+func (*stmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, fmt.Errorf("%w: exec", ErrNotSupported)
+}
+
+// Query opens the table file and returns the selected rows.
+// Without ORDER BY, rows are streamed from the file as they are read.
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	strArgs := make([]string, len(args))
+	for i, arg := range args {
+		v, err := argString(arg)
+		if err != nil {
+			return nil, err
+		}
+		strArgs[i] = v
+	}
+
+	name := s.stmt.table
+	if !filepath.IsAbs(name) {
+		name = filepath.Join(s.conn.dir, name)
+	}
+	f, err := os.Open(name) //nolint:gosec // querying arbitrary files is the purpose of the driver
+	if err != nil {
+		return nil, fmt.Errorf("csvppsql: %w", err)
+	}
+
+	r, err := newRows(f, s.stmt, strArgs)
+	if err != nil {
+		f.Close() //nolint:errcheck,gosec // the original error is more relevant
+		return nil, err
+	}
+	return r, nil
+}
+
+// argString converts a driver argument to the string form used in comparisons.
+func argString(v driver.Value) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case time.Time:
+		return v.Format(time.RFC3339Nano), nil
+	default:
+		return "", fmt.Errorf("%w: argument of type %T", ErrNotSupported, v)
+	}
+}
+
+// rows iterates over the result of a query.
+type rows struct {
+	closer  io.Closer
+	names   []string
+	columns []*column
+	next    func() (*csvpp.Record, error) // returns io.EOF after the last row
+	skip    int                           // rows still to skip for OFFSET
+	limit   int                           // rows still to return, or -1 for no limit
+}
+
+// newRows binds stmt against the table read from f and prepares iteration.
+func newRows(f io.ReadCloser, stmt *selectStmt, args []string) (*rows, error) {
+	reader := csvpp.NewReader(f)
+	headers, err := reader.Headers()
+	if err != nil {
+		return nil, fmt.Errorf("csvppsql: failed to read headers: %w", err)
+	}
+	table, err := tableColumns(headers)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &binder{columns: make(map[string]*column, len(table)), args: args}
+	for _, c := range table {
+		b.columns[c.name] = c
+	}
+
+	r := &rows{closer: f, limit: -1}
+	aliases := make(map[string]*column)
+	for _, item := range stmt.items {
+		if item.star {
+			for _, c := range table {
+				r.names = append(r.names, c.name)
+				r.columns = append(r.columns, c)
+			}
+			continue
+		}
+		c, err := b.column(item.column)
+		if err != nil {
+			return nil, err
+		}
+		name := item.column
+		if item.alias != "" {
+			name = item.alias
+			aliases[item.alias] = c
+		}
+		r.names = append(r.names, name)
+		r.columns = append(r.columns, c)
+	}
+
+	var where cond
+	if stmt.where != nil {
+		if where, err = stmt.where.bind(b); err != nil {
+			return nil, err
+		}
+	}
+
+	if stmt.limit != nil {
+		if r.limit, err = count(stmt.limit, b); err != nil {
+			return nil, err
+		}
+	}
+	if stmt.offset != nil {
+		if r.skip, err = count(stmt.offset, b); err != nil {
+			return nil, err
+		}
+	}
+
+	read := func() (*csvpp.Record, error) {
+		for {
+			rec, err := reader.ReadRecord()
+			if err != nil {
+				return nil, err
+			}
+			if where == nil || where.match(rec) {
+				return rec, nil
+			}
+		}
+	}
+
+	if len(stmt.orderBy) == 0 {
+		r.next = read
+		return r, nil
+	}
+
+	keys := make([]*column, len(stmt.orderBy))
+	for i, item := range stmt.orderBy {
+		if c, ok := aliases[item.column]; ok {
+			keys[i] = c
+			continue
+		}
+		if keys[i], err = b.column(item.column); err != nil {
+			return nil, err
+		}
+	}
+
+	var records []*csvpp.Record
+	for {
+		rec, err := read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("csvppsql: failed to read record: %w", err)
+		}
+		records = append(records, rec)
+	}
+	slices.SortStableFunc(records, func(a, b *csvpp.Record) int {
+		for i, key := range keys {
+			c := compareKeys(key.values(a), key.values(b))
+			if stmt.orderBy[i].desc {
+				c = -c
+			}
+			if c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+	r.next = func() (*csvpp.Record, error) {
+		if len(records) == 0 {
+			return nil, io.EOF
+		}
+		rec := records[0]
+		records = records[1:]
+		return rec, nil
+	}
+	return r, nil
+}
+
+// count evaluates a LIMIT or OFFSET operand.
+func count(op operand, b *binder) (int, error) {
+	bound, err := op.bind(b)
+	if err != nil {
+		return 0, err
+	}
+	s := bound.values(nil)[0]
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%w: invalid LIMIT/OFFSET %q", ErrSyntax, s)
+	}
+	return n, nil
+}
+
+// compareKeys orders two rows by the first value of a sort column.
+// Rows without a value sort first.
+func compareKeys(a, b []string) int {
+	switch {
+	case len(a) == 0 && len(b) == 0:
+		return 0
+	case len(a) == 0:
+		return -1
+	case len(b) == 0:
+		return 1
+	}
+	c, _ := expr.Order(a[0], b[0])
+	return c
+}
+
+// Columns returns the result column names.
+func (r *rows) Columns() []string { return r.names }
+
+// Close closes the underlying file.
+func (r *rows) Close() error {
+	return r.closer.Close()
+}
+
+// Next populates dest with the next row. Every column is a string; see
+// column.value for repeated columns.
+func (r *rows) Next(dest []driver.Value) error {
+	for {
+		if r.limit == 0 {
+			return io.EOF
+		}
+		rec, err := r.next()
+		if errors.Is(err, io.EOF) {
+			return io.EOF
+		}
+		if err != nil {
+			return fmt.Errorf("csvppsql: failed to read record: %w", err)
+		}
+		if r.skip > 0 {
+			r.skip--
+			continue
+		}
+		if r.limit > 0 {
+			r.limit--
+		}
+		for i, c := range r.columns {
+			dest[i] = c.value(rec)
+		}
+		return nil
+	}
+}
+
+// ColumnTypeDatabaseTypeName returns "TEXT" for simple columns and "TEXT[d]" for
+// repeated columns whose values are joined with the array delimiter d.
+func (r *rows) ColumnTypeDatabaseTypeName(index int) string {
+	return r.columns[index].typeName()
+}
+
+// ColumnTypeScanType returns string, the type of every column.
+func (r *rows) ColumnTypeScanType(int) reflect.Type {
+	return reflect.TypeFor[string]()
+}
@@ -0,0 +1,317 @@
+package csvppsql_test
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/osamingo/go-csvpp/csvppsql"
+)
+
+// queryAll runs query against testdata and returns every row as a slice of values.
+func queryAll(t *testing.T, query string, args ...any) ([]string, [][]any, error) {
+	t.Helper()
+
+	db, err := sql.Open(csvppsql.DriverName, "testdata")
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		t.Fatalf("Columns() error = %v", err)
+	}
+
+	var got [][]any
+	for rows.Next() {
+		values := make([]any, len(columns))
+		ptrs := make([]any, len(columns))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			t.Fatalf("Scan() error = %v", err)
+		}
+		got = append(got, values)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	return columns, got, nil
+}
+
+func TestQuery(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		query       string
+		args        []any
+		wantColumns []string
+		wantRows    [][]any
+	}{
+		{
+			name:        "success: select star maps all columns",
+			query:       `SELECT * FROM 'people.csvpp' LIMIT 1`,
+			wantColumns: []string{"name", "age", "tags", "geo.lat", "geo.lon", "address.street", "address.city"},
+			wantRows: [][]any{
+				{"Alice", "30", "go~rust", "35.68", "139.76", "1-1 Chiyoda~5th Ave", "Tokyo~New York"},
+			},
+		},
+		{
+			name:        "success: dotted column and numeric where",
+			query:       `SELECT name, geo.lat FROM 'people.csvpp' WHERE age >= 30`,
+			wantColumns: []string{"name", "geo.lat"},
+			wantRows:    [][]any{{"Alice", "35.68"}, {"Carol", "34.69"}, {"Dave", ""}},
+		},
+		{
+			name:        "success: repeated column matches any value",
+			query:       `SELECT name FROM people.csvpp WHERE address.city = 'New York'`,
+			wantColumns: []string{"name"},
+			wantRows:    [][]any{{"Alice"}},
+		},
+		{
+			name:        "success: placeholders",
+			query:       `SELECT name FROM 'people.csvpp' WHERE age > ? AND tags = ?`,
+			args:        []any{30, "go"},
+			wantColumns: []string{"name"},
+			wantRows:    [][]any{{"Dave"}},
+		},
+		{
+			name:        "success: like",
+			query:       `SELECT name FROM 'people.csvpp' WHERE name LIKE '_a%'`,
+			wantColumns: []string{"name"},
+			wantRows:    [][]any{{"Carol"}, {"Dave"}},
+		},
+		{
+			name:        "success: not like",
+			query:       `SELECT name FROM 'people.csvpp' WHERE name NOT LIKE '%o%'`,
+			wantColumns: []string{"name"},
+			wantRows:    [][]any{{"Alice"}, {"Dave"}},
+		},
+		{
+			name:        "success: in and not in",
+			query:       `SELECT name FROM 'people.csvpp' WHERE tags IN ('go', 'python') AND name NOT IN ('Dave')`,
+			wantColumns: []string{"name"},
+			wantRows:    [][]any{{"Alice"}, {"Bob"}},
+		},
+		{
+			name:        "success: or, not and parentheses",
+			query:       `SELECT name FROM 'people.csvpp' WHERE NOT (age < 30 OR geo.lon > 136)`,
+			wantColumns: []string{"name"},
+			wantRows:    [][]any{{"Carol"}, {"Dave"}},
+		},
+		{
+			name:        "success: empty fields do not order against numbers",
+			query:       `SELECT name FROM 'people.csvpp' WHERE geo.lon < 0`,
+			wantColumns: []string{"name"},
+			wantRows:    [][]any{{"Bob"}},
+		},
+		{
+			name:        "success: order by numeric desc with alias",
+			query:       `SELECT name AS who, age FROM 'people.csvpp' ORDER BY age DESC`,
+			wantColumns: []string{"who", "age"},
+			wantRows:    [][]any{{"Carol", "41"}, {"Dave", "35"}, {"Alice", "30"}, {"Bob", "25"}},
+		},
+		{
+			name:        "success: order by repeated column and limit offset",
+			query:       `SELECT name, tags FROM 'people.csvpp' ORDER BY tags, name LIMIT 2 OFFSET 1;`,
+			wantColumns: []string{"name", "tags"},
+			wantRows:    [][]any{{"Alice", "go~rust"}, {"Dave", "go"}},
+		},
+		{
+			name:        "success: limit placeholder without order",
+			query:       `select name from 'people.csvpp' limit ? offset ?`,
+			args:        []any{1, 2},
+			wantColumns: []string{"name"},
+			wantRows:    [][]any{{"Carol"}},
+		},
+		{
+			name:        "success: quoted column",
+			query:       `SELECT "geo.lon" FROM 'people.csvpp' WHERE name = 'Bob'`,
+			wantColumns: []string{"geo.lon"},
+			wantRows:    [][]any{{"-118.24"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			columns, rows, err := queryAll(t, tt.query, tt.args...)
+			if err != nil {
+				t.Fatalf("Query() unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.wantColumns, columns); diff != "" {
+				t.Errorf("columns mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantRows, rows); diff != "" {
+				t.Errorf("rows mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestQuery_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		query   string
+		args    []any
+		wantErr error
+	}{
+		{name: "error: not a select", query: `DELETE FROM 'people.csvpp'`, wantErr: csvppsql.ErrSyntax},
+		{name: "error: missing from", query: `SELECT name`, wantErr: csvppsql.ErrSyntax},
+		{name: "error: unterminated string", query: `SELECT name FROM 'people.csvpp`, wantErr: csvppsql.ErrSyntax},
+		{name: "error: trailing tokens", query: `SELECT name FROM 'people.csvpp' name`, wantErr: csvppsql.ErrSyntax},
+		{name: "error: missing operator", query: `SELECT name FROM 'people.csvpp' WHERE age`, wantErr: csvppsql.ErrSyntax},
+		{name: "error: negative limit", query: `SELECT name FROM 'people.csvpp' LIMIT ?`, args: []any{-1}, wantErr: csvppsql.ErrSyntax},
+		{name: "error: unknown column", query: `SELECT email FROM 'people.csvpp'`, wantErr: csvppsql.ErrUnknownColumn},
+		{name: "error: unknown column in where", query: `SELECT name FROM 'people.csvpp' WHERE geo.alt > 1`, wantErr: csvppsql.ErrUnknownColumn},
+		{name: "error: structured field is not a column", query: `SELECT geo FROM 'people.csvpp'`, wantErr: csvppsql.ErrUnknownColumn},
+		{name: "error: unknown order column", query: `SELECT name FROM 'people.csvpp' ORDER BY email`, wantErr: csvppsql.ErrUnknownColumn},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, _, err := queryAll(t, tt.query, tt.args...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Query() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestQuery_FileNotFound(t *testing.T) {
+	t.Parallel()
+
+	if _, _, err := queryAll(t, `SELECT * FROM 'missing.csvpp'`); err == nil {
+		t.Error("Query() expected error for missing file")
+	}
+}
+
+func TestExec_NotSupported(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open(csvppsql.DriverName, "testdata")
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(`SELECT * FROM 'people.csvpp'`); !errors.Is(err, csvppsql.ErrNotSupported) {
+		t.Errorf("Exec() error = %v, want %v", err, csvppsql.ErrNotSupported)
+	}
+	if _, err := db.Begin(); !errors.Is(err, csvppsql.ErrNotSupported) {
+		t.Errorf("Begin() error = %v, want %v", err, csvppsql.ErrNotSupported)
+	}
+}
+
+func TestStmt_Reuse(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open(csvppsql.DriverName, "testdata")
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	defer db.Close()
+
+	stmt, err := db.Prepare(`SELECT age FROM 'people.csvpp' WHERE name = ?`)
+	if err != nil {
+		t.Fatalf("Prepare() error = %v", err)
+	}
+	defer stmt.Close()
+
+	for name, want := range map[string]string{"Alice": "30", "Bob": "25"} {
+		var got string
+		if err := stmt.QueryRow(name).Scan(&got); err != nil {
+			t.Fatalf("QueryRow(%q) error = %v", name, err)
+		}
+		if got != want {
+			t.Errorf("QueryRow(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestRows_ColumnTypes(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open(csvppsql.DriverName, "testdata")
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`SELECT name, tags FROM 'people.csvpp'`)
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	defer rows.Close()
+
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		t.Fatalf("ColumnTypes() error = %v", err)
+	}
+	got := []string{types[0].DatabaseTypeName(), types[1].DatabaseTypeName()}
+	if diff := cmp.Diff([]string{"TEXT", "TEXT[~]"}, got); diff != "" {
+		t.Errorf("DatabaseTypeName mismatch (-want +got):\n%s", diff)
+	}
+
+	rows.Next()
+	var name, tags string
+	if err := rows.Scan(&name, &tags); err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if tags != "go~rust" {
+		t.Errorf("tags = %q, want %q", tags, "go~rust")
+	}
+}
+
+func TestRows_RepeatedDelimiters(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open(csvppsql.DriverName, "testdata")
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`SELECT tags, address.city, address.codes FROM 'delimiters.csvpp'`)
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	defer rows.Close()
+
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		t.Fatalf("ColumnTypes() error = %v", err)
+	}
+	var gotTypes []string
+	for _, ct := range types {
+		gotTypes = append(gotTypes, ct.DatabaseTypeName())
+	}
+	if diff := cmp.Diff([]string{"TEXT[;]", "TEXT[~]", "TEXT[|]"}, gotTypes); diff != "" {
+		t.Errorf("DatabaseTypeName mismatch (-want +got):\n%s", diff)
+	}
+
+	rows.Next()
+	got := make([]string, 3)
+	if err := rows.Scan(&got[0], &got[1], &got[2]); err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if diff := cmp.Diff([]string{"a~b;c", "Tokyo~Osaka", "100|101|530"}, got); diff != "" {
+		t.Errorf("values mismatch (-want +got):\n%s", diff)
+	}
+}
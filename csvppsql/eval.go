@@ -0,0 +1,214 @@
+package csvppsql
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/osamingo/go-csvpp"
+	"github.com/osamingo/go-csvpp/internal/expr"
+)
+
+// binder resolves column references and placeholders once the table is known.
+type binder struct {
+	columns map[string]*column
+	args    []string
+}
+
+// column returns the column named name.
+func (b *binder) column(name string) (*column, error) {
+	c, ok := b.columns[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownColumn, name)
+	}
+	return c, nil
+}
+
+// operand is a value-producing expression: a column, a literal or a placeholder.
+type operand interface {
+	bind(b *binder) (operand, error)
+	values(rec *csvpp.Record) []string
+}
+
+// cond is a boolean expression in a WHERE clause.
+// bind returns a copy with columns and placeholders resolved, leaving the
+// prepared statement reusable.
+type cond interface {
+	bind(b *binder) (cond, error)
+	match(rec *csvpp.Record) bool
+}
+
+type (
+	columnRef struct {
+		name string
+		col  *column
+	}
+	literal     struct{ value string }
+	placeholder struct{ index int }
+)
+
+func (r *columnRef) bind(b *binder) (operand, error) {
+	c, err := b.column(r.name)
+	if err != nil {
+		return nil, err
+	}
+	return &columnRef{name: r.name, col: c}, nil
+}
+
+func (r *columnRef) values(rec *csvpp.Record) []string { return r.col.values(rec) }
+
+func (l *literal) bind(*binder) (operand, error)     { return l, nil }
+func (l *literal) values(*csvpp.Record) []string     { return []string{l.value} }
+func (p *placeholder) values(*csvpp.Record) []string { return nil }
+
+func (p *placeholder) bind(b *binder) (operand, error) {
+	return &literal{value: b.args[p.index]}, nil
+}
+
+type (
+	andCond struct{ left, right cond }
+	orCond  struct{ left, right cond }
+	notCond struct{ cond cond }
+
+	// compareCond compares two operands with op, an expr.Compare operator.
+	compareCond struct {
+		op          string
+		left, right operand
+	}
+
+	// likeCond matches an operand against a LIKE pattern.
+	likeCond struct {
+		value   operand
+		pattern operand
+		re      *regexp.Regexp
+	}
+
+	// inCond tests whether an operand equals any operand in a list.
+	inCond struct {
+		value operand
+		list  []operand
+	}
+)
+
+func (c *andCond) bind(b *binder) (cond, error) {
+	left, err := c.left.bind(b)
+	if err != nil {
+		return nil, err
+	}
+	right, err := c.right.bind(b)
+	if err != nil {
+		return nil, err
+	}
+	return &andCond{left: left, right: right}, nil
+}
+
+func (c *orCond) bind(b *binder) (cond, error) {
+	left, err := c.left.bind(b)
+	if err != nil {
+		return nil, err
+	}
+	right, err := c.right.bind(b)
+	if err != nil {
+		return nil, err
+	}
+	return &orCond{left: left, right: right}, nil
+}
+
+func (c *notCond) bind(b *binder) (cond, error) {
+	inner, err := c.cond.bind(b)
+	if err != nil {
+		return nil, err
+	}
+	return &notCond{cond: inner}, nil
+}
+
+func (c *compareCond) bind(b *binder) (cond, error) {
+	left, err := c.left.bind(b)
+	if err != nil {
+		return nil, err
+	}
+	right, err := c.right.bind(b)
+	if err != nil {
+		return nil, err
+	}
+	return &compareCond{op: c.op, left: left, right: right}, nil
+}
+
+func (c *likeCond) bind(b *binder) (cond, error) {
+	value, err := c.value.bind(b)
+	if err != nil {
+		return nil, err
+	}
+	pattern, err := c.pattern.bind(b)
+	if err != nil {
+		return nil, err
+	}
+	return &likeCond{value: value, pattern: pattern, re: likePattern(pattern.values(nil)[0])}, nil
+}
+
+func (c *inCond) bind(b *binder) (cond, error) {
+	value, err := c.value.bind(b)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]operand, len(c.list))
+	for i, item := range c.list {
+		if list[i], err = item.bind(b); err != nil {
+			return nil, err
+		}
+	}
+	return &inCond{value: value, list: list}, nil
+}
+
+func (c *andCond) match(rec *csvpp.Record) bool { return c.left.match(rec) && c.right.match(rec) }
+func (c *orCond) match(rec *csvpp.Record) bool  { return c.left.match(rec) || c.right.match(rec) }
+func (c *notCond) match(rec *csvpp.Record) bool { return !c.cond.match(rec) }
+
+func (c *compareCond) match(rec *csvpp.Record) bool {
+	right := c.right.values(rec)
+	for _, l := range c.left.values(rec) {
+		for _, r := range right {
+			if expr.Compare(c.op, l, r) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (c *likeCond) match(rec *csvpp.Record) bool {
+	return slices.ContainsFunc(c.value.values(rec), c.re.MatchString)
+}
+
+func (c *inCond) match(rec *csvpp.Record) bool {
+	values := c.value.values(rec)
+	for _, item := range c.list {
+		for _, want := range item.values(rec) {
+			for _, v := range values {
+				if expr.Compare("==", v, want) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// likePattern converts a LIKE pattern ("%" any run, "_" any character) to a regular expression.
+func likePattern(pattern string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString(`(?s)^`)
+	for _, r := range pattern {
+		switch r {
+		case '%':
+			b.WriteString(".*")
+		case '_':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
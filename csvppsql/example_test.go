@@ -0,0 +1,39 @@
+package csvppsql_test
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	_ "github.com/osamingo/go-csvpp/csvppsql"
+)
+
+func Example() {
+	db, err := sql.Open("csvpp", "testdata")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`SELECT name, geo.lat, address.city FROM 'people.csvpp' WHERE age >= ? ORDER BY name LIMIT 2`, 30)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name, lat, cities string
+		if err := rows.Scan(&name, &lat, &cities); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(name, lat, strings.Split(cities, "~"))
+	}
+	if err := rows.Err(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Output:
+	// Alice 35.68 [Tokyo New York]
+	// Carol 34.69 [Osaka]
+}
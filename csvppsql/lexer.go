@@ -0,0 +1,47 @@
+package csvppsql
+
+import "github.com/osamingo/go-csvpp/internal/expr"
+
+type (
+	tokenKind = expr.Kind
+	token     = expr.Token
+)
+
+const (
+	tokEOF    = expr.EOF
+	tokIdent  = expr.Ident  // column name, keyword, or "quoted"/`quoted` identifier
+	tokString = expr.String // 'string'
+	tokNumber = expr.Number // 42, -1.5
+)
+
+const (
+	tokOp        = expr.Symbol + iota // = <> != < <= > >=
+	tokComma                          // ,
+	tokLParen                         // (
+	tokRParen                         // )
+	tokStar                           // *
+	tokParam                          // ?
+	tokSemicolon                      // ;
+)
+
+// syntax is the token syntax of SQL statements. Quotes are escaped by
+// doubling them.
+var syntax = &expr.Syntax{
+	Symbols: []expr.SymbolDef{
+		{Text: "<>", Kind: tokOp},
+		{Text: "!=", Kind: tokOp},
+		{Text: "<=", Kind: tokOp},
+		{Text: ">=", Kind: tokOp},
+		{Text: "=", Kind: tokOp},
+		{Text: "<", Kind: tokOp},
+		{Text: ">", Kind: tokOp},
+		{Text: ",", Kind: tokComma},
+		{Text: "(", Kind: tokLParen},
+		{Text: ")", Kind: tokRParen},
+		{Text: "*", Kind: tokStar},
+		{Text: "?", Kind: tokParam},
+		{Text: ";", Kind: tokSemicolon},
+	},
+	Quotes:        map[rune]expr.Kind{'\'': tokString, '"': tokIdent, '`': tokIdent},
+	DoubledQuotes: true,
+}
@@ -0,0 +1,422 @@
+package csvppsql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// selectStmt is a parsed SELECT statement.
+type selectStmt struct {
+	items   []selectItem
+	table   string
+	where   cond // nil if there is no WHERE clause
+	orderBy []orderItem
+	limit   operand // nil if there is no LIMIT clause
+	offset  operand // nil if there is no OFFSET clause
+	params  int     // number of "?" placeholders
+}
+
+// selectItem is one entry of the select list.
+type selectItem struct {
+	star   bool   // "*": every table column
+	column string // column name
+	alias  string // AS alias, or empty
+}
+
+// orderItem is one entry of the ORDER BY list.
+type orderItem struct {
+	column string
+	desc   bool
+}
+
+// parser is a recursive descent parser over a token slice.
+//
+// Grammar:
+//
+//	stmt     = "SELECT" items "FROM" table [ "WHERE" or ] [ "ORDER" "BY" order { "," order } ]
+//	           [ "LIMIT" count [ "OFFSET" count ] ] [ ";" ]
+//	items    = item { "," item }
+//	item     = "*" | column [ [ "AS" ] name ]
+//	table    = string | identifier
+//	or       = and { "OR" and }
+//	and      = not { "AND" not }
+//	not      = "NOT" not | pred
+//	pred     = "(" or ")" | operand ( op operand | [ "NOT" ] "LIKE" pattern
+//	         | [ "NOT" ] "IN" "(" operand { "," operand } ")" )
+//	op       = "=" | "<>" | "!=" | "<" | "<=" | ">" | ">="
+//	operand  = column | string | number | "?"
+//	pattern  = string | "?"
+//	order    = column [ "ASC" | "DESC" ]
+//	count    = number | "?"
+type parser struct {
+	tokens []token
+	pos    int
+	params int
+}
+
+// parse parses a single SELECT statement.
+func parse(src string) (*selectStmt, error) {
+	tokens, err := syntax.Lex(src)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSyntax, err)
+	}
+	p := &parser{tokens: tokens}
+	stmt, err := p.parseSelect()
+	if err != nil {
+		return nil, err
+	}
+	stmt.params = p.params
+	return stmt, nil
+}
+
+// peek returns the current token without consuming it.
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+// next consumes and returns the current token.
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.Kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+// isKeyword reports whether t is the (case-insensitive) keyword kw.
+func isKeyword(t token, kw string) bool {
+	return t.Kind == tokIdent && !t.Quoted && strings.EqualFold(t.Text, kw)
+}
+
+// acceptKeyword consumes the current token if it is the keyword kw.
+func (p *parser) acceptKeyword(kw string) bool {
+	if isKeyword(p.peek(), kw) {
+		p.next()
+		return true
+	}
+	return false
+}
+
+// expectKeyword consumes the keyword kw or returns an error.
+func (p *parser) expectKeyword(kw string) error {
+	if t := p.next(); !isKeyword(t, kw) {
+		return p.errorf(t, "expected %s, got %s", kw, describe(t))
+	}
+	return nil
+}
+
+// expect consumes a token of the given kind or returns an error.
+func (p *parser) expect(kind tokenKind, what string) (token, error) {
+	t := p.next()
+	if t.Kind != kind {
+		return t, p.errorf(t, "expected %s, got %s", what, describe(t))
+	}
+	return t, nil
+}
+
+// errorf returns a syntax error positioned at t.
+func (p *parser) errorf(t token, format string, args ...any) error {
+	return fmt.Errorf("%w at position %d: %s", ErrSyntax, t.Pos, fmt.Sprintf(format, args...))
+}
+
+// describe returns a human-readable description of t for error messages.
+func describe(t token) string {
+	if t.Kind == tokEOF {
+		return "end of statement"
+	}
+	return fmt.Sprintf("%q", t.Text)
+}
+
+// reserved lists keywords that cannot be used as unquoted column names or aliases.
+var reserved = []string{
+	"SELECT", "FROM", "WHERE", "AND", "OR", "NOT", "LIKE", "IN",
+	"ORDER", "BY", "ASC", "DESC", "LIMIT", "OFFSET", "AS",
+}
+
+// expectName consumes a column name or alias.
+func (p *parser) expectName(what string) (string, error) {
+	t, err := p.expect(tokIdent, what)
+	if err != nil {
+		return "", err
+	}
+	for _, kw := range reserved {
+		if isKeyword(t, kw) {
+			return "", p.errorf(t, "expected %s, got keyword %s", what, kw)
+		}
+	}
+	return t.Text, nil
+}
+
+func (p *parser) parseSelect() (*selectStmt, error) {
+	if err := p.expectKeyword("SELECT"); err != nil {
+		return nil, err
+	}
+
+	stmt := &selectStmt{}
+	for {
+		item, err := p.parseItem()
+		if err != nil {
+			return nil, err
+		}
+		stmt.items = append(stmt.items, item)
+		if p.peek().Kind != tokComma {
+			break
+		}
+		p.next()
+	}
+
+	if err := p.expectKeyword("FROM"); err != nil {
+		return nil, err
+	}
+	t := p.next()
+	if t.Kind != tokString && t.Kind != tokIdent {
+		return nil, p.errorf(t, "expected file name, got %s", describe(t))
+	}
+	stmt.table = t.Text
+
+	if p.acceptKeyword("WHERE") {
+		where, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		stmt.where = where
+	}
+
+	if p.acceptKeyword("ORDER") {
+		if err := p.expectKeyword("BY"); err != nil {
+			return nil, err
+		}
+		for {
+			column, err := p.expectName("column name")
+			if err != nil {
+				return nil, err
+			}
+			item := orderItem{column: column}
+			if p.acceptKeyword("DESC") {
+				item.desc = true
+			} else {
+				p.acceptKeyword("ASC")
+			}
+			stmt.orderBy = append(stmt.orderBy, item)
+			if p.peek().Kind != tokComma {
+				break
+			}
+			p.next()
+		}
+	}
+
+	if p.acceptKeyword("LIMIT") {
+		limit, err := p.parseCount()
+		if err != nil {
+			return nil, err
+		}
+		stmt.limit = limit
+		if p.acceptKeyword("OFFSET") {
+			offset, err := p.parseCount()
+			if err != nil {
+				return nil, err
+			}
+			stmt.offset = offset
+		}
+	}
+
+	if p.peek().Kind == tokSemicolon {
+		p.next()
+	}
+	if t := p.peek(); t.Kind != tokEOF {
+		return nil, p.errorf(t, "unexpected %s", describe(t))
+	}
+
+	return stmt, nil
+}
+
+func (p *parser) parseItem() (selectItem, error) {
+	if p.peek().Kind == tokStar {
+		p.next()
+		return selectItem{star: true}, nil
+	}
+
+	column, err := p.expectName("column name")
+	if err != nil {
+		return selectItem{}, err
+	}
+	item := selectItem{column: column}
+
+	if p.acceptKeyword("AS") {
+		alias, err := p.expectName("alias")
+		if err != nil {
+			return selectItem{}, err
+		}
+		item.alias = alias
+	} else if t := p.peek(); t.Kind == tokIdent && !isKeyword(t, "FROM") {
+		alias, err := p.expectName("alias")
+		if err != nil {
+			return selectItem{}, err
+		}
+		item.alias = alias
+	}
+
+	return item, nil
+}
+
+func (p *parser) parseCount() (operand, error) {
+	t := p.next()
+	switch t.Kind {
+	case tokNumber:
+		return &literal{value: t.Text}, nil
+	case tokParam:
+		return p.param(), nil
+	default:
+		return nil, p.errorf(t, "expected number, got %s", describe(t))
+	}
+}
+
+// param returns a placeholder for the next positional argument.
+func (p *parser) param() *placeholder {
+	p.params++
+	return &placeholder{index: p.params - 1}
+}
+
+func (p *parser) parseOr() (cond, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptKeyword("OR") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &orCond{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (cond, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptKeyword("AND") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &andCond{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (cond, error) {
+	if p.acceptKeyword("NOT") {
+		c, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &notCond{cond: c}, nil
+	}
+	return p.parsePred()
+}
+
+func (p *parser) parsePred() (cond, error) {
+	if p.peek().Kind == tokLParen {
+		p.next()
+		c, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokRParen, `")"`); err != nil {
+			return nil, err
+		}
+		return c, nil
+	}
+
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	negate := p.acceptKeyword("NOT")
+	t := p.next()
+	switch {
+	case isKeyword(t, "LIKE"):
+		pt := p.next()
+		var pattern operand
+		switch pt.Kind {
+		case tokString:
+			pattern = &literal{value: pt.Text}
+		case tokParam:
+			pattern = p.param()
+		default:
+			return nil, p.errorf(pt, "expected pattern string, got %s", describe(pt))
+		}
+		var c cond = &likeCond{value: left, pattern: pattern}
+		if negate {
+			c = &notCond{cond: c}
+		}
+		return c, nil
+
+	case isKeyword(t, "IN"):
+		if _, err := p.expect(tokLParen, `"("`); err != nil {
+			return nil, err
+		}
+		var list []operand
+		for {
+			item, err := p.parseOperand()
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, item)
+			sep := p.next()
+			if sep.Kind == tokRParen {
+				break
+			}
+			if sep.Kind != tokComma {
+				return nil, p.errorf(sep, `expected "," or ")", got %s`, describe(sep))
+			}
+		}
+		var c cond = &inCond{value: left, list: list}
+		if negate {
+			c = &notCond{cond: c}
+		}
+		return c, nil
+
+	case t.Kind == tokOp && !negate:
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		op := t.Text
+		switch op {
+		case "=":
+			op = "=="
+		case "<>":
+			op = "!="
+		}
+		return &compareCond{op: op, left: left, right: right}, nil
+
+	default:
+		return nil, p.errorf(t, "expected comparison, LIKE or IN, got %s", describe(t))
+	}
+}
+
+func (p *parser) parseOperand() (operand, error) {
+	t := p.peek()
+	switch t.Kind {
+	case tokString, tokNumber:
+		p.next()
+		return &literal{value: t.Text}, nil
+	case tokParam:
+		p.next()
+		return p.param(), nil
+	case tokIdent:
+		name, err := p.expectName("column name")
+		if err != nil {
+			return nil, err
+		}
+		return &columnRef{name: name}, nil
+	default:
+		p.next()
+		return nil, p.errorf(t, "expected column or value, got %s", describe(t))
+	}
+}
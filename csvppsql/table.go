@@ -0,0 +1,99 @@
+package csvppsql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/osamingo/go-csvpp"
+)
+
+// column is a table column derived from CSV++ headers.
+type column struct {
+	name     string      // dotted column name, e.g. "geo.lat"
+	path     *csvpp.Path // path resolving the column's values in a record
+	repeated bool        // column holds zero or more values
+	delim    rune        // array delimiter joining the values of a repeated column
+}
+
+// tableColumns maps CSV++ headers to table columns:
+//
+//   - simple fields become columns of the same name
+//   - structured fields become one dotted column per component ("geo.lat")
+//   - array fields become repeated columns ("tags")
+//   - array-structured fields become repeated dotted columns ("address.city")
+//
+// Anything nested inside an array is repeated as well.
+func tableColumns(headers []*csvpp.ColumnHeader) ([]*column, error) {
+	var columns []*column
+	if err := appendColumns(&columns, headers, "", "", 0); err != nil {
+		return nil, err
+	}
+	return columns, nil
+}
+
+// appendColumns appends the columns for headers, prefixing column names with name
+// and record paths with path. delim is the delimiter of the innermost enclosing
+// array, or 0 if the headers are not inside an array.
+func appendColumns(columns *[]*column, headers []*csvpp.ColumnHeader, name, path string, delim rune) error {
+	for _, h := range headers {
+		var err error
+		switch h.Kind {
+		case csvpp.SimpleField:
+			err = appendColumn(columns, name+h.Name, path+h.Name, delim)
+		case csvpp.ArrayField:
+			err = appendColumn(columns, name+h.Name, path+h.Name+"[]", h.ArrayDelimiter)
+		case csvpp.StructuredField:
+			err = appendColumns(columns, h.Components, name+h.Name+".", path+h.Name+".", delim)
+		case csvpp.ArrayStructuredField:
+			err = appendColumns(columns, h.Components, name+h.Name+".", path+h.Name+"[].", h.ArrayDelimiter)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendColumn appends a single column, repeated if delim is not 0.
+func appendColumn(columns *[]*column, name, path string, delim rune) error {
+	p, err := csvpp.ParsePath(path)
+	if err != nil {
+		return fmt.Errorf("column %s: %w", name, err)
+	}
+	*columns = append(*columns, &column{name: name, path: p, repeated: delim != 0, delim: delim})
+	return nil
+}
+
+// values returns the values of c in rec.
+// A simple column always has exactly one value.
+func (c *column) values(rec *csvpp.Record) []string {
+	values, err := rec.GetAllPath(c.path)
+	if err != nil || (!c.repeated && len(values) == 0) {
+		// Short rows and out-of-range components read as empty.
+		if c.repeated {
+			return nil
+		}
+		return []string{""}
+	}
+	return values
+}
+
+// value returns the driver value of c in rec. The values of a repeated column
+// are joined with its array delimiter, which cannot occur in them, as in
+// CSV++ text.
+func (c *column) value(rec *csvpp.Record) any {
+	values := c.values(rec)
+	if c.repeated {
+		return strings.Join(values, string(c.delim))
+	}
+	return values[0]
+}
+
+// typeName returns the database type name of c: "TEXT", or "TEXT[d]" with
+// the array delimiter d if c is repeated.
+func (c *column) typeName() string {
+	if c.repeated {
+		return "TEXT[" + string(c.delim) + "]"
+	}
+	return "TEXT"
+}
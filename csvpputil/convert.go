@@ -56,7 +56,8 @@ func arrayStructuredToSlice(headers []*csvpp.ColumnHeader, components []*csvpp.F
 }
 
 // fieldsToMapSlice converts fields to yaml.MapSlice preserving headers order.
-func fieldsToMapSlice(headers []*csvpp.ColumnHeader, fields []*csvpp.Field) yaml.MapSlice {
+// Values are typed by vt, with field paths relative to prefix.
+func fieldsToMapSlice(headers []*csvpp.ColumnHeader, fields []*csvpp.Field, vt *ValueTypes, prefix string) yaml.MapSlice {
 	if len(headers) == 0 || len(fields) == 0 {
 		return nil
 	}
@@ -66,7 +67,7 @@ func fieldsToMapSlice(headers []*csvpp.ColumnHeader, fields []*csvpp.Field) yaml
 	for i := range n {
 		result[i] = yaml.MapItem{
 			Key:   headers[i].Name,
-			Value: fieldToValueYAML(headers[i], fields[i]),
+			Value: fieldToValueYAML(headers[i], fields[i], vt, joinPath(prefix, headers[i].Name)),
 		}
 	}
 	return result
@@ -74,27 +75,49 @@ func fieldsToMapSlice(headers []*csvpp.ColumnHeader, fields []*csvpp.Field) yaml
 
 // fieldToValueYAML converts a single Field to its appropriate Go value for YAML.
 // Uses yaml.MapSlice for structured fields to preserve key order.
-func fieldToValueYAML(header *csvpp.ColumnHeader, field *csvpp.Field) any {
+func fieldToValueYAML(header *csvpp.ColumnHeader, field *csvpp.Field, vt *ValueTypes, path string) any {
 	if header == nil || field == nil {
 		return nil
 	}
 
 	switch header.Kind {
 	case csvpp.SimpleField:
-		return field.Value
+		return scalarToValueYAML(field.Value, vt, path)
 	case csvpp.ArrayField:
-		return field.Values
+		if vt == nil {
+			return field.Values
+		}
+		values := make([]any, len(field.Values))
+		for i, v := range field.Values {
+			values[i] = scalarToValueYAML(v, vt, path)
+		}
+		return values
 	case csvpp.StructuredField:
-		return fieldsToMapSlice(header.Components, field.Components)
+		return fieldsToMapSlice(header.Components, field.Components, vt, path)
 	case csvpp.ArrayStructuredField:
-		return arrayStructuredToSliceYAML(header.Components, field.Components)
+		return arrayStructuredToSliceYAML(header.Components, field.Components, vt, path)
 	default:
-		return field.Value
+		return scalarToValueYAML(field.Value, vt, path)
+	}
+}
+
+// scalarToValueYAML converts a simple value to the YAML type vt selects for path.
+func scalarToValueYAML(s string, vt *ValueTypes, path string) any {
+	switch vt.kind(path, s, isYAMLNumber) {
+	case kindNull:
+		return nil
+	case kindNumber:
+		n, _ := yamlNumber(s)
+		return n
+	case kindBoolean:
+		return s == "true"
+	default:
+		return s
 	}
 }
 
 // arrayStructuredToSliceYAML converts array-structured field to a slice of yaml.MapSlice.
-func arrayStructuredToSliceYAML(headers []*csvpp.ColumnHeader, components []*csvpp.Field) []yaml.MapSlice {
+func arrayStructuredToSliceYAML(headers []*csvpp.ColumnHeader, components []*csvpp.Field, vt *ValueTypes, path string) []yaml.MapSlice {
 	if len(components) == 0 {
 		return nil
 	}
@@ -102,7 +125,7 @@ func arrayStructuredToSliceYAML(headers []*csvpp.ColumnHeader, components []*csv
 	result := make([]yaml.MapSlice, 0, len(components))
 	for _, comp := range components {
 		if comp != nil {
-			result = append(result, fieldsToMapSlice(headers, comp.Components))
+			result = append(result, fieldsToMapSlice(headers, comp.Components, vt, path))
 		}
 	}
 	return result
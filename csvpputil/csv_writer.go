@@ -0,0 +1,285 @@
+package csvpputil
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/osamingo/go-csvpp"
+)
+
+// CSVArrayMode controls how CSVWriter flattens array fields.
+type CSVArrayMode int
+
+const (
+	// CSVArrayJoin writes each array as a single column whose value is the
+	// elements joined with the array delimiter ("tags[]" = "go~rust").
+	// Array-structured fields become one column per component whose values are
+	// joined element-wise ("address[].city" = "Tokyo~Osaka").
+	CSVArrayJoin CSVArrayMode = iota
+
+	// CSVArrayIndex writes one column per array element ("tags[0]", "tags[1]",
+	// "address[0].city"). The number of columns is the longest array in the data,
+	// so records are buffered until Close.
+	CSVArrayIndex
+)
+
+// CSVWriterOption is a functional option for CSVWriter.
+type CSVWriterOption func(*CSVWriter)
+
+// WithCSVArrayMode sets how array fields are flattened. The default is CSVArrayJoin.
+func WithCSVArrayMode(mode CSVArrayMode) CSVWriterOption {
+	return func(w *CSVWriter) {
+		w.mode = mode
+	}
+}
+
+// WithCSVComma sets the field delimiter of the plain CSV output. The default is ','.
+func WithCSVComma(comma rune) CSVWriterOption {
+	return func(w *CSVWriter) {
+		w.csv.Comma = comma
+	}
+}
+
+// CSVWriter writes CSV++ records as plain CSV, flattening structured fields into
+// dotted columns ("geo.lat", "geo.lon") and arrays according to CSVArrayMode.
+type CSVWriter struct {
+	csv     *csv.Writer
+	headers []*csvpp.ColumnHeader
+	mode    CSVArrayMode
+	started bool
+	closed  bool
+
+	// CSVArrayIndex state
+	records [][]*csvpp.Field
+	shapes  []*shape
+}
+
+// NewCSVWriter creates a new CSVWriter that writes to w.
+func NewCSVWriter(w io.Writer, headers []*csvpp.ColumnHeader, opts ...CSVWriterOption) *CSVWriter {
+	writer := &CSVWriter{
+		csv:     csv.NewWriter(w),
+		headers: headers,
+	}
+	for _, opt := range opts {
+		opt(writer)
+	}
+	return writer
+}
+
+// Write writes a single record.
+func (w *CSVWriter) Write(record []*csvpp.Field) error {
+	if w.closed {
+		return io.ErrClosedPipe
+	}
+
+	if w.mode == CSVArrayIndex {
+		if w.shapes == nil {
+			w.shapes = newShapes(w.headers)
+		}
+		for i, h := range w.headers {
+			w.shapes[i].update(h, fieldAt(record, i))
+		}
+		w.records = append(w.records, record)
+		return nil
+	}
+
+	if !w.started {
+		if err := w.csv.Write(joinedColumns(w.headers, "")); err != nil {
+			return err
+		}
+		w.started = true
+	}
+	return w.csv.Write(joinedValues(w.headers, record))
+}
+
+// Close writes any buffered records and flushes the output.
+func (w *CSVWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	if w.mode == CSVArrayIndex {
+		if w.shapes == nil {
+			w.shapes = newShapes(w.headers)
+		}
+		var columns []string
+		for i, h := range w.headers {
+			columns = w.shapes[i].columns(h, "", columns)
+		}
+		if err := w.csv.Write(columns); err != nil {
+			return err
+		}
+		for _, record := range w.records {
+			var row []string
+			for i, h := range w.headers {
+				row = w.shapes[i].values(h, fieldAt(record, i), row)
+			}
+			if err := w.csv.Write(row); err != nil {
+				return err
+			}
+		}
+		w.records = nil
+	} else if !w.started {
+		if err := w.csv.Write(joinedColumns(w.headers, "")); err != nil {
+			return err
+		}
+	}
+
+	w.csv.Flush()
+	return w.csv.Error()
+}
+
+// fieldAt returns record[i], or an empty field if the record is short.
+func fieldAt(record []*csvpp.Field, i int) *csvpp.Field {
+	if i < len(record) && record[i] != nil {
+		return record[i]
+	}
+	return &csvpp.Field{}
+}
+
+// joinedColumns returns the CSVArrayJoin column names for headers.
+func joinedColumns(headers []*csvpp.ColumnHeader, prefix string) []string {
+	var columns []string
+	for _, h := range headers {
+		switch h.Kind {
+		case csvpp.ArrayField:
+			columns = append(columns, prefix+h.Name+"[]")
+		case csvpp.StructuredField:
+			columns = append(columns, joinedColumns(h.Components, prefix+h.Name+".")...)
+		case csvpp.ArrayStructuredField:
+			columns = append(columns, joinedColumns(h.Components, prefix+h.Name+"[].")...)
+		default:
+			columns = append(columns, prefix+h.Name)
+		}
+	}
+	return columns
+}
+
+// joinedValues returns the CSVArrayJoin column values for record.
+func joinedValues(headers []*csvpp.ColumnHeader, record []*csvpp.Field) []string {
+	var values []string
+	for i, h := range headers {
+		values = append(values, joinedFieldValues(h, fieldAt(record, i))...)
+	}
+	return values
+}
+
+// joinedFieldValues returns the CSVArrayJoin column values for one field.
+// Array-structured components are joined element-wise with the array delimiter.
+func joinedFieldValues(h *csvpp.ColumnHeader, f *csvpp.Field) []string {
+	switch h.Kind {
+	case csvpp.ArrayField:
+		return []string{strings.Join(f.Values, string(h.ArrayDelimiter))}
+	case csvpp.StructuredField:
+		return joinedValues(h.Components, f.Components)
+	case csvpp.ArrayStructuredField:
+		columns := make([][]string, len(joinedColumns(h.Components, "")))
+		for _, elem := range f.Components {
+			if elem == nil {
+				elem = &csvpp.Field{}
+			}
+			for j, v := range joinedValues(h.Components, elem.Components) {
+				columns[j] = append(columns[j], v)
+			}
+		}
+		values := make([]string, len(columns))
+		for j, c := range columns {
+			values[j] = strings.Join(c, string(h.ArrayDelimiter))
+		}
+		return values
+	default:
+		return []string{f.Value}
+	}
+}
+
+// shape records the largest array length seen for a header (and its components),
+// which determines the CSVArrayIndex columns.
+type shape struct {
+	max   int
+	comps []*shape
+}
+
+// newShapes returns empty shapes for headers.
+func newShapes(headers []*csvpp.ColumnHeader) []*shape {
+	shapes := make([]*shape, len(headers))
+	for i, h := range headers {
+		shapes[i] = &shape{comps: newShapes(h.Components)}
+	}
+	return shapes
+}
+
+// update widens s to fit f.
+func (s *shape) update(h *csvpp.ColumnHeader, f *csvpp.Field) {
+	switch h.Kind {
+	case csvpp.ArrayField:
+		s.max = max(s.max, len(f.Values))
+	case csvpp.StructuredField:
+		for j, c := range h.Components {
+			s.comps[j].update(c, fieldAt(f.Components, j))
+		}
+	case csvpp.ArrayStructuredField:
+		s.max = max(s.max, len(f.Components))
+		for _, elem := range f.Components {
+			if elem == nil {
+				continue
+			}
+			for j, c := range h.Components {
+				s.comps[j].update(c, fieldAt(elem.Components, j))
+			}
+		}
+	}
+}
+
+// columns appends the CSVArrayIndex column names of h to dst.
+func (s *shape) columns(h *csvpp.ColumnHeader, prefix string, dst []string) []string {
+	switch h.Kind {
+	case csvpp.ArrayField:
+		for i := range s.max {
+			dst = append(dst, fmt.Sprintf("%s%s[%d]", prefix, h.Name, i))
+		}
+	case csvpp.StructuredField:
+		for j, c := range h.Components {
+			dst = s.comps[j].columns(c, prefix+h.Name+".", dst)
+		}
+	case csvpp.ArrayStructuredField:
+		for i := range s.max {
+			for j, c := range h.Components {
+				dst = s.comps[j].columns(c, fmt.Sprintf("%s%s[%d].", prefix, h.Name, i), dst)
+			}
+		}
+	default:
+		dst = append(dst, prefix+h.Name)
+	}
+	return dst
+}
+
+// values appends the CSVArrayIndex column values of f to dst.
+func (s *shape) values(h *csvpp.ColumnHeader, f *csvpp.Field, dst []string) []string {
+	switch h.Kind {
+	case csvpp.ArrayField:
+		for i := range s.max {
+			v := ""
+			if i < len(f.Values) {
+				v = f.Values[i]
+			}
+			dst = append(dst, v)
+		}
+	case csvpp.StructuredField:
+		for j, c := range h.Components {
+			dst = s.comps[j].values(c, fieldAt(f.Components, j), dst)
+		}
+	case csvpp.ArrayStructuredField:
+		for i := range s.max {
+			elem := fieldAt(f.Components, i)
+			for j, c := range h.Components {
+				dst = s.comps[j].values(c, fieldAt(elem.Components, j), dst)
+			}
+		}
+	default:
+		dst = append(dst, f.Value)
+	}
+	return dst
+}
@@ -0,0 +1,118 @@
+package csvpputil_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/osamingo/go-csvpp"
+	"github.com/osamingo/go-csvpp/csvpputil"
+)
+
+func TestCSVWriter(t *testing.T) {
+	t.Parallel()
+
+	headers := []*csvpp.ColumnHeader{
+		{Name: "name", Kind: csvpp.SimpleField},
+		{Name: "tags", Kind: csvpp.ArrayField, ArrayDelimiter: '~'},
+		{Name: "geo", Kind: csvpp.StructuredField, ComponentDelimiter: '^', Components: []*csvpp.ColumnHeader{
+			{Name: "lat", Kind: csvpp.SimpleField},
+			{Name: "lon", Kind: csvpp.SimpleField},
+		}},
+		{Name: "address", Kind: csvpp.ArrayStructuredField, ArrayDelimiter: '~', ComponentDelimiter: '^', Components: []*csvpp.ColumnHeader{
+			{Name: "street", Kind: csvpp.SimpleField},
+			{Name: "city", Kind: csvpp.SimpleField},
+		}},
+	}
+	records := [][]*csvpp.Field{
+		{
+			{Value: "Alice"},
+			{Values: []string{"go", "rust"}},
+			{Components: []*csvpp.Field{{Value: "35.6"}, {Value: "139.7"}}},
+			{Components: []*csvpp.Field{
+				{Components: []*csvpp.Field{{Value: "1-1 Chiyoda"}, {Value: "Tokyo"}}},
+				{Components: []*csvpp.Field{{Value: "Main St"}, {Value: "Boston"}}},
+			}},
+		},
+		{
+			{Value: "Bob"},
+			{Values: []string{"python"}},
+			{Components: []*csvpp.Field{{Value: "40.7"}, {Value: "-74.0"}}},
+			{},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		opts    []csvpputil.CSVWriterOption
+		records [][]*csvpp.Field
+		want    string
+	}{
+		{
+			name:    "success: joined arrays",
+			records: records,
+			want: "name,tags[],geo.lat,geo.lon,address[].street,address[].city\n" +
+				"Alice,go~rust,35.6,139.7,1-1 Chiyoda~Main St,Tokyo~Boston\n" +
+				"Bob,python,40.7,-74.0,,\n",
+		},
+		{
+			name:    "success: indexed arrays",
+			opts:    []csvpputil.CSVWriterOption{csvpputil.WithCSVArrayMode(csvpputil.CSVArrayIndex)},
+			records: records,
+			want: "name,tags[0],tags[1],geo.lat,geo.lon,address[0].street,address[0].city,address[1].street,address[1].city\n" +
+				"Alice,go,rust,35.6,139.7,1-1 Chiyoda,Tokyo,Main St,Boston\n" +
+				"Bob,python,,40.7,-74.0,,,,\n",
+		},
+		{
+			name:    "success: custom comma",
+			opts:    []csvpputil.CSVWriterOption{csvpputil.WithCSVComma(';')},
+			records: records[1:],
+			want: "name;tags[];geo.lat;geo.lon;address[].street;address[].city\n" +
+				"Bob;python;40.7;-74.0;;\n",
+		},
+		{
+			name: "success: header only for no records in joined mode",
+			want: "name,tags[],geo.lat,geo.lon,address[].street,address[].city\n",
+		},
+		{
+			name: "success: no array columns for no records in indexed mode",
+			opts: []csvpputil.CSVWriterOption{csvpputil.WithCSVArrayMode(csvpputil.CSVArrayIndex)},
+			want: "name,geo.lat,geo.lon\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			w := csvpputil.NewCSVWriter(&buf, headers, tt.opts...)
+			for _, record := range tt.records {
+				if err := w.Write(record); err != nil {
+					t.Fatalf("Write() error = %v", err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, buf.String()); diff != "" {
+				t.Errorf("output mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCSVWriter_WriteAfterClose(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	w := csvpputil.NewCSVWriter(&buf, []*csvpp.ColumnHeader{{Name: "name", Kind: csvpp.SimpleField}})
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := w.Write([]*csvpp.Field{{Value: "Alice"}}); err == nil {
+		t.Error("Write() after Close() expected error but got nil")
+	}
+}
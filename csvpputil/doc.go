@@ -1,5 +1,5 @@
 // Package csvpputil provides utility functions for converting CSV++ data
-// to other formats such as JSON and YAML.
+// to other formats such as JSON, NDJSON, YAML, plain CSV, TOML, XML and MessagePack.
 //
 // # JSON Streaming Output
 //
@@ -22,6 +22,12 @@
 //	    return err
 //	}
 //
+// # NDJSON Output
+//
+// NDJSONWriter writes one JSON object per line (JSON Lines) as each record is written:
+//
+//	w := csvpputil.NewNDJSONWriter(out, headers)
+//
 // # YAML Streaming Output
 //
 // For YAML output, use YAMLArrayWriter:
@@ -36,6 +42,36 @@
 //	    return err
 //	}
 //
+// # Plain CSV Output
+//
+// CSVWriter flattens records into plain CSV with dotted columns for structured
+// fields ("geo.lat") and joined ("tags[]") or indexed ("tags[0]") array columns:
+//
+//	w := csvpputil.NewCSVWriter(out, headers, csvpputil.WithCSVArrayMode(csvpputil.CSVArrayIndex))
+//
+// # Typed Values
+//
+// JSON, NDJSON and YAML values are strings by default. ValueTypes writes them as
+// numbers, booleans and nulls, inferred or set per field path:
+//
+//	w := csvpputil.NewJSONArrayWriter(out, headers, csvpputil.WithJSONValueTypes(csvpputil.InferValueTypes()))
+//	vt, err := csvpputil.ValueTypesOf([]Person{}) // from csvpp struct tags
+//
+// # TOML, XML and MessagePack Output
+//
+// TOMLWriter writes a [[records]] table per record, XMLWriter a <record>
+// element with nested component elements, and MsgpackWriter one map per record:
+//
+//	w := csvpputil.NewTOMLWriter(out, headers)
+//	w := csvpputil.NewXMLWriter(out, headers, csvpputil.WithXMLRecordName("person"))
+//	w := csvpputil.NewMsgpackWriter(out, headers)
+//
+// # Parquet
+//
+// Parquet files and Arrow record batches are written and read by the parquet
+// subpackage, which is kept apart so that only programs using it depend on
+// Apache Arrow.
+//
 // # Convenience Functions
 //
 // For small to medium datasets, use the Marshal or Write functions:
@@ -45,4 +81,28 @@
 //
 //	err := csvpputil.WriteJSON(w, headers, records)
 //	err := csvpputil.WriteYAML(w, headers, records)
+//
+// # Sorting
+//
+// Sort streams records from a Reader to a Writer ordered by one or more keys.
+// Keys are field paths with optional modifiers (see ParseSortKey); inputs larger
+// than the memory limit are sorted externally using temporary files:
+//
+//	keys := []csvpputil.SortKey{
+//	    {Path: "geo.lat", Numeric: true, Desc: true},
+//	    {Path: "tags", Count: true},
+//	}
+//	err := csvpputil.Sort(csvpp.NewWriter(out), csvpp.NewReader(in), keys,
+//	    csvpputil.WithSortMemoryLimit(256<<20))
+//
+// SortRecords sorts records that are already in memory.
+//
+// # Explode and Nest
+//
+// Explode writes one record per element of an array field, promoting the
+// components of an array-structured field to "<column>_<component>" columns.
+// Nest groups consecutive records back into an array field:
+//
+//	err := csvpputil.Explode(csvpp.NewWriter(out), csvpp.NewReader(in), "address")
+//	err := csvpputil.Nest(csvpp.NewWriter(out), csvpp.NewReader(in), "address")
 package csvpputil
@@ -0,0 +1,310 @@
+package csvpputil
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/osamingo/go-csvpp"
+)
+
+// ErrInvalidColumn is returned when a column cannot be exploded or nested.
+var ErrInvalidColumn = errors.New("csvpputil: invalid column")
+
+// Explode reads every record from src and writes one record per element of the
+// array or array-structured field column to dst, preceded by the header row.
+//
+// An array field becomes a simple field of the same name holding one element.
+// The components of an array-structured field are promoted to columns named
+// "<column>_<component>" (address[](street^city) becomes address_street and
+// address_city). A record whose array is empty is kept as a single record with
+// empty element values.
+func Explode(dst *csvpp.Writer, src *csvpp.Reader, column string) error {
+	headers, err := src.Headers()
+	if err != nil {
+		return err
+	}
+	idx := headerIndex(headers, column)
+	if idx < 0 {
+		return fmt.Errorf("%w: %q not found", ErrInvalidColumn, column)
+	}
+	h := headers[idx]
+	if h.Kind != csvpp.ArrayField && h.Kind != csvpp.ArrayStructuredField {
+		return fmt.Errorf("%w: %q is not an array field", ErrInvalidColumn, column)
+	}
+
+	var promoted []*csvpp.ColumnHeader
+	if h.Kind == csvpp.ArrayField {
+		promoted = []*csvpp.ColumnHeader{{Name: h.Name, Kind: csvpp.SimpleField}}
+	} else {
+		for _, c := range h.Components {
+			p := *c
+			p.Name = h.Name + "_" + c.Name
+			promoted = append(promoted, &p)
+		}
+	}
+	dst.SetHeaders(spliceHeaders(headers, idx, 1, promoted))
+	if err := dst.WriteHeader(); err != nil {
+		return err
+	}
+
+	for {
+		record, err := src.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		var elems [][]*csvpp.Field
+		f := fieldAt(record, idx)
+		if h.Kind == csvpp.ArrayField {
+			for _, v := range f.Values {
+				elems = append(elems, []*csvpp.Field{{Value: v}})
+			}
+		} else {
+			for _, e := range f.Components {
+				elem := make([]*csvpp.Field, len(h.Components))
+				for j := range h.Components {
+					if e != nil {
+						elem[j] = fieldAt(e.Components, j)
+					} else {
+						elem[j] = &csvpp.Field{}
+					}
+				}
+				elems = append(elems, elem)
+			}
+		}
+		if len(elems) == 0 {
+			elem := make([]*csvpp.Field, len(promoted))
+			for j := range elem {
+				elem[j] = &csvpp.Field{}
+			}
+			elems = append(elems, elem)
+		}
+
+		for _, elem := range elems {
+			if err := dst.Write(spliceFields(record, idx, 1, elem)); err != nil {
+				return err
+			}
+		}
+	}
+
+	dst.Flush()
+	return dst.Error()
+}
+
+// Nest is the inverse of Explode. It groups consecutive records of src whose
+// other fields are equal and writes one record per group to dst, with the
+// group's values collected into the array field column.
+//
+// If src has a simple field named column, its values are collected into an array
+// field. Otherwise the columns named "<column>_<component>" are collected into an
+// array-structured field with those components, placed at the first such column.
+// A group of one record whose element values are all empty becomes an empty array.
+func Nest(dst *csvpp.Writer, src *csvpp.Reader, column string) error {
+	headers, err := src.Headers()
+	if err != nil {
+		return err
+	}
+
+	var (
+		positions []int // element columns in src
+		nested    *csvpp.ColumnHeader
+	)
+	if idx := headerIndex(headers, column); idx >= 0 {
+		if headers[idx].Kind != csvpp.SimpleField {
+			return fmt.Errorf("%w: %q is not a simple field", ErrInvalidColumn, column)
+		}
+		positions = []int{idx}
+		nested = &csvpp.ColumnHeader{Name: column, Kind: csvpp.ArrayField, ArrayDelimiter: csvpp.DefaultArrayDelimiter}
+	} else {
+		var components []*csvpp.ColumnHeader
+		for i, h := range headers {
+			if name, ok := strings.CutPrefix(h.Name, column+"_"); ok && name != "" {
+				c := *h
+				c.Name = name
+				components = append(components, &c)
+				positions = append(positions, i)
+			}
+		}
+		if len(components) == 0 {
+			return fmt.Errorf("%w: no %q or %q columns found", ErrInvalidColumn, column, column+"_*")
+		}
+		arrayDelim, componentDelim := nestDelimiters(components)
+		nested = &csvpp.ColumnHeader{
+			Name:               column,
+			Kind:               csvpp.ArrayStructuredField,
+			ArrayDelimiter:     arrayDelim,
+			ComponentDelimiter: componentDelim,
+			Components:         components,
+		}
+	}
+
+	isElem := make(map[int]bool, len(positions))
+	for _, p := range positions {
+		isElem[p] = true
+	}
+	var rest []*csvpp.ColumnHeader
+	for i, h := range headers {
+		if !isElem[i] {
+			rest = append(rest, h)
+		}
+	}
+	at := positions[0]
+	dst.SetHeaders(spliceHeaders(rest, at, 0, []*csvpp.ColumnHeader{nested}))
+	if err := dst.WriteHeader(); err != nil {
+		return err
+	}
+
+	var (
+		started   bool
+		groupKey  string
+		groupRest []*csvpp.Field
+		elems     [][]*csvpp.Field
+	)
+	flush := func() error {
+		if !started {
+			return nil
+		}
+		if len(elems) == 1 && allEmpty(elems[0]) {
+			elems = nil
+		}
+		f := &csvpp.Field{}
+		for _, elem := range elems {
+			if nested.Kind == csvpp.ArrayField {
+				f.Values = append(f.Values, elem[0].Value)
+			} else {
+				f.Components = append(f.Components, &csvpp.Field{Components: elem})
+			}
+		}
+		return dst.Write(spliceFields(groupRest, at, 0, []*csvpp.Field{f}))
+	}
+
+	for {
+		record, err := src.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		var restFields, elem []*csvpp.Field
+		for i := range headers {
+			if isElem[i] {
+				elem = append(elem, fieldAt(record, i))
+			} else {
+				restFields = append(restFields, fieldAt(record, i))
+			}
+		}
+
+		key := fieldsKey(restFields)
+		if !started || key != groupKey {
+			if err := flush(); err != nil {
+				return err
+			}
+			started, groupKey, groupRest, elems = true, key, restFields, nil
+		}
+		elems = append(elems, elem)
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+
+	dst.Flush()
+	return dst.Error()
+}
+
+// headerIndex returns the index of the header named name, or -1.
+func headerIndex(headers []*csvpp.ColumnHeader, name string) int {
+	for i, h := range headers {
+		if h.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// spliceHeaders returns a copy of headers with n entries at i replaced by insert.
+func spliceHeaders(headers []*csvpp.ColumnHeader, i, n int, insert []*csvpp.ColumnHeader) []*csvpp.ColumnHeader {
+	out := make([]*csvpp.ColumnHeader, 0, len(headers)-n+len(insert))
+	out = append(out, headers[:i]...)
+	out = append(out, insert...)
+	return append(out, headers[i+n:]...)
+}
+
+// spliceFields returns a copy of record with n fields at i replaced by insert.
+func spliceFields(record []*csvpp.Field, i, n int, insert []*csvpp.Field) []*csvpp.Field {
+	i = min(i, len(record))
+	n = min(n, len(record)-i)
+	out := make([]*csvpp.Field, 0, len(record)-n+len(insert))
+	out = append(out, record[:i]...)
+	out = append(out, insert...)
+	return append(out, record[i+n:]...)
+}
+
+// allEmpty reports whether every field holds no values.
+func allEmpty(fields []*csvpp.Field) bool {
+	for _, f := range fields {
+		if f.Value != "" || len(f.Values) > 0 || !allEmpty(f.Components) {
+			return false
+		}
+	}
+	return true
+}
+
+// fieldsKey returns a string that is equal for equal field lists.
+func fieldsKey(fields []*csvpp.Field) string {
+	var sb strings.Builder
+	var write func([]*csvpp.Field)
+	write = func(fields []*csvpp.Field) {
+		sb.WriteByte('[')
+		for _, f := range fields {
+			sb.WriteString(strconv.Quote(f.Value))
+			for _, v := range f.Values {
+				sb.WriteString(strconv.Quote(v))
+			}
+			write(f.Components)
+		}
+		sb.WriteByte(']')
+	}
+	write(fields)
+	return sb.String()
+}
+
+// nestDelimiters returns array and component delimiters for an array-structured
+// field that are not used by any of its components.
+func nestDelimiters(components []*csvpp.ColumnHeader) (rune, rune) {
+	used := make(map[rune]bool)
+	var walk func([]*csvpp.ColumnHeader)
+	walk = func(headers []*csvpp.ColumnHeader) {
+		for _, h := range headers {
+			switch h.Kind {
+			case csvpp.ArrayField:
+				used[h.ArrayDelimiter] = true
+			case csvpp.StructuredField:
+				used[h.ComponentDelimiter] = true
+			case csvpp.ArrayStructuredField:
+				used[h.ArrayDelimiter] = true
+				used[h.ComponentDelimiter] = true
+			}
+			walk(h.Components)
+		}
+	}
+	walk(components)
+
+	var free []rune
+	for _, d := range csvpp.Delimiters() {
+		if !used[d] {
+			free = append(free, d)
+		}
+	}
+	if len(free) < 2 {
+		return csvpp.DefaultArrayDelimiter, csvpp.DefaultComponentDelimiter
+	}
+	return free[0], free[1]
+}
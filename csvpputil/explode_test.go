@@ -0,0 +1,170 @@
+package csvpputil_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/osamingo/go-csvpp"
+	"github.com/osamingo/go-csvpp/csvpputil"
+)
+
+const explodeTestInput = `name,tags[],address[](street^city)
+Alice,go~rust,1-1 Chiyoda^Tokyo~5th Ave^New York
+Bob,python,Main St^Boston
+Carol,,
+`
+
+func TestExplode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		input   string
+		column  string
+		want    string
+		wantErr error
+	}{
+		{
+			name:   "success: array field",
+			input:  explodeTestInput,
+			column: "tags",
+			want: "name,tags,address[](street^city)\n" +
+				"Alice,go,1-1 Chiyoda^Tokyo~5th Ave^New York\n" +
+				"Alice,rust,1-1 Chiyoda^Tokyo~5th Ave^New York\n" +
+				"Bob,python,Main St^Boston\n" +
+				"Carol,,\n",
+		},
+		{
+			name:   "success: array-structured field",
+			input:  explodeTestInput,
+			column: "address",
+			want: "name,tags[],address_street,address_city\n" +
+				"Alice,go~rust,1-1 Chiyoda,Tokyo\n" +
+				"Alice,go~rust,5th Ave,New York\n" +
+				"Bob,python,Main St,Boston\n" +
+				"Carol,,,\n",
+		},
+		{
+			name:    "error: unknown column",
+			input:   explodeTestInput,
+			column:  "email",
+			wantErr: csvpputil.ErrInvalidColumn,
+		},
+		{
+			name:    "error: simple field",
+			input:   explodeTestInput,
+			column:  "name",
+			wantErr: csvpputil.ErrInvalidColumn,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			err := csvpputil.Explode(csvpp.NewWriter(&buf), csvpp.NewReader(strings.NewReader(tt.input)), tt.column)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Explode() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Explode() unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, buf.String()); diff != "" {
+				t.Errorf("Explode() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNest(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		input   string
+		column  string
+		want    string
+		wantErr error
+	}{
+		{
+			name:   "success: simple field into array field",
+			input:  "name,tag\nAlice,go\nAlice,rust\nBob,python\nAlice,zig\n",
+			column: "tag",
+			want:   "name,tag[]\nAlice,go~rust\nBob,python\nAlice,zig\n",
+		},
+		{
+			name:   "success: promoted columns into array-structured field",
+			input:  "name,address_street,address_city,age\nAlice,1-1 Chiyoda,Tokyo,30\nAlice,5th Ave,New York,30\nCarol,,,41\n",
+			column: "address",
+			want:   "name,address[](street^city),age\nAlice,1-1 Chiyoda^Tokyo~5th Ave^New York,30\nCarol,,41\n",
+		},
+		{
+			name:   "success: delimiters avoid component delimiters",
+			input:  "name,address_tags[~],address_city\nAlice,a~b,Tokyo\nAlice,c,Osaka\n",
+			column: "address",
+			want:   "name,address[^];(tags[];city)\nAlice,a~b;Tokyo^c;Osaka\n",
+		},
+		{
+			name:    "error: no matching columns",
+			input:   "name,age\nAlice,30\n",
+			column:  "address",
+			wantErr: csvpputil.ErrInvalidColumn,
+		},
+		{
+			name:    "error: array field",
+			input:   "name,tags[]\nAlice,go\n",
+			column:  "tags",
+			wantErr: csvpputil.ErrInvalidColumn,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			err := csvpputil.Nest(csvpp.NewWriter(&buf), csvpp.NewReader(strings.NewReader(tt.input)), tt.column)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Nest() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Nest() unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, buf.String()); diff != "" {
+				t.Errorf("Nest() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestExplodeNest_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, column := range []string{"tags", "address"} {
+		t.Run(column, func(t *testing.T) {
+			t.Parallel()
+
+			var exploded, nested bytes.Buffer
+			if err := csvpputil.Explode(csvpp.NewWriter(&exploded), csvpp.NewReader(strings.NewReader(explodeTestInput)), column); err != nil {
+				t.Fatalf("Explode() error = %v", err)
+			}
+			if err := csvpputil.Nest(csvpp.NewWriter(&nested), csvpp.NewReader(&exploded), column); err != nil {
+				t.Fatalf("Nest() error = %v", err)
+			}
+			if diff := cmp.Diff(explodeTestInput, nested.String()); diff != "" {
+				t.Errorf("round trip mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -19,11 +19,20 @@ func WithDeterministic(_ bool) JSONArrayWriterOption { //nolint:unused // kept f
 	return func(_ *JSONArrayWriter) {} //nolint:unused // kept for API compatibility
 }
 
+// WithJSONValueTypes writes simple values as JSON numbers, booleans and nulls
+// as described by vt instead of as strings.
+func WithJSONValueTypes(vt ValueTypes) JSONArrayWriterOption {
+	return func(w *JSONArrayWriter) {
+		w.types = &vt
+	}
+}
+
 // JSONArrayWriter writes CSV++ records as a JSON array using streaming output.
 // It uses jsontext.Encoder internally for efficient token-level writing.
 type JSONArrayWriter struct {
 	enc     *jsontext.Encoder
 	headers []*csvpp.ColumnHeader
+	types   *ValueTypes
 	started bool
 	closed  bool
 }
@@ -54,12 +63,13 @@ func (w *JSONArrayWriter) Write(record []*csvpp.Field) error {
 		w.started = true
 	}
 
-	return w.writeObject(w.headers, record)
+	return writeJSONObject(w.enc, w.headers, record, w.types, "")
 }
 
-// writeObject writes fields as a JSON object.
-func (w *JSONArrayWriter) writeObject(headers []*csvpp.ColumnHeader, fields []*csvpp.Field) error {
-	if err := w.enc.WriteToken(jsontext.BeginObject); err != nil {
+// writeJSONObject writes fields as a JSON object.
+// Values are typed by vt, with field paths relative to prefix.
+func writeJSONObject(enc *jsontext.Encoder, headers []*csvpp.ColumnHeader, fields []*csvpp.Field, vt *ValueTypes, prefix string) error {
+	if err := enc.WriteToken(jsontext.BeginObject); err != nil {
 		return err
 	}
 
@@ -69,58 +79,72 @@ func (w *JSONArrayWriter) writeObject(headers []*csvpp.ColumnHeader, fields []*c
 		field := fields[i]
 
 		// Write key
-		if err := w.enc.WriteToken(jsontext.String(header.Name)); err != nil {
+		if err := enc.WriteToken(jsontext.String(header.Name)); err != nil {
 			return err
 		}
 
 		// Write value
-		if err := w.writeValue(header, field); err != nil {
+		if err := writeJSONValue(enc, header, field, vt, joinPath(prefix, header.Name)); err != nil {
 			return err
 		}
 	}
 
-	return w.enc.WriteToken(jsontext.EndObject)
+	return enc.WriteToken(jsontext.EndObject)
 }
 
-// writeValue writes a single field value.
-func (w *JSONArrayWriter) writeValue(header *csvpp.ColumnHeader, field *csvpp.Field) error {
+// writeJSONValue writes a single field value.
+func writeJSONValue(enc *jsontext.Encoder, header *csvpp.ColumnHeader, field *csvpp.Field, vt *ValueTypes, path string) error {
 	if header == nil || field == nil {
-		return w.enc.WriteToken(jsontext.Null)
+		return enc.WriteToken(jsontext.Null)
 	}
 
 	switch header.Kind {
 	case csvpp.SimpleField:
-		return w.enc.WriteToken(jsontext.String(field.Value))
+		return writeJSONScalar(enc, field.Value, vt, path)
 
 	case csvpp.ArrayField:
-		if err := w.enc.WriteToken(jsontext.BeginArray); err != nil {
+		if err := enc.WriteToken(jsontext.BeginArray); err != nil {
 			return err
 		}
 		for _, v := range field.Values {
-			if err := w.enc.WriteToken(jsontext.String(v)); err != nil {
+			if err := writeJSONScalar(enc, v, vt, path); err != nil {
 				return err
 			}
 		}
-		return w.enc.WriteToken(jsontext.EndArray)
+		return enc.WriteToken(jsontext.EndArray)
 
 	case csvpp.StructuredField:
-		return w.writeObject(header.Components, field.Components)
+		return writeJSONObject(enc, header.Components, field.Components, vt, path)
 
 	case csvpp.ArrayStructuredField:
-		if err := w.enc.WriteToken(jsontext.BeginArray); err != nil {
+		if err := enc.WriteToken(jsontext.BeginArray); err != nil {
 			return err
 		}
 		for _, comp := range field.Components {
 			if comp != nil {
-				if err := w.writeObject(header.Components, comp.Components); err != nil {
+				if err := writeJSONObject(enc, header.Components, comp.Components, vt, path); err != nil {
 					return err
 				}
 			}
 		}
-		return w.enc.WriteToken(jsontext.EndArray)
+		return enc.WriteToken(jsontext.EndArray)
+
+	default:
+		return writeJSONScalar(enc, field.Value, vt, path)
+	}
+}
 
+// writeJSONScalar writes a simple value as the JSON type vt selects for path.
+func writeJSONScalar(enc *jsontext.Encoder, s string, vt *ValueTypes, path string) error {
+	switch vt.kind(path, s, isJSONNumber) {
+	case kindNull:
+		return enc.WriteToken(jsontext.Null)
+	case kindNumber:
+		return enc.WriteValue(jsontext.Value(s))
+	case kindBoolean:
+		return enc.WriteToken(jsontext.Bool(s == "true"))
 	default:
-		return w.enc.WriteToken(jsontext.String(field.Value))
+		return enc.WriteToken(jsontext.String(s))
 	}
 }
 
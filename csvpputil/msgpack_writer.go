@@ -0,0 +1,109 @@
+package csvpputil
+
+import (
+	"io"
+
+	"github.com/vmihailenco/msgpack/v5"
+
+	"github.com/osamingo/go-csvpp"
+)
+
+// MsgpackWriterOption is a functional option for MsgpackWriter.
+type MsgpackWriterOption func(*MsgpackWriter)
+
+// MsgpackWriter writes CSV++ records as a stream of MessagePack maps, one map
+// per record, with keys in header order. Array fields are encoded as arrays of
+// strings, structured fields as nested maps, and array-structured fields as
+// arrays of maps. Each record is written as soon as Write is called.
+type MsgpackWriter struct {
+	enc     *msgpack.Encoder
+	headers []*csvpp.ColumnHeader
+	closed  bool
+}
+
+// NewMsgpackWriter creates a new MsgpackWriter that writes to w.
+func NewMsgpackWriter(w io.Writer, headers []*csvpp.ColumnHeader, opts ...MsgpackWriterOption) *MsgpackWriter {
+	writer := &MsgpackWriter{
+		enc:     msgpack.NewEncoder(w),
+		headers: headers,
+	}
+	for _, opt := range opts {
+		opt(writer)
+	}
+	return writer
+}
+
+// Write writes a single record as a MessagePack map.
+func (w *MsgpackWriter) Write(record []*csvpp.Field) error {
+	if w.closed {
+		return io.ErrClosedPipe
+	}
+	return writeMsgpackMap(w.enc, w.headers, record)
+}
+
+// Close marks the writer as closed. Records are already written by Write,
+// so there is nothing to flush.
+func (w *MsgpackWriter) Close() error {
+	w.closed = true
+	return nil
+}
+
+// writeMsgpackMap writes fields as a MessagePack map keyed by header names.
+func writeMsgpackMap(enc *msgpack.Encoder, headers []*csvpp.ColumnHeader, fields []*csvpp.Field) error {
+	n := min(len(headers), len(fields))
+	if err := enc.EncodeMapLen(n); err != nil {
+		return err
+	}
+	for i := range n {
+		if err := enc.EncodeString(headers[i].Name); err != nil {
+			return err
+		}
+		if err := writeMsgpackValue(enc, headers[i], fields[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeMsgpackValue writes a single field value.
+func writeMsgpackValue(enc *msgpack.Encoder, header *csvpp.ColumnHeader, field *csvpp.Field) error {
+	if field == nil {
+		return enc.EncodeNil()
+	}
+
+	switch header.Kind {
+	case csvpp.ArrayField:
+		if err := enc.EncodeArrayLen(len(field.Values)); err != nil {
+			return err
+		}
+		for _, v := range field.Values {
+			if err := enc.EncodeString(v); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case csvpp.StructuredField:
+		return writeMsgpackMap(enc, header.Components, field.Components)
+
+	case csvpp.ArrayStructuredField:
+		var elems []*csvpp.Field
+		for _, comp := range field.Components {
+			if comp != nil {
+				elems = append(elems, comp)
+			}
+		}
+		if err := enc.EncodeArrayLen(len(elems)); err != nil {
+			return err
+		}
+		for _, elem := range elems {
+			if err := writeMsgpackMap(enc, header.Components, elem.Components); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	default:
+		return enc.EncodeString(field.Value)
+	}
+}
@@ -0,0 +1,116 @@
+package csvpputil_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/vmihailenco/msgpack/v5"
+
+	"github.com/osamingo/go-csvpp"
+	"github.com/osamingo/go-csvpp/csvpputil"
+)
+
+func TestMsgpackWriter(t *testing.T) {
+	t.Parallel()
+
+	headers := []*csvpp.ColumnHeader{
+		{Name: "name", Kind: csvpp.SimpleField},
+		{Name: "tags", Kind: csvpp.ArrayField},
+		{Name: "geo", Kind: csvpp.StructuredField, Components: []*csvpp.ColumnHeader{
+			{Name: "lat", Kind: csvpp.SimpleField},
+			{Name: "lon", Kind: csvpp.SimpleField},
+		}},
+		{Name: "address", Kind: csvpp.ArrayStructuredField, Components: []*csvpp.ColumnHeader{
+			{Name: "city", Kind: csvpp.SimpleField},
+		}},
+	}
+	records := [][]*csvpp.Field{
+		{
+			{Value: "Alice"},
+			{Values: []string{"go", "rust"}},
+			{Components: []*csvpp.Field{{Value: "35.6"}, {Value: "139.7"}}},
+			{Components: []*csvpp.Field{{Components: []*csvpp.Field{{Value: "Tokyo"}}}}},
+		},
+		{{Value: "Bob"}, {}, {Components: []*csvpp.Field{{}, {}}}, {}},
+	}
+
+	var buf bytes.Buffer
+	w := csvpputil.NewMsgpackWriter(&buf, headers)
+	for _, record := range records {
+		if err := w.Write(record); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	var got []map[string]any
+	dec := msgpack.NewDecoder(&buf)
+	for {
+		var m map[string]any
+		err := dec.Decode(&m)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+		got = append(got, m)
+	}
+
+	want := []map[string]any{
+		{
+			"name":    "Alice",
+			"tags":    []any{"go", "rust"},
+			"geo":     map[string]any{"lat": "35.6", "lon": "139.7"},
+			"address": []any{map[string]any{"city": "Tokyo"}},
+		},
+		{
+			"name":    "Bob",
+			"tags":    []any{},
+			"geo":     map[string]any{"lat": "", "lon": ""},
+			"address": []any{},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("decoded mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestMsgpackWriter_KeyOrder(t *testing.T) {
+	t.Parallel()
+
+	headers := []*csvpp.ColumnHeader{
+		{Name: "b", Kind: csvpp.SimpleField},
+		{Name: "a", Kind: csvpp.SimpleField},
+	}
+
+	var buf bytes.Buffer
+	w := csvpputil.NewMsgpackWriter(&buf, headers)
+	if err := w.Write([]*csvpp.Field{{Value: "1"}, {Value: "2"}}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	// fixmap(2) "b" "1" "a" "2"
+	want := []byte{0x82, 0xa1, 'b', 0xa1, '1', 0xa1, 'a', 0xa1, '2'}
+	if diff := cmp.Diff(want, buf.Bytes()); diff != "" {
+		t.Errorf("output mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestMsgpackWriter_WriteAfterClose(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	w := csvpputil.NewMsgpackWriter(&buf, []*csvpp.ColumnHeader{{Name: "name", Kind: csvpp.SimpleField}})
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := w.Write([]*csvpp.Field{{Value: "Alice"}}); !errors.Is(err, io.ErrClosedPipe) {
+		t.Errorf("Write() after Close() error = %v, want %v", err, io.ErrClosedPipe)
+	}
+}
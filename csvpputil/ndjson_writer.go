@@ -0,0 +1,55 @@
+package csvpputil
+
+import (
+	"encoding/json/jsontext"
+	"io"
+
+	"github.com/osamingo/go-csvpp"
+)
+
+// NDJSONWriterOption is a functional option for NDJSONWriter.
+type NDJSONWriterOption func(*NDJSONWriter)
+
+// WithNDJSONValueTypes writes simple values as JSON numbers, booleans and nulls
+// as described by vt instead of as strings.
+func WithNDJSONValueTypes(vt ValueTypes) NDJSONWriterOption {
+	return func(w *NDJSONWriter) {
+		w.types = &vt
+	}
+}
+
+// NDJSONWriter writes CSV++ records as newline-delimited JSON (JSON Lines),
+// one JSON object per line. Each record is written as soon as Write is called.
+type NDJSONWriter struct {
+	enc     *jsontext.Encoder
+	headers []*csvpp.ColumnHeader
+	types   *ValueTypes
+	closed  bool
+}
+
+// NewNDJSONWriter creates a new NDJSONWriter that writes to w.
+func NewNDJSONWriter(w io.Writer, headers []*csvpp.ColumnHeader, opts ...NDJSONWriterOption) *NDJSONWriter {
+	writer := &NDJSONWriter{
+		enc:     jsontext.NewEncoder(w),
+		headers: headers,
+	}
+	for _, opt := range opts {
+		opt(writer)
+	}
+	return writer
+}
+
+// Write writes a single record as a JSON object followed by a newline.
+func (w *NDJSONWriter) Write(record []*csvpp.Field) error {
+	if w.closed {
+		return io.ErrClosedPipe
+	}
+	return writeJSONObject(w.enc, w.headers, record, w.types, "")
+}
+
+// Close marks the writer as closed. Records are already written by Write,
+// so there is nothing to flush.
+func (w *NDJSONWriter) Close() error {
+	w.closed = true
+	return nil
+}
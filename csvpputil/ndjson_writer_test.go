@@ -0,0 +1,80 @@
+package csvpputil_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/osamingo/go-csvpp"
+	"github.com/osamingo/go-csvpp/csvpputil"
+)
+
+func TestNDJSONWriter(t *testing.T) {
+	t.Parallel()
+
+	headers := []*csvpp.ColumnHeader{
+		{Name: "name", Kind: csvpp.SimpleField},
+		{Name: "tags", Kind: csvpp.ArrayField},
+		{Name: "geo", Kind: csvpp.StructuredField, Components: []*csvpp.ColumnHeader{
+			{Name: "lat", Kind: csvpp.SimpleField},
+			{Name: "lon", Kind: csvpp.SimpleField},
+		}},
+	}
+
+	tests := []struct {
+		name    string
+		records [][]*csvpp.Field
+		want    string
+	}{
+		{
+			name: "success: one object per line",
+			records: [][]*csvpp.Field{
+				{{Value: "Alice"}, {Values: []string{"go", "rust"}}, {Components: []*csvpp.Field{{Value: "35.6"}, {Value: "139.7"}}}},
+				{{Value: "Bob"}, {}, {Components: []*csvpp.Field{{Value: "40.7"}, {Value: "-74.0"}}}},
+			},
+			want: `{"name":"Alice","tags":["go","rust"],"geo":{"lat":"35.6","lon":"139.7"}}` + "\n" +
+				`{"name":"Bob","tags":[],"geo":{"lat":"40.7","lon":"-74.0"}}` + "\n",
+		},
+		{
+			name: "success: no records",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			w := csvpputil.NewNDJSONWriter(&buf, headers)
+			for _, record := range tt.records {
+				if err := w.Write(record); err != nil {
+					t.Fatalf("Write() error = %v", err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, buf.String()); diff != "" {
+				t.Errorf("output mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNDJSONWriter_WriteAfterClose(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	w := csvpputil.NewNDJSONWriter(&buf, []*csvpp.ColumnHeader{{Name: "name", Kind: csvpp.SimpleField}})
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := w.Write([]*csvpp.Field{{Value: "Alice"}}); !errors.Is(err, io.ErrClosedPipe) {
+		t.Errorf("Write() after Close() error = %v, want %v", err, io.ErrClosedPipe)
+	}
+}
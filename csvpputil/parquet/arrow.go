@@ -0,0 +1,216 @@
+package parquet
+
+import (
+	"errors"
+	"io"
+	"sync/atomic"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+
+	"github.com/osamingo/go-csvpp"
+)
+
+// DefaultArrowBatchSize is the default number of records per Arrow record batch.
+const DefaultArrowBatchSize = 1024
+
+// ArrowOption is a functional option for ArrowRecordReader and NewArrowRecordBatch.
+type ArrowOption func(*arrowConfig)
+
+// arrowConfig holds Arrow conversion settings.
+type arrowConfig struct {
+	batchSize int
+	mem       memory.Allocator
+}
+
+// newArrowConfig returns the settings for opts.
+func newArrowConfig(opts []ArrowOption) arrowConfig {
+	cfg := arrowConfig{batchSize: DefaultArrowBatchSize, mem: memory.DefaultAllocator}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// WithArrowBatchSize sets the maximum number of records per record batch.
+// The default is DefaultArrowBatchSize.
+func WithArrowBatchSize(n int) ArrowOption {
+	return func(c *arrowConfig) {
+		if n > 0 {
+			c.batchSize = n
+		}
+	}
+}
+
+// WithArrowAllocator sets the memory allocator used to build Arrow arrays.
+// The default is memory.DefaultAllocator.
+func WithArrowAllocator(mem memory.Allocator) ArrowOption {
+	return func(c *arrowConfig) {
+		c.mem = mem
+	}
+}
+
+// ArrowRecordReader reads CSV++ records from a csvpp.Reader and returns them as
+// Arrow record batches with the schema from ArrowSchema. It implements
+// array.RecordReader, so it can be handed to Arrow-based engines directly.
+//
+// Each batch is valid until the next call to Next; call Retain on it to keep it
+// longer. Release the reader when done.
+type ArrowRecordReader struct {
+	refs    atomic.Int64
+	src     *csvpp.Reader
+	headers []*csvpp.ColumnHeader
+	schema  *arrow.Schema
+	builder *array.RecordBuilder
+	cfg     arrowConfig
+	cur     arrow.Record
+	done    bool
+	err     error
+}
+
+var _ array.RecordReader = (*ArrowRecordReader)(nil)
+
+// NewArrowRecordReader creates an ArrowRecordReader that reads from src.
+// It reads the headers of src to build the schema.
+func NewArrowRecordReader(src *csvpp.Reader, opts ...ArrowOption) (*ArrowRecordReader, error) {
+	headers, err := src.Headers()
+	if err != nil {
+		return nil, err
+	}
+	cfg := newArrowConfig(opts)
+	schema := ArrowSchema(headers)
+	r := &ArrowRecordReader{
+		src:     src,
+		headers: headers,
+		schema:  schema,
+		builder: array.NewRecordBuilder(cfg.mem, schema),
+		cfg:     cfg,
+	}
+	r.refs.Store(1)
+	return r, nil
+}
+
+// Retain increases the reference count by 1.
+func (r *ArrowRecordReader) Retain() {
+	r.refs.Add(1)
+}
+
+// Release decreases the reference count by 1, releasing the current batch and
+// the builder when it reaches zero.
+func (r *ArrowRecordReader) Release() {
+	if r.refs.Add(-1) != 0 {
+		return
+	}
+	if r.cur != nil {
+		r.cur.Release()
+		r.cur = nil
+	}
+	r.builder.Release()
+}
+
+// Schema returns the Arrow schema derived from the CSV++ headers.
+func (r *ArrowRecordReader) Schema() *arrow.Schema {
+	return r.schema
+}
+
+// Next reads up to the batch size of records into a new record batch.
+// It returns false when the input is exhausted or an error occurred (see Err).
+func (r *ArrowRecordReader) Next() bool {
+	if r.cur != nil {
+		r.cur.Release()
+		r.cur = nil
+	}
+	if r.done {
+		return false
+	}
+
+	n := 0
+	for n < r.cfg.batchSize {
+		record, err := r.src.Read()
+		if errors.Is(err, io.EOF) {
+			r.done = true
+			break
+		}
+		if err != nil {
+			r.done, r.err = true, err
+			return false
+		}
+		appendArrowRecord(r.builder, r.headers, record)
+		n++
+	}
+	if n == 0 {
+		return false
+	}
+	r.cur = r.builder.NewRecord()
+	return true
+}
+
+// Record returns the current record batch.
+func (r *ArrowRecordReader) Record() arrow.Record {
+	return r.cur
+}
+
+// Err returns the error that stopped Next, if any.
+func (r *ArrowRecordReader) Err() error {
+	return r.err
+}
+
+// NewArrowRecordBatch builds a single Arrow record batch from in-memory records.
+// The batch size option is ignored. The caller must Release the batch.
+func NewArrowRecordBatch(headers []*csvpp.ColumnHeader, records [][]*csvpp.Field, opts ...ArrowOption) arrow.Record {
+	cfg := newArrowConfig(opts)
+	b := array.NewRecordBuilder(cfg.mem, ArrowSchema(headers))
+	defer b.Release()
+	for _, record := range records {
+		appendArrowRecord(b, headers, record)
+	}
+	return b.NewRecord()
+}
+
+// RecordsFromArrow converts every row of rec to CSV++ fields described by headers,
+// which usually come from HeadersFromArrowSchema(rec.Schema()). It returns
+// ErrInvalidSchema if headers do not match the schema of rec.
+func RecordsFromArrow(rec arrow.Record, headers []*csvpp.ColumnHeader) ([][]*csvpp.Field, error) {
+	records := make([][]*csvpp.Field, rec.NumRows())
+	for i := range records {
+		record, err := arrowRecordFields(rec, headers, i)
+		if err != nil {
+			return nil, err
+		}
+		records[i] = record
+	}
+	return records, nil
+}
+
+// WriteArrow writes the header row derived from the schema of src, then every
+// row of every record batch of src, to dst.
+func WriteArrow(dst *csvpp.Writer, src array.RecordReader) error {
+	headers, err := HeadersFromArrowSchema(src.Schema())
+	if err != nil {
+		return err
+	}
+	dst.SetHeaders(headers)
+	if err := dst.WriteHeader(); err != nil {
+		return err
+	}
+
+	for src.Next() {
+		rec := src.Record()
+		for i := range int(rec.NumRows()) {
+			record, err := arrowRecordFields(rec, headers, i)
+			if err != nil {
+				return err
+			}
+			if err := dst.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+	if err := src.Err(); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	dst.Flush()
+	return dst.Error()
+}
@@ -0,0 +1,284 @@
+package parquet_test
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/osamingo/go-csvpp"
+	"github.com/osamingo/go-csvpp/csvpputil/parquet"
+)
+
+func TestArrowSchema(t *testing.T) {
+	t.Parallel()
+
+	headers, err := csvpp.NewReader(strings.NewReader("name,tags[],geo(lat^lon),address[](street^phones[;])\n")).Headers()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, f := range parquet.ArrowSchema(headers).Fields() {
+		got = append(got, f.Name+": "+f.Type.String())
+	}
+	want := []string{
+		"name: utf8",
+		"tags: list<item: utf8>",
+		"geo: struct<lat: utf8, lon: utf8>",
+		"address: list<item: struct<street: utf8, phones: list<item: utf8>>>",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("schema mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestHeadersFromArrowSchema(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		schema  *arrow.Schema
+		want    []*csvpp.ColumnHeader
+		wantErr bool
+	}{
+		{
+			name: "success: nested types",
+			schema: arrow.NewSchema([]arrow.Field{
+				{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+				{Name: "tags", Type: arrow.ListOf(arrow.BinaryTypes.String)},
+				{Name: "geo", Type: arrow.StructOf(
+					arrow.Field{Name: "lat", Type: arrow.PrimitiveTypes.Float64},
+					arrow.Field{Name: "codes", Type: arrow.ListOf(arrow.PrimitiveTypes.Int32)},
+				)},
+				{Name: "address", Type: arrow.ListOf(arrow.StructOf(
+					arrow.Field{Name: "city", Type: arrow.BinaryTypes.String},
+				))},
+			}, nil),
+			want: []*csvpp.ColumnHeader{
+				{Name: "id", Kind: csvpp.SimpleField, ArrayDelimiter: '~', ComponentDelimiter: '^'},
+				{Name: "tags", Kind: csvpp.ArrayField, ArrayDelimiter: '~', ComponentDelimiter: '^'},
+				{Name: "geo", Kind: csvpp.StructuredField, ArrayDelimiter: '~', ComponentDelimiter: '^', Components: []*csvpp.ColumnHeader{
+					{Name: "lat", Kind: csvpp.SimpleField, ArrayDelimiter: ';', ComponentDelimiter: ':'},
+					{Name: "codes", Kind: csvpp.ArrayField, ArrayDelimiter: ';', ComponentDelimiter: ':'},
+				}},
+				{Name: "address", Kind: csvpp.ArrayStructuredField, ArrayDelimiter: '~', ComponentDelimiter: '^', Components: []*csvpp.ColumnHeader{
+					{Name: "city", Kind: csvpp.SimpleField, ArrayDelimiter: ';', ComponentDelimiter: ':'},
+				}},
+			},
+		},
+		{
+			name: "error: name with a space",
+			schema: arrow.NewSchema([]arrow.Field{
+				{Name: "first name", Type: arrow.BinaryTypes.String},
+			}, nil),
+			wantErr: true,
+		},
+		{
+			name: "error: nested name with a dot",
+			schema: arrow.NewSchema([]arrow.Field{
+				{Name: "geo", Type: arrow.StructOf(arrow.Field{Name: "lat.deg", Type: arrow.PrimitiveTypes.Float64})},
+			}, nil),
+			wantErr: true,
+		},
+		{
+			name: "error: name with brackets",
+			schema: arrow.NewSchema([]arrow.Field{
+				{Name: "tags[]", Type: arrow.ListOf(arrow.BinaryTypes.String)},
+			}, nil),
+			wantErr: true,
+		},
+		{
+			name: "error: empty name",
+			schema: arrow.NewSchema([]arrow.Field{
+				{Name: "", Type: arrow.BinaryTypes.String},
+			}, nil),
+			wantErr: true,
+		},
+		{
+			name: "error: nested too deeply",
+			schema: func() *arrow.Schema {
+				// One level more than the delimiter progression provides.
+				var typ arrow.DataType = arrow.BinaryTypes.String
+				for range len(csvpp.Delimiters()) / 2 {
+					typ = arrow.StructOf(arrow.Field{Name: "a", Type: typ})
+				}
+				return arrow.NewSchema([]arrow.Field{{Name: "a", Type: typ}}, nil)
+			}(),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parquet.HeadersFromArrowSchema(tt.schema)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("HeadersFromArrowSchema() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !errors.Is(err, parquet.ErrInvalidSchema) {
+					t.Errorf("HeadersFromArrowSchema() error = %v, want %v", err, parquet.ErrInvalidSchema)
+				}
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("headers mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestArrowRecordReader(t *testing.T) {
+	t.Parallel()
+
+	input := "name,tags[],geo(lat^lon),address[](street^city)\n" +
+		"Alice,go~rust,35.6^139.7,1-1 Chiyoda^Tokyo~5th Ave^New York\n" +
+		"Bob,,40.7^-74.0,\n" +
+		"Carol,python,^,Main St^Boston\n"
+
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	rr, err := parquet.NewArrowRecordReader(csvpp.NewReader(strings.NewReader(input)),
+		parquet.WithArrowBatchSize(2), parquet.WithArrowAllocator(mem))
+	if err != nil {
+		t.Fatalf("NewArrowRecordReader() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	w := csvpp.NewWriter(&buf)
+	if err := parquet.WriteArrow(w, rr); err != nil {
+		t.Fatalf("WriteArrow() error = %v", err)
+	}
+	rr.Release()
+
+	if diff := cmp.Diff(input, buf.String()); diff != "" {
+		t.Errorf("round trip mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestArrowRecordReader_Batches(t *testing.T) {
+	t.Parallel()
+
+	input := "name\na\nb\nc\n"
+	rr, err := parquet.NewArrowRecordReader(csvpp.NewReader(strings.NewReader(input)), parquet.WithArrowBatchSize(2))
+	if err != nil {
+		t.Fatalf("NewArrowRecordReader() error = %v", err)
+	}
+	defer rr.Release()
+
+	var sizes []int64
+	for rr.Next() {
+		sizes = append(sizes, rr.Record().NumRows())
+	}
+	if err := rr.Err(); err != nil {
+		t.Fatalf("Err() = %v", err)
+	}
+	if diff := cmp.Diff([]int64{2, 1}, sizes); diff != "" {
+		t.Errorf("batch sizes mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestArrowRecordReader_ReadError(t *testing.T) {
+	t.Parallel()
+
+	input := "name,age\nAlice,30\nBob,25,extra\n"
+	rr, err := parquet.NewArrowRecordReader(csvpp.NewReader(strings.NewReader(input)))
+	if err != nil {
+		t.Fatalf("NewArrowRecordReader() error = %v", err)
+	}
+	defer rr.Release()
+
+	for rr.Next() {
+	}
+	if rr.Err() == nil {
+		t.Error("Err() = nil, want read error")
+	}
+}
+
+func TestRecordsFromArrow(t *testing.T) {
+	t.Parallel()
+
+	headers := []*csvpp.ColumnHeader{
+		{Name: "name", Kind: csvpp.SimpleField},
+		{Name: "tags", Kind: csvpp.ArrayField},
+		{Name: "geo", Kind: csvpp.StructuredField, Components: []*csvpp.ColumnHeader{
+			{Name: "lat", Kind: csvpp.SimpleField},
+			{Name: "lon", Kind: csvpp.SimpleField},
+		}},
+	}
+	records := [][]*csvpp.Field{
+		{{Value: "Alice"}, {Values: []string{"go", "rust"}}, {Components: []*csvpp.Field{{Value: "35.6"}, {Value: "139.7"}}}},
+		{{Value: "Bob"}, {}, {Components: []*csvpp.Field{{}, {}}}},
+	}
+
+	rec := parquet.NewArrowRecordBatch(headers, records)
+	t.Cleanup(rec.Release)
+
+	tests := []struct {
+		name    string
+		headers []*csvpp.ColumnHeader
+		want    [][]*csvpp.Field
+		wantErr bool
+	}{
+		{name: "success: matching headers", headers: headers, want: records},
+		{
+			name: "error: structured header for a string column",
+			headers: []*csvpp.ColumnHeader{
+				{Name: "name", Kind: csvpp.StructuredField, Components: []*csvpp.ColumnHeader{{Name: "a", Kind: csvpp.SimpleField}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "error: array-structured header for a string column",
+			headers: []*csvpp.ColumnHeader{
+				{Name: "name", Kind: csvpp.ArrayStructuredField, Components: []*csvpp.ColumnHeader{{Name: "a", Kind: csvpp.SimpleField}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "error: more components than struct fields",
+			headers: []*csvpp.ColumnHeader{
+				headers[0], headers[1],
+				{Name: "geo", Kind: csvpp.StructuredField, Components: []*csvpp.ColumnHeader{
+					{Name: "lat", Kind: csvpp.SimpleField},
+					{Name: "lon", Kind: csvpp.SimpleField},
+					{Name: "alt", Kind: csvpp.SimpleField},
+				}},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "error: more headers than columns",
+			headers: append(slices.Clone(headers), &csvpp.ColumnHeader{Name: "extra", Kind: csvpp.SimpleField}),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parquet.RecordsFromArrow(rec, tt.headers)
+			if tt.wantErr {
+				if !errors.Is(err, parquet.ErrInvalidSchema) {
+					t.Fatalf("RecordsFromArrow() error = %v, want ErrInvalidSchema", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RecordsFromArrow() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("records mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
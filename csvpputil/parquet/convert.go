@@ -0,0 +1,245 @@
+package parquet
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+
+	"github.com/osamingo/go-csvpp"
+)
+
+// ErrInvalidSchema is returned when a Parquet or Arrow schema cannot be
+// converted to CSV++ headers.
+var ErrInvalidSchema = errors.New("parquet: invalid schema")
+
+// ArrowSchema derives an Arrow schema from CSV++ headers. Simple fields become
+// non-nullable strings, array fields lists of strings, structured fields
+// structs, and array-structured fields lists of structs.
+func ArrowSchema(headers []*csvpp.ColumnHeader) *arrow.Schema {
+	return arrow.NewSchema(arrowFields(headers), nil)
+}
+
+// arrowFields converts headers to Arrow fields.
+func arrowFields(headers []*csvpp.ColumnHeader) []arrow.Field {
+	fields := make([]arrow.Field, len(headers))
+	for i, h := range headers {
+		fields[i] = arrow.Field{Name: h.Name, Type: arrowType(h)}
+	}
+	return fields
+}
+
+// arrowType returns the Arrow data type for a header.
+func arrowType(h *csvpp.ColumnHeader) arrow.DataType {
+	switch h.Kind {
+	case csvpp.ArrayField:
+		return arrow.ListOfNonNullable(arrow.BinaryTypes.String)
+	case csvpp.StructuredField:
+		return arrow.StructOf(arrowFields(h.Components)...)
+	case csvpp.ArrayStructuredField:
+		return arrow.ListOfNonNullable(arrow.StructOf(arrowFields(h.Components)...))
+	default:
+		return arrow.BinaryTypes.String
+	}
+}
+
+// appendArrowRecord appends one record to the field builders of b.
+func appendArrowRecord(b *array.RecordBuilder, headers []*csvpp.ColumnHeader, record []*csvpp.Field) {
+	for i, h := range headers {
+		appendArrowValue(b.Field(i), h, fieldAt(record, i))
+	}
+}
+
+// appendArrowValue appends a single field to a builder created for arrowType(h).
+func appendArrowValue(b array.Builder, h *csvpp.ColumnHeader, f *csvpp.Field) {
+	switch h.Kind {
+	case csvpp.ArrayField:
+		lb := b.(*array.ListBuilder)
+		lb.Append(true)
+		vb := lb.ValueBuilder().(*array.StringBuilder)
+		for _, v := range f.Values {
+			vb.Append(v)
+		}
+
+	case csvpp.StructuredField:
+		appendArrowStruct(b.(*array.StructBuilder), h.Components, f.Components)
+
+	case csvpp.ArrayStructuredField:
+		lb := b.(*array.ListBuilder)
+		lb.Append(true)
+		sb := lb.ValueBuilder().(*array.StructBuilder)
+		for _, elem := range f.Components {
+			if elem == nil {
+				continue
+			}
+			appendArrowStruct(sb, h.Components, elem.Components)
+		}
+
+	default:
+		b.(*array.StringBuilder).Append(f.Value)
+	}
+}
+
+// appendArrowStruct appends one struct value built from components.
+func appendArrowStruct(sb *array.StructBuilder, headers []*csvpp.ColumnHeader, components []*csvpp.Field) {
+	sb.Append(true)
+	for i, h := range headers {
+		appendArrowValue(sb.FieldBuilder(i), h, fieldAt(components, i))
+	}
+}
+
+// HeadersFromArrowSchema derives CSV++ headers from an Arrow schema.
+// Lists of structs become array-structured fields, other lists array fields,
+// structs structured fields, and every other type a simple field whose values
+// are the string representation of the Arrow values. Delimiters are assigned
+// by nesting level. Field names must be valid CSV++ names, made of ASCII
+// letters, digits, '_' and '-'.
+func HeadersFromArrowSchema(schema *arrow.Schema) ([]*csvpp.ColumnHeader, error) {
+	headers, err := headersFromArrowFields(schema.Fields(), 0)
+	if err != nil {
+		return nil, err
+	}
+	return csvpp.AssignDelimiters(headers, nil)
+}
+
+// headersFromArrowFields converts Arrow fields at nesting level to headers
+// without delimiters.
+func headersFromArrowFields(fields []arrow.Field, level int) ([]*csvpp.ColumnHeader, error) {
+	if 2*level+1 >= len(csvpp.Delimiters()) {
+		return nil, fmt.Errorf("%w: nested too deeply", ErrInvalidSchema)
+	}
+
+	headers := make([]*csvpp.ColumnHeader, len(fields))
+	for i, f := range fields {
+		if !csvpp.IsValidName(f.Name) {
+			return nil, fmt.Errorf("%w: Arrow field %q is not a valid CSV++ name", ErrInvalidSchema, f.Name)
+		}
+		h := &csvpp.ColumnHeader{Name: f.Name, Kind: csvpp.SimpleField}
+		var err error
+		switch t := f.Type.(type) {
+		case arrow.ListLikeType:
+			h.Kind = csvpp.ArrayField
+			if st, ok := t.Elem().(*arrow.StructType); ok {
+				h.Kind = csvpp.ArrayStructuredField
+				h.Components, err = headersFromArrowFields(st.Fields(), level+1)
+			}
+		case *arrow.StructType:
+			h.Kind = csvpp.StructuredField
+			h.Components, err = headersFromArrowFields(t.Fields(), level+1)
+		}
+		if err != nil {
+			return nil, err
+		}
+		headers[i] = h
+	}
+	return headers, nil
+}
+
+// arrowRecordFields converts row i of rec to CSV++ fields. It returns
+// ErrInvalidSchema if headers do not match the schema of rec.
+func arrowRecordFields(rec arrow.Record, headers []*csvpp.ColumnHeader, i int) ([]*csvpp.Field, error) {
+	if len(headers) > int(rec.NumCols()) {
+		return nil, fmt.Errorf("%w: %d headers for %d Arrow columns", ErrInvalidSchema, len(headers), rec.NumCols())
+	}
+	fields := make([]*csvpp.Field, len(headers))
+	for j, h := range headers {
+		f, err := arrowField(rec.Column(j), h, i)
+		if err != nil {
+			return nil, err
+		}
+		fields[j] = f
+	}
+	return fields, nil
+}
+
+// arrowField converts element i of arr to a field described by h.
+// Null values become empty fields.
+func arrowField(arr arrow.Array, h *csvpp.ColumnHeader, i int) (*csvpp.Field, error) {
+	if arr.IsNull(i) {
+		if h.Kind == csvpp.StructuredField {
+			return &csvpp.Field{Components: emptyFields(len(h.Components))}, nil
+		}
+		return &csvpp.Field{}, nil
+	}
+
+	switch h.Kind {
+	case csvpp.ArrayField:
+		list, ok := arr.(array.ListLike)
+		if !ok {
+			return &csvpp.Field{Values: []string{arrowString(arr, i)}}, nil
+		}
+		start, end := list.ValueOffsets(i)
+		values := list.ListValues()
+		f := &csvpp.Field{}
+		for j := int(start); j < int(end); j++ {
+			f.Values = append(f.Values, arrowString(values, j))
+		}
+		return f, nil
+
+	case csvpp.StructuredField:
+		s, ok := arr.(*array.Struct)
+		if !ok || len(h.Components) > s.NumField() {
+			return nil, fmt.Errorf("%w: column %q does not match Arrow type %s", ErrInvalidSchema, h.Name, arr.DataType())
+		}
+		f := &csvpp.Field{Components: make([]*csvpp.Field, len(h.Components))}
+		for k, c := range h.Components {
+			var err error
+			if f.Components[k], err = arrowField(s.Field(k), c, i); err != nil {
+				return nil, err
+			}
+		}
+		return f, nil
+
+	case csvpp.ArrayStructuredField:
+		list, ok := arr.(array.ListLike)
+		if !ok {
+			return nil, fmt.Errorf("%w: column %q does not match Arrow type %s", ErrInvalidSchema, h.Name, arr.DataType())
+		}
+		start, end := list.ValueOffsets(i)
+		elems := list.ListValues()
+		f := &csvpp.Field{}
+		for j := int(start); j < int(end); j++ {
+			c, err := arrowField(elems, &csvpp.ColumnHeader{
+				Name:       h.Name,
+				Kind:       csvpp.StructuredField,
+				Components: h.Components,
+			}, j)
+			if err != nil {
+				return nil, err
+			}
+			f.Components = append(f.Components, c)
+		}
+		return f, nil
+
+	default:
+		return &csvpp.Field{Value: arrowString(arr, i)}, nil
+	}
+}
+
+// arrowString formats element i of arr as a string, or "" if it is null.
+// The result does not share memory with the Arrow buffers.
+func arrowString(arr arrow.Array, i int) string {
+	if arr.IsNull(i) {
+		return ""
+	}
+	return strings.Clone(arr.ValueStr(i))
+}
+
+// fieldAt returns record[i], or an empty field if the record is short.
+func fieldAt(record []*csvpp.Field, i int) *csvpp.Field {
+	if i < len(record) && record[i] != nil {
+		return record[i]
+	}
+	return &csvpp.Field{}
+}
+
+// emptyFields returns n empty fields.
+func emptyFields(n int) []*csvpp.Field {
+	fields := make([]*csvpp.Field, n)
+	for i := range fields {
+		fields[i] = &csvpp.Field{}
+	}
+	return fields
+}
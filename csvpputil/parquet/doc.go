@@ -0,0 +1,23 @@
+// Package parquet converts CSV++ records to and from Apache Parquet files and
+// Apache Arrow record batches.
+//
+// It is separate from csvpputil so that only programs that use it depend on
+// Apache Arrow.
+//
+// Writer writes records to a Parquet file with array fields as repeated
+// columns, structured fields as groups and array-structured fields as repeated
+// groups. Reader reads such files back, deriving headers from the schema:
+//
+//	w := parquet.NewWriter(f, headers)
+//	r := parquet.NewReader(f) // f must support ReadAt and Seek
+//
+// # Arrow
+//
+// ArrowRecordReader streams records as Arrow record batches with list and
+// struct arrays for array and structured fields, and WriteArrow writes Arrow
+// record batches back as CSV++:
+//
+//	rr, err := parquet.NewArrowRecordReader(csvpp.NewReader(in))
+//	defer rr.Release()
+//	err = parquet.WriteArrow(csvpp.NewWriter(out), rr)
+package parquet
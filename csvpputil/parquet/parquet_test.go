@@ -0,0 +1,193 @@
+package parquet_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/osamingo/go-csvpp"
+	"github.com/osamingo/go-csvpp/csvpputil/parquet"
+)
+
+func TestRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	headers := []*csvpp.ColumnHeader{
+		{Name: "name", Kind: csvpp.SimpleField, ArrayDelimiter: '~', ComponentDelimiter: '^'},
+		{Name: "tags", Kind: csvpp.ArrayField, ArrayDelimiter: '~', ComponentDelimiter: '^'},
+		{Name: "geo", Kind: csvpp.StructuredField, ArrayDelimiter: '~', ComponentDelimiter: '^', Components: []*csvpp.ColumnHeader{
+			{Name: "lat", Kind: csvpp.SimpleField, ArrayDelimiter: ';', ComponentDelimiter: ':'},
+			{Name: "lon", Kind: csvpp.SimpleField, ArrayDelimiter: ';', ComponentDelimiter: ':'},
+		}},
+		{Name: "address", Kind: csvpp.ArrayStructuredField, ArrayDelimiter: '~', ComponentDelimiter: '^', Components: []*csvpp.ColumnHeader{
+			{Name: "street", Kind: csvpp.SimpleField, ArrayDelimiter: ';', ComponentDelimiter: ':'},
+			{Name: "phones", Kind: csvpp.ArrayField, ArrayDelimiter: ';', ComponentDelimiter: ':'},
+		}},
+	}
+
+	tests := []struct {
+		name    string
+		opts    []parquet.WriterOption
+		records [][]*csvpp.Field
+	}{
+		{
+			name: "success: nested types",
+			records: [][]*csvpp.Field{
+				{
+					{Value: "Alice"},
+					{Values: []string{"go", "rust"}},
+					{Components: []*csvpp.Field{{Value: "35.6"}, {Value: "139.7"}}},
+					{Components: []*csvpp.Field{
+						{Components: []*csvpp.Field{{Value: "1-1 Chiyoda"}, {Values: []string{"03-1234"}}}},
+						{Components: []*csvpp.Field{{Value: "5th Ave"}, {}}},
+					}},
+				},
+				{
+					{Value: "Bob"},
+					{},
+					{Components: []*csvpp.Field{{Value: "40.7"}, {Value: "-74.0"}}},
+					{},
+				},
+			},
+		},
+		{
+			name: "success: several row groups",
+			opts: []parquet.WriterOption{parquet.WithRowGroupSize(1)},
+			records: [][]*csvpp.Field{
+				{{Value: "a"}, {Values: []string{"x"}}, {Components: []*csvpp.Field{{}, {}}}, {}},
+				{{Value: "b"}, {}, {Components: []*csvpp.Field{{}, {}}}, {}},
+				{{Value: "c"}, {Values: []string{"y", "z"}}, {Components: []*csvpp.Field{{}, {}}}, {}},
+			},
+		},
+		{
+			name: "success: no records",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			w := parquet.NewWriter(&buf, headers, tt.opts...)
+			for _, record := range tt.records {
+				if err := w.Write(record); err != nil {
+					t.Fatalf("Write() error = %v", err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+
+			r := parquet.NewReader(bytes.NewReader(buf.Bytes()))
+			defer r.Close()
+
+			gotHeaders, err := r.Headers()
+			if err != nil {
+				t.Fatalf("Headers() error = %v", err)
+			}
+			if diff := cmp.Diff(headers, gotHeaders); diff != "" {
+				t.Errorf("headers mismatch (-want +got):\n%s", diff)
+			}
+
+			var got [][]*csvpp.Field
+			for {
+				record, err := r.Read()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				if err != nil {
+					t.Fatalf("Read() error = %v", err)
+				}
+				got = append(got, record)
+			}
+			if diff := cmp.Diff(tt.records, got); diff != "" {
+				t.Errorf("records mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReader_TypedColumns(t *testing.T) {
+	t.Parallel()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "score", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+		{Name: "ok", Type: arrow.FixedWidthTypes.Boolean},
+	}, nil)
+	b := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer b.Release()
+	b.Field(0).(*array.Int64Builder).AppendValues([]int64{1, 2}, nil)
+	b.Field(1).(*array.Float64Builder).AppendValues([]float64{1.5, 0}, []bool{true, false})
+	b.Field(2).(*array.BooleanBuilder).AppendValues([]bool{true, false}, nil)
+	rec := b.NewRecord()
+	defer rec.Release()
+
+	var buf bytes.Buffer
+	fw, err := pqarrow.NewFileWriter(schema, &buf, nil, pqarrow.DefaultWriterProps())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fw.Write(rec); err != nil {
+		t.Fatal(err)
+	}
+	if err := fw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r := parquet.NewReader(bytes.NewReader(buf.Bytes()))
+	defer r.Close()
+
+	var got [][]*csvpp.Field
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Read() error = %v", err)
+		}
+		got = append(got, record)
+	}
+
+	want := [][]*csvpp.Field{
+		{{Value: "1"}, {Value: "1.5"}, {Value: "true"}},
+		{{Value: "2"}, {}, {Value: "false"}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("records mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestReader_InvalidFile(t *testing.T) {
+	t.Parallel()
+
+	r := parquet.NewReader(bytes.NewReader([]byte("name\nAlice\n")))
+	if _, err := r.Headers(); err == nil {
+		t.Error("Headers() expected error for non-Parquet input")
+	}
+	if _, err := r.Read(); err == nil {
+		t.Error("Read() expected error for non-Parquet input")
+	}
+}
+
+func TestWriter_WriteAfterClose(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	w := parquet.NewWriter(&buf, []*csvpp.ColumnHeader{{Name: "name", Kind: csvpp.SimpleField}})
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := w.Write([]*csvpp.Field{{Value: "Alice"}}); !errors.Is(err, io.ErrClosedPipe) {
+		t.Errorf("Write() after Close() error = %v, want %v", err, io.ErrClosedPipe)
+	}
+}
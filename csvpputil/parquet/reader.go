@@ -0,0 +1,118 @@
+package parquet
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+
+	"github.com/osamingo/go-csvpp"
+)
+
+// batchSize is the number of rows decoded from a Parquet file at a time.
+const batchSize = 1024
+
+// Source is the random-access input a Parquet file is read from,
+// such as *os.File or *bytes.Reader.
+type Source interface {
+	io.ReaderAt
+	io.Seeker
+}
+
+// Reader reads CSV++ records from an Apache Parquet file, one at a time.
+//
+// Headers are derived from the file schema: repeated columns (lists) become
+// array fields, groups (structs) structured fields, and lists of groups
+// array-structured fields. Other column types are read as simple fields using
+// their string representation, and nulls become empty values.
+type Reader struct {
+	src Source
+
+	rr      pqarrow.RecordReader
+	rec     arrow.Record
+	row     int
+	headers []*csvpp.ColumnHeader
+	err     error
+}
+
+// NewReader creates a new Reader that reads from src.
+// The file is opened on the first call to Headers or Read.
+func NewReader(src Source) *Reader {
+	return &Reader{src: src}
+}
+
+// Headers returns the headers derived from the Parquet schema.
+func (r *Reader) Headers() ([]*csvpp.ColumnHeader, error) {
+	if r.headers != nil || r.err != nil {
+		return r.headers, r.err
+	}
+
+	pf, err := file.NewParquetReader(r.src)
+	if err != nil {
+		r.err = err
+		return nil, err
+	}
+
+	fr, err := pqarrow.NewFileReader(pf, pqarrow.ArrowReadProperties{BatchSize: batchSize}, memory.DefaultAllocator)
+	if err != nil {
+		r.err = err
+		return nil, err
+	}
+	schema, err := fr.Schema()
+	if err != nil {
+		r.err = err
+		return nil, err
+	}
+	headers, err := HeadersFromArrowSchema(schema)
+	if err != nil {
+		r.err = err
+		return nil, err
+	}
+	rr, err := fr.GetRecordReader(context.Background(), nil, nil)
+	if err != nil {
+		r.err = err
+		return nil, err
+	}
+	r.rr = rr
+	r.headers = headers
+	return headers, nil
+}
+
+// Read returns the next record, or io.EOF when the file is exhausted.
+func (r *Reader) Read() ([]*csvpp.Field, error) {
+	if _, err := r.Headers(); err != nil {
+		return nil, err
+	}
+
+	for r.rec == nil || r.row >= int(r.rec.NumRows()) {
+		if !r.rr.Next() {
+			if err := r.rr.Err(); err != nil && !errors.Is(err, io.EOF) {
+				r.err = err
+				return nil, err
+			}
+			return nil, io.EOF
+		}
+		r.rec, r.row = r.rr.Record(), 0
+	}
+
+	record, err := arrowRecordFields(r.rec, r.headers, r.row)
+	if err != nil {
+		r.err = err
+		return nil, err
+	}
+	r.row++
+	return record, nil
+}
+
+// Close releases the resources held by the reader. It does not close the source.
+func (r *Reader) Close() error {
+	if r.rr != nil {
+		r.rr.Release()
+		r.rr = nil
+	}
+	return nil
+}
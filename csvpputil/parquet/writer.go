@@ -0,0 +1,138 @@
+package parquet
+
+import (
+	"io"
+
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	pq "github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/compress"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+
+	"github.com/osamingo/go-csvpp"
+)
+
+// DefaultRowGroupSize is the default number of records per Parquet row group.
+const DefaultRowGroupSize = 10000
+
+// WriterOption is a functional option for Writer.
+type WriterOption func(*Writer)
+
+// WithRowGroupSize sets the number of records buffered and written as one
+// row group. The default is DefaultRowGroupSize.
+func WithRowGroupSize(n int) WriterOption {
+	return func(w *Writer) {
+		if n > 0 {
+			w.rowGroupSize = n
+		}
+	}
+}
+
+// Writer writes CSV++ records as an Apache Parquet file with a schema
+// derived from the headers:
+//
+//	name                    required binary (STRING)
+//	tags[]                  LIST of required binary (STRING)  (repeated column)
+//	geo(lat^lon)            group { lat, lon }
+//	address[](street^city)  LIST of group { street, city }   (repeated group)
+//
+// Records are buffered and written one row group at a time, so memory use is
+// bounded by the row group size. Columns are Snappy-compressed. The footer is
+// written by Close, which does not close the underlying writer.
+type Writer struct {
+	w            io.Writer
+	headers      []*csvpp.ColumnHeader
+	rowGroupSize int
+
+	fw      *pqarrow.FileWriter
+	builder *array.RecordBuilder
+	rows    int
+	err     error
+	closed  bool
+}
+
+// NewWriter creates a new Writer that writes to w.
+func NewWriter(w io.Writer, headers []*csvpp.ColumnHeader, opts ...WriterOption) *Writer {
+	writer := &Writer{
+		w:            w,
+		headers:      headers,
+		rowGroupSize: DefaultRowGroupSize,
+	}
+	for _, opt := range opts {
+		opt(writer)
+	}
+	return writer
+}
+
+// Write buffers a single record, writing a row group when the buffer is full.
+func (w *Writer) Write(record []*csvpp.Field) error {
+	if w.closed {
+		return io.ErrClosedPipe
+	}
+	if err := w.init(); err != nil {
+		return err
+	}
+
+	appendArrowRecord(w.builder, w.headers, record)
+	w.rows++
+	if w.rows >= w.rowGroupSize {
+		return w.flush()
+	}
+	return nil
+}
+
+// Close writes the buffered records and the file footer.
+func (w *Writer) Close() error {
+	if w.closed {
+		return w.err
+	}
+	w.closed = true
+
+	if err := w.init(); err != nil {
+		return err
+	}
+	defer w.builder.Release()
+
+	if err := w.flush(); err != nil {
+		return err
+	}
+	if err := w.fw.Close(); err != nil {
+		w.err = err
+	}
+	return w.err
+}
+
+// init creates the Parquet file writer on first use.
+func (w *Writer) init() error {
+	if w.err != nil || w.fw != nil {
+		return w.err
+	}
+
+	schema := ArrowSchema(w.headers)
+	props := pq.NewWriterProperties(pq.WithCompression(compress.Codecs.Snappy))
+	// Hide Close from pqarrow, which would otherwise close w.
+	fw, err := pqarrow.NewFileWriter(schema, struct{ io.Writer }{w.w}, props, pqarrow.DefaultWriterProps())
+	if err != nil {
+		w.err = err
+		return err
+	}
+	w.fw = fw
+	w.builder = array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	return nil
+}
+
+// flush writes the buffered records as a row group.
+func (w *Writer) flush() error {
+	if w.rows == 0 {
+		return nil
+	}
+	rec := w.builder.NewRecord()
+	defer rec.Release()
+	w.rows = 0
+
+	if err := w.fw.Write(rec); err != nil {
+		w.err = err
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,497 @@
+package csvpputil
+
+import (
+	"bufio"
+	"cmp"
+	"container/heap"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/osamingo/go-csvpp"
+)
+
+// ErrInvalidSortKey is returned when a sort key cannot be parsed or does not fit the headers.
+var ErrInvalidSortKey = errors.New("csvpputil: invalid sort key")
+
+// DefaultSortMemoryLimit is the default approximate number of bytes of records
+// Sort holds in memory before spilling sorted runs to temporary files.
+const DefaultSortMemoryLimit = 64 << 20
+
+// maxMergeRuns is the number of run files Sort merges at once. More runs are
+// merged in several passes, so that the number of open files stays bounded.
+const maxMergeRuns = 64
+
+// SortKey describes one key to sort records by.
+type SortKey struct {
+	Path    string // Field path in csvpp.Record syntax, e.g. "name", "geo.lat", "address[0].city"
+	Numeric bool   // Compare values as numbers; non-numeric values sort last
+	Count   bool   // Compare the number of elements selected by Path instead of its value
+	Desc    bool   // Sort in descending order
+}
+
+// ParseSortKey parses a sort key of the form "path[:modifier...]".
+// Modifiers are "num" (numeric), "len" (element count), "asc" and "desc":
+//
+//	name
+//	geo.lat:num:desc
+//	tags:len
+func ParseSortKey(s string) (SortKey, error) {
+	parts := strings.Split(s, ":")
+	key := SortKey{Path: parts[0]}
+	if key.Path == "" {
+		return SortKey{}, fmt.Errorf("%w: %q: empty path", ErrInvalidSortKey, s)
+	}
+	for _, mod := range parts[1:] {
+		switch strings.ToLower(mod) {
+		case "num", "numeric":
+			key.Numeric = true
+		case "len", "count":
+			key.Count = true
+		case "asc":
+			key.Desc = false
+		case "desc":
+			key.Desc = true
+		default:
+			return SortKey{}, fmt.Errorf("%w: %q: unknown modifier %q", ErrInvalidSortKey, s, mod)
+		}
+	}
+	return key, nil
+}
+
+// String returns the key in the form accepted by ParseSortKey.
+func (k SortKey) String() string { //nostyle:recvtype
+	var sb strings.Builder
+	sb.WriteString(k.Path)
+	if k.Count {
+		sb.WriteString(":len")
+	}
+	if k.Numeric {
+		sb.WriteString(":num")
+	}
+	if k.Desc {
+		sb.WriteString(":desc")
+	}
+	return sb.String()
+}
+
+// SortOption is a functional option for Sort.
+type SortOption func(*sortConfig)
+
+// sortConfig holds Sort settings.
+type sortConfig struct {
+	memoryLimit int
+	tempDir     string
+}
+
+// WithSortMemoryLimit sets the approximate number of bytes of records Sort holds
+// in memory before spilling a sorted run to a temporary file.
+// The default is DefaultSortMemoryLimit, which is also used for a limit of
+// zero or less.
+func WithSortMemoryLimit(bytes int) SortOption {
+	return func(c *sortConfig) {
+		if bytes <= 0 {
+			bytes = DefaultSortMemoryLimit
+		}
+		c.memoryLimit = bytes
+	}
+}
+
+// WithSortTempDir sets the directory for temporary run files.
+// The default is os.TempDir.
+func WithSortTempDir(dir string) SortOption {
+	return func(c *sortConfig) {
+		c.tempDir = dir
+	}
+}
+
+// SortRecords sorts records in place by keys.
+// The sort is stable: records with equal keys keep their original order.
+func SortRecords(headers []*csvpp.ColumnHeader, records [][]*csvpp.Field, keys []SortKey) error {
+	s, err := newSorter(headers, keys)
+	if err != nil {
+		return err
+	}
+
+	items := make([]sortItem, len(records))
+	for i, record := range records {
+		items[i] = s.item(record)
+	}
+	slices.SortStableFunc(items, s.compare)
+	for i, item := range items {
+		records[i] = item.record
+	}
+	return nil
+}
+
+// Sort reads every record from src and writes them to dst sorted by keys,
+// preceded by the header row. The sort is stable.
+//
+// Inputs larger than the memory limit are sorted externally: sorted runs are
+// spilled to temporary files, which are merged and removed before Sort returns.
+// Many runs are merged in several passes to bound the number of open files.
+func Sort(dst *csvpp.Writer, src *csvpp.Reader, keys []SortKey, opts ...SortOption) (retErr error) {
+	cfg := &sortConfig{memoryLimit: DefaultSortMemoryLimit}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	headers, err := src.Headers()
+	if err != nil {
+		return err
+	}
+	s, err := newSorter(headers, keys)
+	if err != nil {
+		return err
+	}
+
+	var (
+		runs  []string
+		chunk []sortItem
+		size  int
+	)
+	defer func() {
+		for _, name := range runs {
+			if rerr := os.Remove(name); rerr != nil && retErr == nil {
+				retErr = rerr
+			}
+		}
+	}()
+
+	for {
+		record, err := src.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		chunk = append(chunk, s.item(record))
+		size += recordSize(record)
+
+		if size >= cfg.memoryLimit {
+			slices.SortStableFunc(chunk, s.compare)
+			name, err := writeRun(cfg.tempDir, chunk)
+			if err != nil {
+				return err
+			}
+			runs = append(runs, name)
+			chunk, size = nil, 0
+		}
+	}
+
+	for len(runs) > maxMergeRuns {
+		if runs, err = s.mergePass(cfg.tempDir, runs); err != nil {
+			return err
+		}
+	}
+
+	dst.SetHeaders(headers)
+	if err := dst.WriteHeader(); err != nil {
+		return err
+	}
+
+	slices.SortStableFunc(chunk, s.compare)
+	if len(runs) == 0 {
+		for _, item := range chunk {
+			if err := dst.Write(item.record); err != nil {
+				return err
+			}
+		}
+	} else if err := s.merge(runs, chunk, dst.Write); err != nil {
+		return err
+	}
+
+	dst.Flush()
+	return dst.Error()
+}
+
+// sorter compares records by a list of compiled keys.
+type sorter struct {
+	headers []*csvpp.ColumnHeader
+	keys    []SortKey
+	paths   []*csvpp.Path
+}
+
+// newSorter compiles keys against headers.
+func newSorter(headers []*csvpp.ColumnHeader, keys []SortKey) (*sorter, error) {
+	s := &sorter{headers: headers, keys: keys, paths: make([]*csvpp.Path, len(keys))}
+	for i, key := range keys {
+		p, err := csvpp.ParsePath(key.Path)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidSortKey, err)
+		}
+		if _, err := p.Header(headers); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidSortKey, err)
+		}
+		s.paths[i] = p
+	}
+	return s, nil
+}
+
+// sortItem is a record with its precomputed key values.
+type sortItem struct {
+	record []*csvpp.Field
+	values []sortValue
+}
+
+// sortValue is the value of one key for one record.
+type sortValue struct {
+	str string
+	num float64
+	ok  bool // the record has a (numeric, for numeric keys) value
+}
+
+// item computes the key values of record.
+func (s *sorter) item(record []*csvpp.Field) sortItem {
+	rec := csvpp.NewRecord(s.headers, record)
+	values := make([]sortValue, len(s.keys))
+	for i, key := range s.keys {
+		if key.Count {
+			n, err := rec.LenPath(s.paths[i])
+			values[i] = sortValue{num: float64(n), ok: err == nil}
+			continue
+		}
+
+		all, err := rec.GetAllPath(s.paths[i])
+		if err != nil || len(all) == 0 {
+			continue
+		}
+		v := sortValue{str: all[0], ok: true}
+		if key.Numeric {
+			v.num, err = strconv.ParseFloat(strings.TrimSpace(v.str), 64)
+			v.ok = err == nil
+		}
+		values[i] = v
+	}
+	return sortItem{record: record, values: values}
+}
+
+// compare orders two items by the sorter's keys.
+// Missing values sort last regardless of direction.
+func (s *sorter) compare(a, b sortItem) int {
+	for i, key := range s.keys {
+		av, bv := a.values[i], b.values[i]
+		var c int
+		switch {
+		case !av.ok && !bv.ok:
+			continue
+		case !av.ok:
+			return 1
+		case !bv.ok:
+			return -1
+		case key.Numeric || key.Count:
+			c = cmp.Compare(av.num, bv.num)
+		default:
+			c = strings.Compare(av.str, bv.str)
+		}
+		if key.Desc {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// recordSize estimates the in-memory size of record in bytes.
+func recordSize(record []*csvpp.Field) int {
+	const fieldOverhead = 64
+	size := 0
+	for _, f := range record {
+		if f == nil {
+			continue
+		}
+		size += fieldOverhead + len(f.Value) + recordSize(f.Components)
+		for _, v := range f.Values {
+			size += len(v) + 16
+		}
+	}
+	return size
+}
+
+// writeRun writes sorted items to a new temporary file and returns its name.
+func writeRun(dir string, items []sortItem) (string, error) {
+	return createRun(dir, func(enc *gob.Encoder) error {
+		for _, item := range items {
+			if err := enc.Encode(item.record); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// createRun creates a temporary run file, writes records to it with write and
+// returns its name. Runs use encoding/gob so that values round-trip exactly.
+func createRun(dir string, write func(enc *gob.Encoder) error) (name string, retErr error) {
+	f, err := os.CreateTemp(dir, "csvpp-sort-*.run")
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if cerr := f.Close(); cerr != nil && retErr == nil {
+			retErr = cerr
+		}
+		if retErr != nil {
+			os.Remove(f.Name()) //nolint:errcheck,gosec // the original error is more relevant
+		}
+	}()
+
+	bw := bufio.NewWriter(f)
+	if err := write(gob.NewEncoder(bw)); err != nil {
+		return "", err
+	}
+	if err := bw.Flush(); err != nil {
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// mergePass merges each maxMergeRuns consecutive runs into one run, removing
+// the merged runs, and returns the new runs. On error it returns every run
+// file that still exists. Merging consecutive runs keeps the sort stable.
+func (s *sorter) mergePass(dir string, runs []string) ([]string, error) {
+	var merged []string
+	for len(runs) > 0 {
+		group := runs[:min(len(runs), maxMergeRuns)]
+		name, err := createRun(dir, func(enc *gob.Encoder) error {
+			return s.merge(group, nil, func(record []*csvpp.Field) error { return enc.Encode(record) })
+		})
+		if err != nil {
+			return append(merged, runs...), err
+		}
+		merged = append(merged, name)
+		for i, run := range group {
+			if err := os.Remove(run); err != nil {
+				return append(merged, runs[i:]...), err
+			}
+		}
+		runs = runs[len(group):]
+	}
+	return merged, nil
+}
+
+// runReader reads records back from a run file.
+type runReader struct {
+	f   *os.File
+	dec *gob.Decoder
+}
+
+// next decodes the next record, returning io.EOF at the end of the run.
+func (r *runReader) next() ([]*csvpp.Field, error) {
+	var record []*csvpp.Field
+	if err := r.dec.Decode(&record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// mergeEntry is the head record of one run in the merge heap.
+type mergeEntry struct {
+	item sortItem
+	run  int
+}
+
+// mergeHeap is a min-heap of run heads. Ties are broken by run index,
+// which keeps the merge stable because runs hold consecutive input ranges.
+type mergeHeap struct {
+	entries []mergeEntry
+	s       *sorter
+}
+
+func (h *mergeHeap) Len() int { return len(h.entries) }
+
+func (h *mergeHeap) Less(i, j int) bool {
+	if c := h.s.compare(h.entries[i].item, h.entries[j].item); c != 0 {
+		return c < 0
+	}
+	return h.entries[i].run < h.entries[j].run
+}
+
+func (h *mergeHeap) Swap(i, j int) { h.entries[i], h.entries[j] = h.entries[j], h.entries[i] }
+
+func (h *mergeHeap) Push(x any) { h.entries = append(h.entries, x.(mergeEntry)) } //nolint:errcheck // heap only stores mergeEntry
+
+func (h *mergeHeap) Pop() any {
+	last := h.entries[len(h.entries)-1]
+	h.entries = h.entries[:len(h.entries)-1]
+	return last
+}
+
+// merge performs a k-way merge of the spilled runs and the final in-memory chunk
+// (which holds the last input records), passing each record to emit in order.
+func (s *sorter) merge(runs []string, chunk []sortItem, emit func([]*csvpp.Field) error) (retErr error) {
+	readers := make([]*runReader, len(runs))
+	defer func() {
+		for _, r := range readers {
+			if r == nil {
+				continue
+			}
+			if cerr := r.f.Close(); cerr != nil && retErr == nil {
+				retErr = cerr
+			}
+		}
+	}()
+
+	h := &mergeHeap{s: s}
+	for i, name := range runs {
+		f, err := os.Open(name) //nolint:gosec // run files are created by writeRun
+		if err != nil {
+			return err
+		}
+		readers[i] = &runReader{f: f, dec: gob.NewDecoder(bufio.NewReader(f))}
+		record, err := readers[i].next()
+		if err != nil {
+			return fmt.Errorf("csvpputil: failed to read sort run: %w", err)
+		}
+		h.entries = append(h.entries, mergeEntry{item: s.item(record), run: i})
+	}
+	memRun := len(runs)
+	if len(chunk) > 0 {
+		h.entries = append(h.entries, mergeEntry{item: chunk[0], run: memRun})
+		chunk = chunk[1:]
+	}
+	heap.Init(h)
+
+	for h.Len() > 0 {
+		top := h.entries[0]
+		if err := emit(top.item.record); err != nil {
+			return err
+		}
+
+		var (
+			next sortItem
+			more bool
+		)
+		if top.run == memRun {
+			if len(chunk) > 0 {
+				next, more = chunk[0], true
+				chunk = chunk[1:]
+			}
+		} else {
+			record, err := readers[top.run].next()
+			switch {
+			case errors.Is(err, io.EOF):
+			case err != nil:
+				return fmt.Errorf("csvpputil: failed to read sort run: %w", err)
+			default:
+				next, more = s.item(record), true
+			}
+		}
+
+		if more {
+			h.entries[0].item = next
+			heap.Fix(h, 0)
+		} else {
+			heap.Pop(h)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,254 @@
+package csvpputil_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/osamingo/go-csvpp"
+	"github.com/osamingo/go-csvpp/csvpputil"
+)
+
+const sortTestInput = `name,age,tags[],geo(lat^lon)
+Alice,30,go~rust,35.68^139.76
+Bob,25,,34.05^-118.24
+Carol,41,python,x^0
+Dave,30,go~rust~zig,51.51^-0.13
+`
+
+func TestParseSortKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		input   string
+		want    csvpputil.SortKey
+		wantErr bool
+	}{
+		{name: "success: path only", input: "name", want: csvpputil.SortKey{Path: "name"}},
+		{name: "success: numeric descending", input: "geo.lat:num:desc", want: csvpputil.SortKey{Path: "geo.lat", Numeric: true, Desc: true}},
+		{name: "success: element count", input: "tags:len", want: csvpputil.SortKey{Path: "tags", Count: true}},
+		{name: "success: explicit ascending", input: "address[0].city:ASC", want: csvpputil.SortKey{Path: "address[0].city"}},
+		{name: "error: empty path", input: ":desc", wantErr: true},
+		{name: "error: unknown modifier", input: "name:reverse", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := csvpputil.ParseSortKey(tt.input)
+			if tt.wantErr {
+				if !errors.Is(err, csvpputil.ErrInvalidSortKey) {
+					t.Errorf("ParseSortKey() error = %v, want %v", err, csvpputil.ErrInvalidSortKey)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseSortKey() unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ParseSortKey() mismatch (-want +got):\n%s", diff)
+			}
+			if got.String() != strings.ToLower(strings.ReplaceAll(tt.input, ":ASC", "")) {
+				t.Errorf("String() = %q, want round trip of %q", got.String(), tt.input)
+			}
+		})
+	}
+}
+
+// sortedNames sorts sortTestInput with keys and returns the names in output order.
+func sortedNames(t *testing.T, keys []csvpputil.SortKey, opts ...csvpputil.SortOption) ([]string, error) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := csvpputil.Sort(csvpp.NewWriter(&buf), csvpp.NewReader(strings.NewReader(sortTestInput)), keys, opts...); err != nil {
+		return nil, err
+	}
+
+	reader := csvpp.NewReader(&buf)
+	records, err := reader.ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	names := make([]string, len(records))
+	for i, r := range records {
+		names[i] = r[0].Value
+	}
+	return names, nil
+}
+
+func TestSort(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		keys []string
+		want []string
+	}{
+		{name: "success: lexical", keys: []string{"name:desc"}, want: []string{"Dave", "Carol", "Bob", "Alice"}},
+		{name: "success: numeric is stable", keys: []string{"age:num"}, want: []string{"Bob", "Alice", "Dave", "Carol"}},
+		{name: "success: multiple keys", keys: []string{"age:num:desc", "name:desc"}, want: []string{"Carol", "Dave", "Alice", "Bob"}},
+		{name: "success: component path, non-numeric last", keys: []string{"geo.lat:num:desc"}, want: []string{"Dave", "Alice", "Bob", "Carol"}},
+		{name: "success: element count", keys: []string{"tags:len:desc"}, want: []string{"Dave", "Alice", "Carol", "Bob"}},
+		{name: "success: first array element, empty last", keys: []string{"tags"}, want: []string{"Alice", "Dave", "Carol", "Bob"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			keys := make([]csvpputil.SortKey, len(tt.keys))
+			for i, k := range tt.keys {
+				key, err := csvpputil.ParseSortKey(k)
+				if err != nil {
+					t.Fatalf("ParseSortKey(%q) error = %v", k, err)
+				}
+				keys[i] = key
+			}
+
+			for _, limit := range []int{csvpputil.DefaultSortMemoryLimit, 1, 200} {
+				dir := t.TempDir()
+				got, err := sortedNames(t, keys, csvpputil.WithSortMemoryLimit(limit), csvpputil.WithSortTempDir(dir))
+				if err != nil {
+					t.Fatalf("Sort() unexpected error: %v", err)
+				}
+				if diff := cmp.Diff(tt.want, got); diff != "" {
+					t.Errorf("Sort() with memory limit %d mismatch (-want +got):\n%s", limit, diff)
+				}
+
+				entries, err := os.ReadDir(dir)
+				if err != nil {
+					t.Fatalf("ReadDir() error = %v", err)
+				}
+				if len(entries) != 0 {
+					t.Errorf("Sort() left %d temporary files", len(entries))
+				}
+			}
+		})
+	}
+}
+
+func TestSort_UnknownPath(t *testing.T) {
+	t.Parallel()
+
+	_, err := sortedNames(t, []csvpputil.SortKey{{Path: "geo.alt"}})
+	if !errors.Is(err, csvpputil.ErrInvalidSortKey) {
+		t.Errorf("Sort() error = %v, want %v", err, csvpputil.ErrInvalidSortKey)
+	}
+}
+
+func TestSort_ExternalRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	// Values containing delimiters must survive spilling to run files.
+	var in strings.Builder
+	in.WriteString("id,note,items[](k^v)\n")
+	for i := 999; i >= 0; i-- {
+		fmt.Fprintf(&in, "%d,\"a,b \"\"%d\"\"\",k%d^v%d~k^v\n", i, i, i, i)
+	}
+
+	var sorted, external bytes.Buffer
+	keys := []csvpputil.SortKey{{Path: "id", Numeric: true}}
+	if err := csvpputil.Sort(csvpp.NewWriter(&sorted), csvpp.NewReader(strings.NewReader(in.String())), keys); err != nil {
+		t.Fatalf("Sort() error = %v", err)
+	}
+	err := csvpputil.Sort(csvpp.NewWriter(&external), csvpp.NewReader(strings.NewReader(in.String())), keys,
+		csvpputil.WithSortMemoryLimit(1024), csvpputil.WithSortTempDir(t.TempDir()))
+	if err != nil {
+		t.Fatalf("Sort() external error = %v", err)
+	}
+
+	if diff := cmp.Diff(sorted.String(), external.String()); diff != "" {
+		t.Errorf("external sort mismatch (-in-memory +external):\n%s", diff)
+	}
+	if !strings.HasPrefix(sorted.String(), "id,note,items[](k^v)\n0,") {
+		t.Errorf("Sort() output starts with %q", sorted.String()[:40])
+	}
+}
+
+func TestSort_ManyRuns(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		limit int
+	}{
+		{name: "success: one run per record, merged in several passes", limit: 1},
+		{name: "success: zero limit uses the default", limit: 0},
+		{name: "success: negative limit uses the default", limit: -1},
+	}
+
+	var in strings.Builder
+	in.WriteString("id,name\n")
+	for i := 299; i >= 0; i-- {
+		fmt.Fprintf(&in, "%d,n%d\n", i%100, i)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			var out bytes.Buffer
+			keys := []csvpputil.SortKey{{Path: "id", Numeric: true}}
+			err := csvpputil.Sort(csvpp.NewWriter(&out), csvpp.NewReader(strings.NewReader(in.String())), keys,
+				csvpputil.WithSortMemoryLimit(tt.limit), csvpputil.WithSortTempDir(dir))
+			if err != nil {
+				t.Fatalf("Sort() error = %v", err)
+			}
+
+			lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+			if len(lines) != 301 {
+				t.Fatalf("Sort() wrote %d lines, want 301", len(lines))
+			}
+			// Equal ids keep their input order: n299 and n199 before n99 for id 99.
+			if diff := cmp.Diff([]string{"0,n200", "0,n100", "0,n0"}, lines[1:4]); diff != "" {
+				t.Errorf("first records mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff([]string{"99,n299", "99,n199", "99,n99"}, lines[298:]); diff != "" {
+				t.Errorf("last records mismatch (-want +got):\n%s", diff)
+			}
+
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != 0 {
+				t.Errorf("Sort() left %d files in the temporary directory", len(entries))
+			}
+		})
+	}
+}
+
+func TestSortRecords(t *testing.T) {
+	t.Parallel()
+
+	headers := []*csvpp.ColumnHeader{
+		{Name: "name", Kind: csvpp.SimpleField},
+		{Name: "score", Kind: csvpp.SimpleField},
+	}
+	records := [][]*csvpp.Field{
+		{{Value: "a"}, {Value: "10"}},
+		{{Value: "b"}, {Value: "9"}},
+		{{Value: "c"}, {Value: "10"}},
+	}
+
+	if err := csvpputil.SortRecords(headers, records, []csvpputil.SortKey{{Path: "score", Numeric: true, Desc: true}}); err != nil {
+		t.Fatalf("SortRecords() error = %v", err)
+	}
+
+	got := []string{records[0][0].Value, records[1][0].Value, records[2][0].Value}
+	if diff := cmp.Diff([]string{"a", "c", "b"}, got); diff != "" {
+		t.Errorf("SortRecords() mismatch (-want +got):\n%s", diff)
+	}
+
+	if err := csvpputil.SortRecords(headers, records, []csvpputil.SortKey{{Path: "missing"}}); !errors.Is(err, csvpputil.ErrInvalidSortKey) {
+		t.Errorf("SortRecords() error = %v, want %v", err, csvpputil.ErrInvalidSortKey)
+	}
+}
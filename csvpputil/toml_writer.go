@@ -0,0 +1,171 @@
+package csvpputil
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/osamingo/go-csvpp"
+)
+
+// DefaultTOMLTableName is the default name of the array of tables written by TOMLWriter.
+const DefaultTOMLTableName = "records"
+
+// TOMLWriterOption is a functional option for TOMLWriter.
+type TOMLWriterOption func(*TOMLWriter)
+
+// WithTOMLTableName sets the name of the array of tables. The default is DefaultTOMLTableName.
+func WithTOMLTableName(name string) TOMLWriterOption {
+	return func(w *TOMLWriter) {
+		w.table = name
+	}
+}
+
+// TOMLWriter writes CSV++ records as a TOML array of tables, one [[records]]
+// table per record. Structured fields are written as inline tables and
+// array-structured fields as arrays of inline tables. Each record is written
+// as soon as Write is called.
+type TOMLWriter struct {
+	w       io.Writer
+	headers []*csvpp.ColumnHeader
+	table   string
+	started bool
+	closed  bool
+}
+
+// NewTOMLWriter creates a new TOMLWriter that writes to w.
+func NewTOMLWriter(w io.Writer, headers []*csvpp.ColumnHeader, opts ...TOMLWriterOption) *TOMLWriter {
+	writer := &TOMLWriter{
+		w:       w,
+		headers: headers,
+		table:   DefaultTOMLTableName,
+	}
+	for _, opt := range opts {
+		opt(writer)
+	}
+	return writer
+}
+
+// Write writes a single record as a TOML table.
+func (w *TOMLWriter) Write(record []*csvpp.Field) error {
+	if w.closed {
+		return io.ErrClosedPipe
+	}
+
+	var sb strings.Builder
+	if w.started {
+		sb.WriteByte('\n')
+	}
+	w.started = true
+
+	sb.WriteString("[[" + tomlKey(w.table) + "]]\n")
+	n := min(len(w.headers), len(record))
+	for i := range n {
+		sb.WriteString(tomlKey(w.headers[i].Name) + " = ")
+		writeTOMLValue(&sb, w.headers[i], record[i])
+		sb.WriteByte('\n')
+	}
+
+	_, err := io.WriteString(w.w, sb.String())
+	return err
+}
+
+// Close marks the writer as closed. Records are already written by Write.
+func (w *TOMLWriter) Close() error {
+	w.closed = true
+	return nil
+}
+
+// writeTOMLValue writes a single field value.
+func writeTOMLValue(sb *strings.Builder, header *csvpp.ColumnHeader, field *csvpp.Field) {
+	if field == nil {
+		field = &csvpp.Field{}
+	}
+
+	switch header.Kind {
+	case csvpp.ArrayField:
+		sb.WriteByte('[')
+		for i, v := range field.Values {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString(tomlString(v))
+		}
+		sb.WriteByte(']')
+
+	case csvpp.StructuredField:
+		writeTOMLInlineTable(sb, header.Components, field.Components)
+
+	case csvpp.ArrayStructuredField:
+		sb.WriteByte('[')
+		first := true
+		for _, comp := range field.Components {
+			if comp == nil {
+				continue
+			}
+			if !first {
+				sb.WriteString(", ")
+			}
+			first = false
+			writeTOMLInlineTable(sb, header.Components, comp.Components)
+		}
+		sb.WriteByte(']')
+
+	default:
+		sb.WriteString(tomlString(field.Value))
+	}
+}
+
+// writeTOMLInlineTable writes fields as a TOML inline table.
+func writeTOMLInlineTable(sb *strings.Builder, headers []*csvpp.ColumnHeader, fields []*csvpp.Field) {
+	sb.WriteByte('{')
+	n := min(len(headers), len(fields))
+	for i := range n {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(" " + tomlKey(headers[i].Name) + " = ")
+		writeTOMLValue(sb, headers[i], fields[i])
+	}
+	if n > 0 {
+		sb.WriteByte(' ')
+	}
+	sb.WriteByte('}')
+}
+
+// tomlKey returns name as a bare key if possible, or as a quoted key.
+// TOML bare keys allow the same characters as CSV++ names.
+func tomlKey(name string) string {
+	if csvpp.IsValidName(name) {
+		return name
+	}
+	return tomlString(name)
+}
+
+// tomlString returns s as a TOML basic string.
+func tomlString(s string) string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			sb.WriteString(`\"`)
+		case '\\':
+			sb.WriteString(`\\`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case '\t':
+			sb.WriteString(`\t`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&sb, `\u%04X`, r)
+				continue
+			}
+			sb.WriteRune(r)
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
@@ -0,0 +1,117 @@
+package csvpputil_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/osamingo/go-csvpp"
+	"github.com/osamingo/go-csvpp/csvpputil"
+)
+
+func TestTOMLWriter(t *testing.T) {
+	t.Parallel()
+
+	headers := []*csvpp.ColumnHeader{
+		{Name: "name", Kind: csvpp.SimpleField},
+		{Name: "tags", Kind: csvpp.ArrayField},
+		{Name: "geo", Kind: csvpp.StructuredField, Components: []*csvpp.ColumnHeader{
+			{Name: "lat", Kind: csvpp.SimpleField},
+			{Name: "lon", Kind: csvpp.SimpleField},
+		}},
+		{Name: "address", Kind: csvpp.ArrayStructuredField, Components: []*csvpp.ColumnHeader{
+			{Name: "city", Kind: csvpp.SimpleField},
+		}},
+	}
+
+	tests := []struct {
+		name    string
+		opts    []csvpputil.TOMLWriterOption
+		records [][]*csvpp.Field
+		want    string
+	}{
+		{
+			name: "success: array of tables",
+			records: [][]*csvpp.Field{
+				{
+					{Value: "Alice"},
+					{Values: []string{"go", "rust"}},
+					{Components: []*csvpp.Field{{Value: "35.6"}, {Value: "139.7"}}},
+					{Components: []*csvpp.Field{{Components: []*csvpp.Field{{Value: "Tokyo"}}}, {Components: []*csvpp.Field{{Value: "Osaka"}}}}},
+				},
+				{{Value: "Bob"}, {}, {Components: []*csvpp.Field{{}, {}}}, {}},
+			},
+			want: `[[records]]
+name = "Alice"
+tags = ["go", "rust"]
+geo = { lat = "35.6", lon = "139.7" }
+address = [{ city = "Tokyo" }, { city = "Osaka" }]
+
+[[records]]
+name = "Bob"
+tags = []
+geo = { lat = "", lon = "" }
+address = []
+`,
+		},
+		{
+			name: "success: escapes strings",
+			records: [][]*csvpp.Field{
+				{{Value: "say \"hi\"\\\n\x01"}, {}, {}, {}},
+			},
+			want: `[[records]]
+name = "say \"hi\"\\\n\u0001"
+tags = []
+geo = {}
+address = []
+`,
+		},
+		{
+			name:    "success: custom table name",
+			opts:    []csvpputil.TOMLWriterOption{csvpputil.WithTOMLTableName("people list")},
+			records: [][]*csvpp.Field{{{Value: "Alice"}}},
+			want:    "[[\"people list\"]]\nname = \"Alice\"\n",
+		},
+		{
+			name: "success: no records",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			w := csvpputil.NewTOMLWriter(&buf, headers, tt.opts...)
+			for _, record := range tt.records {
+				if err := w.Write(record); err != nil {
+					t.Fatalf("Write() error = %v", err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, buf.String()); diff != "" {
+				t.Errorf("output mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestTOMLWriter_WriteAfterClose(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	w := csvpputil.NewTOMLWriter(&buf, []*csvpp.ColumnHeader{{Name: "name", Kind: csvpp.SimpleField}})
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := w.Write([]*csvpp.Field{{Value: "Alice"}}); !errors.Is(err, io.ErrClosedPipe) {
+		t.Errorf("Write() after Close() error = %v, want %v", err, io.ErrClosedPipe)
+	}
+}
@@ -0,0 +1,234 @@
+package csvpputil
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/osamingo/go-csvpp"
+)
+
+// ValueType controls how a simple CSV++ value is written in JSON and YAML output.
+type ValueType int
+
+const (
+	// TypeString writes the value as a string. This is the default.
+	TypeString ValueType = iota
+	// TypeAuto writes numbers as numbers, "true" and "false" as booleans and
+	// empty values as null, and everything else as a string.
+	TypeAuto
+	// TypeNumber writes the value as a number, or null if it is empty.
+	// Values that are not numbers are written as strings.
+	TypeNumber
+	// TypeBoolean writes "true" and "false" as booleans, or null if the value is
+	// empty. Other values are written as strings.
+	TypeBoolean
+)
+
+// String returns the name of the type as accepted by ParseValueType.
+func (t ValueType) String() string {
+	switch t {
+	case TypeString:
+		return "string"
+	case TypeAuto:
+		return "auto"
+	case TypeNumber:
+		return "number"
+	case TypeBoolean:
+		return "boolean"
+	default:
+		return fmt.Sprintf("ValueType(%d)", int(t))
+	}
+}
+
+// ParseValueType parses a type name: "string", "auto", "number" or "boolean"
+// ("bool" is also accepted).
+func ParseValueType(s string) (ValueType, error) {
+	switch strings.ToLower(s) {
+	case "string":
+		return TypeString, nil
+	case "auto":
+		return TypeAuto, nil
+	case "number":
+		return TypeNumber, nil
+	case "boolean", "bool":
+		return TypeBoolean, nil
+	default:
+		return TypeString, fmt.Errorf("unknown value type %q (must be string, auto, number or boolean)", s)
+	}
+}
+
+// ValueTypes describes how simple values are typed in JSON and YAML output.
+//
+// Paths maps field paths to types: "age" for a simple or array field,
+// "geo.lat" for a component and "address.zip" for a component of every element
+// of an array-structured field. Values of array fields use the type of the
+// array. Fields not in Paths use Default.
+//
+// Values are only written as numbers or booleans when reading the output back
+// yields the same text, so typed output converts back to identical CSV++.
+type ValueTypes struct {
+	Default ValueType
+	Paths   map[string]ValueType
+}
+
+// InferValueTypes returns ValueTypes that infer the type of every value.
+func InferValueTypes() ValueTypes {
+	return ValueTypes{Default: TypeAuto}
+}
+
+// ValueTypesOf derives ValueTypes from the csvpp struct tags of v, a struct,
+// a pointer to a struct or a slice of either, as used with csvpp.Marshal.
+// Integer and floating-point fields become TypeNumber, bool fields TypeBoolean,
+// and other fields TypeString. Components of structured fields are matched to
+// struct fields by position, as csvpp.Unmarshal does.
+func ValueTypesOf(v any) (ValueTypes, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && (t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice) {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return ValueTypes{}, fmt.Errorf("csvpputil: ValueTypesOf needs a struct, got %T", v)
+	}
+
+	vt := ValueTypes{Paths: make(map[string]ValueType)}
+	for i := range t.NumField() {
+		f := t.Field(i)
+		tag := f.Tag.Get("csvpp")
+		if !f.IsExported() || tag == "" || tag == "-" {
+			continue
+		}
+		headers, err := csvpp.NewReader(strings.NewReader(tag)).Headers()
+		if err != nil || len(headers) != 1 {
+			return ValueTypes{}, fmt.Errorf("csvpputil: invalid csvpp tag %q on field %s", tag, f.Name)
+		}
+		addStructValueTypes(vt.Paths, headers[0].Name, headers[0], f.Type)
+	}
+	return vt, nil
+}
+
+// addStructValueTypes records the value types of a struct field described by h at path.
+func addStructValueTypes(paths map[string]ValueType, path string, h *csvpp.ColumnHeader, t reflect.Type) {
+	t = derefType(t)
+	switch h.Kind {
+	case csvpp.ArrayField:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			paths[path] = goValueType(derefType(t.Elem()))
+		}
+	case csvpp.StructuredField, csvpp.ArrayStructuredField:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			t = derefType(t.Elem())
+		}
+		if t.Kind() != reflect.Struct {
+			return
+		}
+		for i, c := range h.Components {
+			if i >= t.NumField() {
+				break
+			}
+			addStructValueTypes(paths, path+"."+c.Name, c, t.Field(i).Type)
+		}
+	default:
+		paths[path] = goValueType(t)
+	}
+}
+
+// derefType returns the element type of pointer types.
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+// goValueType returns the ValueType for values of a Go type.
+func goValueType(t reflect.Type) ValueType {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return TypeNumber
+	case reflect.Bool:
+		return TypeBoolean
+	default:
+		return TypeString
+	}
+}
+
+// valueKind is the kind of JSON/YAML value a simple value is written as.
+type valueKind int
+
+const (
+	kindString valueKind = iota
+	kindNumber
+	kindBoolean
+	kindNull
+)
+
+// kind returns how value s at path is written. isNumber reports whether s can
+// be written as a number of the output format without changing its text.
+func (vt *ValueTypes) kind(path, s string, isNumber func(string) bool) valueKind {
+	if vt == nil {
+		return kindString
+	}
+	t, ok := vt.Paths[path]
+	if !ok {
+		t = vt.Default
+	}
+	if t == TypeString {
+		return kindString
+	}
+
+	switch {
+	case s == "":
+		return kindNull
+	case (t == TypeAuto || t == TypeNumber) && isNumber(s):
+		return kindNumber
+	case (t == TypeAuto || t == TypeBoolean) && (s == "true" || s == "false"):
+		return kindBoolean
+	default:
+		return kindString
+	}
+}
+
+// jsonNumberPattern matches the JSON number grammar (RFC 8259, Section 6).
+var jsonNumberPattern = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+
+// isJSONNumber reports whether s is a JSON number literal. Such literals are
+// written verbatim, so they read back unchanged when decoded as json.Number.
+func isJSONNumber(s string) bool {
+	return jsonNumberPattern.MatchString(s)
+}
+
+// yamlNumber returns s as an int64 or float64 if decoding the YAML encoding of
+// that value yields s again. Integers must be in canonical form and floats must
+// have a fraction and no exponent.
+func yamlNumber(s string) (any, bool) {
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return i, strconv.FormatInt(i, 10) == s
+	}
+	if !isJSONNumber(s) || !strings.Contains(s, ".") || strings.ContainsAny(s, "eE") {
+		return nil, false
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || strconv.FormatFloat(f, 'g', -1, 64) != s {
+		return nil, false
+	}
+	return f, true
+}
+
+// isYAMLNumber reports whether yamlNumber accepts s.
+func isYAMLNumber(s string) bool {
+	_, ok := yamlNumber(s)
+	return ok
+}
+
+// joinPath appends name to the dotted field path prefix.
+func joinPath(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
@@ -0,0 +1,230 @@
+package csvpputil_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/osamingo/go-csvpp"
+	"github.com/osamingo/go-csvpp/csvpputil"
+)
+
+var typedHeaders = []*csvpp.ColumnHeader{
+	{Name: "id", Kind: csvpp.SimpleField},
+	{Name: "name", Kind: csvpp.SimpleField},
+	{Name: "active", Kind: csvpp.SimpleField},
+	{Name: "scores", Kind: csvpp.ArrayField},
+	{Name: "geo", Kind: csvpp.StructuredField, Components: []*csvpp.ColumnHeader{
+		{Name: "lat", Kind: csvpp.SimpleField},
+		{Name: "lon", Kind: csvpp.SimpleField},
+	}},
+	{Name: "address", Kind: csvpp.ArrayStructuredField, Components: []*csvpp.ColumnHeader{
+		{Name: "zip", Kind: csvpp.SimpleField},
+	}},
+}
+
+var typedRecords = [][]*csvpp.Field{
+	{
+		{Value: "1"},
+		{Value: "Alice"},
+		{Value: "true"},
+		{Values: []string{"1.5", "n/a", "1e3"}},
+		{Components: []*csvpp.Field{{Value: "35.60"}, {Value: ""}}},
+		{Components: []*csvpp.Field{{Components: []*csvpp.Field{{Value: "0123"}}}, {Components: []*csvpp.Field{{Value: "100"}}}}},
+	},
+}
+
+func TestJSONArrayWriter_ValueTypes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		vt   csvpputil.ValueTypes
+		want string
+	}{
+		{
+			name: "success: infer",
+			vt:   csvpputil.InferValueTypes(),
+			want: `[{"id":1,"name":"Alice","active":true,"scores":[1.5,"n/a",1e3],"geo":{"lat":35.60,"lon":null},"address":[{"zip":"0123"},{"zip":100}]}]` + "\n",
+		},
+		{
+			name: "success: paths",
+			vt: csvpputil.ValueTypes{Paths: map[string]csvpputil.ValueType{
+				"id":          csvpputil.TypeNumber,
+				"active":      csvpputil.TypeNumber,
+				"geo.lat":     csvpputil.TypeAuto,
+				"address.zip": csvpputil.TypeBoolean,
+			}},
+			want: `[{"id":1,"name":"Alice","active":"true","scores":["1.5","n/a","1e3"],"geo":{"lat":35.60,"lon":""},"address":[{"zip":"0123"},{"zip":"100"}]}]` + "\n",
+		},
+		{
+			name: "success: auto default with string override",
+			vt: csvpputil.ValueTypes{Default: csvpputil.TypeAuto, Paths: map[string]csvpputil.ValueType{
+				"address.zip": csvpputil.TypeString,
+			}},
+			want: `[{"id":1,"name":"Alice","active":true,"scores":[1.5,"n/a",1e3],"geo":{"lat":35.60,"lon":null},"address":[{"zip":"0123"},{"zip":"100"}]}]` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := csvpputil.MarshalJSON(typedHeaders, typedRecords, csvpputil.WithJSONValueTypes(tt.vt))
+			if err != nil {
+				t.Fatalf("MarshalJSON() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.want, string(got)); diff != "" {
+				t.Errorf("MarshalJSON() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNDJSONWriter_ValueTypes(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	w := csvpputil.NewNDJSONWriter(&buf, typedHeaders, csvpputil.WithNDJSONValueTypes(csvpputil.InferValueTypes()))
+	for _, record := range typedRecords {
+		if err := w.Write(record); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	want := `{"id":1,"name":"Alice","active":true,"scores":[1.5,"n/a",1e3],"geo":{"lat":35.60,"lon":null},"address":[{"zip":"0123"},{"zip":100}]}` + "\n"
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("output mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestMarshalYAML_ValueTypes(t *testing.T) {
+	t.Parallel()
+
+	// YAML numbers are only written when they read back as the same text,
+	// so "35.60" and "1e3" stay strings.
+	want := `- id: 1
+  name: Alice
+  active: true
+  scores:
+  - 1.5
+  - n/a
+  - 1e3
+  geo:
+    lat: "35.60"
+    lon: null
+  address:
+  - zip: "0123"
+  - zip: 100
+`
+	got, err := csvpputil.MarshalYAML(typedHeaders, typedRecords, csvpputil.WithYAMLValueTypes(csvpputil.InferValueTypes()))
+	if err != nil {
+		t.Fatalf("MarshalYAML() error = %v", err)
+	}
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("MarshalYAML() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestValueTypesOf(t *testing.T) {
+	t.Parallel()
+
+	type location struct {
+		Lat  float64
+		Lon  float64
+		Name string
+	}
+	type address struct {
+		Zip     string
+		Primary bool
+	}
+	type person struct {
+		ID        int        `csvpp:"id"`
+		Name      string     `csvpp:"name"`
+		Active    *bool      `csvpp:"active"`
+		Scores    []float32  `csvpp:"scores[]"`
+		Geo       location   `csvpp:"geo(lat^lon^name)"`
+		Addresses []*address `csvpp:"address[](zip^primary)"`
+		Ignored   int        `csvpp:"-"`
+	}
+
+	tests := []struct {
+		name    string
+		v       any
+		want    csvpputil.ValueTypes
+		wantErr bool
+	}{
+		{
+			name: "success: struct tags",
+			v:    []person{},
+			want: csvpputil.ValueTypes{Paths: map[string]csvpputil.ValueType{
+				"id":              csvpputil.TypeNumber,
+				"name":            csvpputil.TypeString,
+				"active":          csvpputil.TypeBoolean,
+				"scores":          csvpputil.TypeNumber,
+				"geo.lat":         csvpputil.TypeNumber,
+				"geo.lon":         csvpputil.TypeNumber,
+				"geo.name":        csvpputil.TypeString,
+				"address.zip":     csvpputil.TypeString,
+				"address.primary": csvpputil.TypeBoolean,
+			}},
+		},
+		{
+			name:    "error: not a struct",
+			v:       []int{},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := csvpputil.ValueTypesOf(tt.v)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValueTypesOf() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ValueTypesOf() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseValueType(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		s       string
+		want    csvpputil.ValueType
+		wantErr bool
+	}{
+		{name: "success: string", s: "string", want: csvpputil.TypeString},
+		{name: "success: auto", s: "auto", want: csvpputil.TypeAuto},
+		{name: "success: number", s: "Number", want: csvpputil.TypeNumber},
+		{name: "success: bool", s: "bool", want: csvpputil.TypeBoolean},
+		{name: "error: unknown", s: "date", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := csvpputil.ParseValueType(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseValueType() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseValueType() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
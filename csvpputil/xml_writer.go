@@ -0,0 +1,215 @@
+package csvpputil
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+
+	"github.com/osamingo/go-csvpp"
+)
+
+// Default element names used by XMLWriter.
+const (
+	DefaultXMLRootName   = "records"
+	DefaultXMLRecordName = "record"
+	DefaultXMLItemName   = "item"
+)
+
+// xmlFieldName is the element written for a field whose name is not a valid
+// XML name, such as "1st"; the name is kept in its name attribute.
+const xmlFieldName = "field"
+
+// XMLWriterOption is a functional option for XMLWriter.
+type XMLWriterOption func(*XMLWriter)
+
+// WithXMLRootName sets the name of the root element. The default is DefaultXMLRootName.
+func WithXMLRootName(name string) XMLWriterOption {
+	return func(w *XMLWriter) {
+		w.root = name
+	}
+}
+
+// WithXMLRecordName sets the name of the element written for each record.
+// The default is DefaultXMLRecordName.
+func WithXMLRecordName(name string) XMLWriterOption {
+	return func(w *XMLWriter) {
+		w.record = name
+	}
+}
+
+// XMLWriter writes CSV++ records as XML, one element per record:
+//
+//	<records>
+//	  <record>
+//	    <name>Alice</name>
+//	    <tags><item>go</item><item>rust</item></tags>
+//	    <geo><lat>35.6</lat><lon>139.7</lon></geo>
+//	    <address><item><city>Tokyo</city></item></address>
+//	  </record>
+//	</records>
+//
+// Structured fields become nested component elements, and the elements of
+// array and array-structured fields are wrapped in <item> elements. A field
+// whose name is not a valid XML name, such as "1st", is written as
+// <field name="1st">. Each record is written as soon as Write is called.
+type XMLWriter struct {
+	enc     *xml.Encoder
+	w       io.Writer
+	headers []*csvpp.ColumnHeader
+	root    string
+	record  string
+	started bool
+	closed  bool
+}
+
+// NewXMLWriter creates a new XMLWriter that writes to w.
+func NewXMLWriter(w io.Writer, headers []*csvpp.ColumnHeader, opts ...XMLWriterOption) *XMLWriter {
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	writer := &XMLWriter{
+		enc:     enc,
+		w:       w,
+		headers: headers,
+		root:    DefaultXMLRootName,
+		record:  DefaultXMLRecordName,
+	}
+	for _, opt := range opts {
+		opt(writer)
+	}
+	return writer
+}
+
+// Write writes a single record element.
+// The first call writes the XML declaration and the opening root element.
+func (w *XMLWriter) Write(record []*csvpp.Field) error {
+	if w.closed {
+		return io.ErrClosedPipe
+	}
+	if err := w.start(); err != nil {
+		return err
+	}
+
+	if err := w.writeElements(xml.StartElement{Name: xml.Name{Local: w.record}}, w.headers, record); err != nil {
+		return err
+	}
+	return w.enc.Flush()
+}
+
+// Close writes the closing root element.
+func (w *XMLWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	if err := w.start(); err != nil {
+		return err
+	}
+	if err := w.enc.EncodeToken(xml.EndElement{Name: xml.Name{Local: w.root}}); err != nil {
+		return err
+	}
+	if err := w.enc.Flush(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w.w, "\n")
+	return err
+}
+
+// start writes the XML declaration and the opening root element once.
+func (w *XMLWriter) start() error {
+	if w.started {
+		return nil
+	}
+	w.started = true
+
+	if _, err := io.WriteString(w.w, xml.Header); err != nil {
+		return err
+	}
+	return w.enc.EncodeToken(xml.StartElement{Name: xml.Name{Local: w.root}})
+}
+
+// writeElements writes the element start containing one child element per field.
+func (w *XMLWriter) writeElements(start xml.StartElement, headers []*csvpp.ColumnHeader, fields []*csvpp.Field) error {
+	if err := w.enc.EncodeToken(start); err != nil {
+		return err
+	}
+	n := min(len(headers), len(fields))
+	for i := range n {
+		if err := w.writeValue(headers[i], fields[i]); err != nil {
+			return err
+		}
+	}
+	return w.enc.EncodeToken(start.End())
+}
+
+// writeValue writes a single field as an element named after its header.
+func (w *XMLWriter) writeValue(header *csvpp.ColumnHeader, field *csvpp.Field) error {
+	if field == nil {
+		field = &csvpp.Field{}
+	}
+
+	switch header.Kind {
+	case csvpp.ArrayField:
+		start := fieldStart(header.Name)
+		if err := w.enc.EncodeToken(start); err != nil {
+			return err
+		}
+		for _, v := range field.Values {
+			if err := w.enc.EncodeElement(v, xml.StartElement{Name: xml.Name{Local: DefaultXMLItemName}}); err != nil {
+				return err
+			}
+		}
+		return w.enc.EncodeToken(start.End())
+
+	case csvpp.StructuredField:
+		return w.writeElements(fieldStart(header.Name), header.Components, field.Components)
+
+	case csvpp.ArrayStructuredField:
+		start := fieldStart(header.Name)
+		if err := w.enc.EncodeToken(start); err != nil {
+			return err
+		}
+		for _, comp := range field.Components {
+			if comp == nil {
+				continue
+			}
+			if err := w.writeElements(xml.StartElement{Name: xml.Name{Local: DefaultXMLItemName}}, header.Components, comp.Components); err != nil {
+				return err
+			}
+		}
+		return w.enc.EncodeToken(start.End())
+
+	default:
+		return w.enc.EncodeElement(field.Value, fieldStart(header.Name))
+	}
+}
+
+// fieldStart returns the start of the element written for a field named name:
+// <name>, or <field name="..."> if name is not a valid XML name.
+func fieldStart(name string) xml.StartElement {
+	if isXMLName(name) {
+		return xml.StartElement{Name: xml.Name{Local: name}}
+	}
+	return xml.StartElement{
+		Name: xml.Name{Local: xmlFieldName},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "name"}, Value: name}},
+	}
+}
+
+// isXMLName reports whether name can be used as an XML element name. Only
+// ASCII names are accepted: a letter or "_" followed by letters, digits,
+// "_", "-" or ".". Names beginning with "xml" are reserved by XML.
+func isXMLName(name string) bool {
+	if name == "" || len(name) >= 3 && strings.EqualFold(name[:3], "xml") {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_':
+		case i > 0 && (r >= '0' && r <= '9' || r == '-' || r == '.'):
+		default:
+			return false
+		}
+	}
+	return true
+}
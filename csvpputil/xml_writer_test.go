@@ -0,0 +1,176 @@
+package csvpputil_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/osamingo/go-csvpp"
+	"github.com/osamingo/go-csvpp/csvpputil"
+)
+
+func TestXMLWriter(t *testing.T) {
+	t.Parallel()
+
+	headers := []*csvpp.ColumnHeader{
+		{Name: "name", Kind: csvpp.SimpleField},
+		{Name: "tags", Kind: csvpp.ArrayField},
+		{Name: "geo", Kind: csvpp.StructuredField, Components: []*csvpp.ColumnHeader{
+			{Name: "lat", Kind: csvpp.SimpleField},
+			{Name: "lon", Kind: csvpp.SimpleField},
+		}},
+		{Name: "address", Kind: csvpp.ArrayStructuredField, Components: []*csvpp.ColumnHeader{
+			{Name: "city", Kind: csvpp.SimpleField},
+		}},
+	}
+
+	tests := []struct {
+		name    string
+		opts    []csvpputil.XMLWriterOption
+		records [][]*csvpp.Field
+		want    string
+	}{
+		{
+			name: "success: element per record",
+			records: [][]*csvpp.Field{
+				{
+					{Value: "Alice & Bob"},
+					{Values: []string{"go", "rust"}},
+					{Components: []*csvpp.Field{{Value: "35.6"}, {Value: "139.7"}}},
+					{Components: []*csvpp.Field{{Components: []*csvpp.Field{{Value: "Tokyo"}}}}},
+				},
+				{{Value: "Carol"}, {}, {Components: []*csvpp.Field{{}, {}}}, {}},
+			},
+			want: `<?xml version="1.0" encoding="UTF-8"?>
+<records>
+  <record>
+    <name>Alice &amp; Bob</name>
+    <tags>
+      <item>go</item>
+      <item>rust</item>
+    </tags>
+    <geo>
+      <lat>35.6</lat>
+      <lon>139.7</lon>
+    </geo>
+    <address>
+      <item>
+        <city>Tokyo</city>
+      </item>
+    </address>
+  </record>
+  <record>
+    <name>Carol</name>
+    <tags></tags>
+    <geo>
+      <lat></lat>
+      <lon></lon>
+    </geo>
+    <address></address>
+  </record>
+</records>
+`,
+		},
+		{
+			name: "success: custom element names",
+			opts: []csvpputil.XMLWriterOption{
+				csvpputil.WithXMLRootName("people"),
+				csvpputil.WithXMLRecordName("person"),
+			},
+			records: [][]*csvpp.Field{{{Value: "Alice"}}},
+			want: `<?xml version="1.0" encoding="UTF-8"?>
+<people>
+  <person>
+    <name>Alice</name>
+  </person>
+</people>
+`,
+		},
+		{
+			name: "success: no records",
+			want: `<?xml version="1.0" encoding="UTF-8"?>
+<records></records>
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			w := csvpputil.NewXMLWriter(&buf, headers, tt.opts...)
+			for _, record := range tt.records {
+				if err := w.Write(record); err != nil {
+					t.Fatalf("Write() error = %v", err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, buf.String()); diff != "" {
+				t.Errorf("output mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestXMLWriter_FieldNames(t *testing.T) {
+	t.Parallel()
+
+	headers := []*csvpp.ColumnHeader{
+		{Name: "1st", Kind: csvpp.SimpleField},
+		{Name: "-rank", Kind: csvpp.ArrayField},
+		{Name: "xmlns", Kind: csvpp.StructuredField, Components: []*csvpp.ColumnHeader{
+			{Name: "2nd", Kind: csvpp.SimpleField},
+		}},
+	}
+	records := [][]*csvpp.Field{
+		{{Value: "gold"}, {Values: []string{"1"}}, {Components: []*csvpp.Field{{Value: "silver"}}}},
+	}
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<records>
+  <record>
+    <field name="1st">gold</field>
+    <field name="-rank">
+      <item>1</item>
+    </field>
+    <field name="xmlns">
+      <field name="2nd">silver</field>
+    </field>
+  </record>
+</records>
+`
+
+	var buf bytes.Buffer
+	w := csvpputil.NewXMLWriter(&buf, headers)
+	for _, record := range records {
+		if err := w.Write(record); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("output mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestXMLWriter_WriteAfterClose(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	w := csvpputil.NewXMLWriter(&buf, []*csvpp.ColumnHeader{{Name: "name", Kind: csvpp.SimpleField}})
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := w.Write([]*csvpp.Field{{Value: "Alice"}}); !errors.Is(err, io.ErrClosedPipe) {
+		t.Errorf("Write() after Close() error = %v, want %v", err, io.ErrClosedPipe)
+	}
+}
@@ -22,6 +22,14 @@ func WithYAMLCapacity(n int) YAMLArrayWriterOption {
 	}
 }
 
+// WithYAMLValueTypes writes simple values as YAML numbers, booleans and nulls
+// as described by vt instead of as strings.
+func WithYAMLValueTypes(vt ValueTypes) YAMLArrayWriterOption {
+	return func(w *YAMLArrayWriter) {
+		w.types = &vt
+	}
+}
+
 // YAMLArrayWriter writes CSV++ records as a YAML array.
 // Due to YAML's structure (go-yaml doesn't support streaming array elements),
 // records are buffered until Close.
@@ -29,6 +37,7 @@ type YAMLArrayWriter struct {
 	w       io.Writer
 	headers []*csvpp.ColumnHeader
 	records []yaml.MapSlice
+	types   *ValueTypes
 	closed  bool
 }
 
@@ -51,7 +60,7 @@ func (w *YAMLArrayWriter) Write(record []*csvpp.Field) error {
 		return io.ErrClosedPipe
 	}
 
-	m := fieldsToMapSlice(w.headers, record)
+	m := fieldsToMapSlice(w.headers, record, w.types, "")
 	w.records = append(w.records, m)
 	return nil
 }
@@ -73,9 +82,9 @@ func (w *YAMLArrayWriter) Close() error {
 
 // MarshalYAML converts CSV++ records to YAML bytes.
 // The output is a YAML array where each element is a record.
-func MarshalYAML(headers []*csvpp.ColumnHeader, records [][]*csvpp.Field) ([]byte, error) {
+func MarshalYAML(headers []*csvpp.ColumnHeader, records [][]*csvpp.Field, opts ...YAMLArrayWriterOption) ([]byte, error) {
 	var buf bytes.Buffer
-	if err := encodeYAMLRecords(&buf, headers, records); err != nil {
+	if err := encodeYAMLRecords(&buf, headers, records, opts); err != nil {
 		return nil, err
 	}
 	return buf.Bytes(), nil
@@ -83,17 +92,22 @@ func MarshalYAML(headers []*csvpp.ColumnHeader, records [][]*csvpp.Field) ([]byt
 
 // WriteYAML writes CSV++ records as a YAML array to the provided writer.
 // The output is a YAML array where each element is a record.
-func WriteYAML(w io.Writer, headers []*csvpp.ColumnHeader, records [][]*csvpp.Field) error {
-	return encodeYAMLRecords(w, headers, records)
+func WriteYAML(w io.Writer, headers []*csvpp.ColumnHeader, records [][]*csvpp.Field, opts ...YAMLArrayWriterOption) error {
+	return encodeYAMLRecords(w, headers, records, opts)
 }
 
 // encodeYAMLRecords builds the complete MapSlice array with exact allocation
 // and encodes it in one shot. This avoids the overhead of the YAMLArrayWriter's
 // per-record append growth.
-func encodeYAMLRecords(w io.Writer, headers []*csvpp.ColumnHeader, records [][]*csvpp.Field) error {
+func encodeYAMLRecords(w io.Writer, headers []*csvpp.ColumnHeader, records [][]*csvpp.Field, opts []YAMLArrayWriterOption) error {
+	var cfg YAMLArrayWriter
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
 	ms := make([]yaml.MapSlice, len(records))
 	for i, record := range records {
-		ms[i] = fieldsToMapSlice(headers, record)
+		ms[i] = fieldsToMapSlice(headers, record, cfg.types, "")
 	}
 	enc := yaml.NewEncoder(w)
 	if err := enc.Encode(ms); err != nil {
@@ -0,0 +1,168 @@
+package csvpp
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// delimiterProgression holds the delimiters assigned to nesting levels in turn,
+// an array and a component delimiter per level. It starts with the progression
+// recommended in IETF CSV++ Section 2.3.2 (~ → ^ → ; → :); the remaining
+// characters serve deeper levels and replace delimiters that occur in the data.
+var delimiterProgression = []rune{
+	DefaultArrayDelimiter, DefaultComponentDelimiter,
+	';', ':',
+	'|', '!',
+	'@', '#',
+	'$', '%',
+	'&', '*',
+	'+', '=',
+	'/', '?',
+	'<', '>',
+}
+
+// Delimiters returns the delimiter progression used by AssignDelimiters:
+// '~' and '^' for the top level, ';' and ':' for its components, then
+// '|', '!', '@', '#' and further characters.
+func Delimiters() []rune {
+	return slices.Clone(delimiterProgression)
+}
+
+// AssignDelimiters returns a copy of headers with delimiters assigned by
+// nesting level following the delimiter progression: top-level fields use '~'
+// and '^', their components ';' and ':', and so on.
+//
+// If records is non-nil, a delimiter that occurs in any value of a field,
+// including the values of its components, is replaced with the next free
+// delimiter of the progression, so that records can be written and read back
+// unchanged. A delimiter of an enclosing field is never reused. It returns an
+// error wrapping ErrNoFreeDelimiter if every candidate occurs in the data.
+func AssignDelimiters(headers []*ColumnHeader, records [][]*Field) ([]*ColumnHeader, error) {
+	out := make([]*ColumnHeader, len(headers))
+	for i, h := range headers {
+		var fields []*Field
+		for _, record := range records {
+			if i < len(record) && record[i] != nil {
+				fields = append(fields, record[i])
+			}
+		}
+		c, err := assignDelimiters(h, fields, 0, nil, h.Name)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = c
+	}
+	return out, nil
+}
+
+// assignDelimiters assigns delimiters to a copy of h at the given level.
+// fields are the values of h and active holds the delimiters of enclosing fields.
+func assignDelimiters(h *ColumnHeader, fields []*Field, level int, active []rune, path string) (*ColumnHeader, error) {
+	c := *h
+	c.ArrayDelimiter = levelDelimiter(level, 0)
+	c.ComponentDelimiter = levelDelimiter(level, 1)
+	c.Components = nil
+
+	excluded := slices.Clone(active)
+	for _, f := range fields {
+		excluded = appendFieldRunes(excluded, f)
+	}
+
+	var own []rune
+	if h.Kind == ArrayField || h.Kind == ArrayStructuredField {
+		d, ok := chooseDelimiter(2*level, excluded)
+		if !ok {
+			return nil, fmt.Errorf("%w: field %q", ErrNoFreeDelimiter, path)
+		}
+		c.ArrayDelimiter = d
+		excluded = append(excluded, d)
+		own = append(own, d)
+	}
+	if h.Kind == StructuredField || h.Kind == ArrayStructuredField {
+		d, ok := chooseDelimiter(2*level+1, excluded)
+		if !ok {
+			return nil, fmt.Errorf("%w: field %q", ErrNoFreeDelimiter, path)
+		}
+		c.ComponentDelimiter = d
+		own = append(own, d)
+	}
+
+	if len(h.Components) > 0 {
+		active = slices.Concat(active, own)
+		c.Components = make([]*ColumnHeader, len(h.Components))
+		for j, comp := range h.Components {
+			cc, err := assignDelimiters(comp, componentFields(h.Kind, fields, j), level+1, active, path+"."+comp.Name)
+			if err != nil {
+				return nil, err
+			}
+			c.Components[j] = cc
+		}
+	}
+	return &c, nil
+}
+
+// levelDelimiter returns the preferred array (k = 0) or component (k = 1)
+// delimiter of level, or the last delimiter of the progression for levels
+// beyond it.
+func levelDelimiter(level, k int) rune {
+	if i := 2*level + k; i < len(delimiterProgression) {
+		return delimiterProgression[i]
+	}
+	return delimiterProgression[len(delimiterProgression)-1]
+}
+
+// chooseDelimiter returns the first delimiter of the progression, starting at
+// index start and wrapping around, that is not excluded.
+func chooseDelimiter(start int, excluded []rune) (rune, bool) {
+	n := len(delimiterProgression)
+	for i := range n {
+		if d := delimiterProgression[(start+i)%n]; !slices.Contains(excluded, d) {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// appendFieldRunes appends the delimiters of the progression that occur in the
+// values of f and its components.
+func appendFieldRunes(runes []rune, f *Field) []rune {
+	if f == nil {
+		return runes
+	}
+	add := func(s string) {
+		for _, d := range delimiterProgression {
+			if !slices.Contains(runes, d) && strings.ContainsRune(s, d) {
+				runes = append(runes, d)
+			}
+		}
+	}
+	add(f.Value)
+	for _, v := range f.Values {
+		add(v)
+	}
+	for _, c := range f.Components {
+		runes = appendFieldRunes(runes, c)
+	}
+	return runes
+}
+
+// componentFields returns the values of component j of fields of the given kind.
+func componentFields(kind FieldKind, fields []*Field, j int) []*Field {
+	var out []*Field
+	add := func(f *Field) {
+		if f != nil && j < len(f.Components) && f.Components[j] != nil {
+			out = append(out, f.Components[j])
+		}
+	}
+	for _, f := range fields {
+		if kind == ArrayStructuredField {
+			for _, elem := range f.Components {
+				add(elem)
+			}
+			continue
+		}
+		add(f)
+	}
+	return out
+}
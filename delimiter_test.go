@@ -0,0 +1,135 @@
+package csvpp_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/osamingo/go-csvpp"
+)
+
+func TestAssignDelimiters(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		header  string
+		records [][]*csvpp.Field
+		want    string
+		wantErr error
+	}{
+		{
+			name:   "success: progression by level",
+			header: "name,tags[],geo(lat^lon),address[](street^phones[])",
+			want:   "name,tags[],geo(lat^lon),address[](street^phones[;])",
+		},
+		{
+			name:   "success: custom delimiters are replaced",
+			header: "tags[|],geo;(lat;lon)",
+			want:   "tags[],geo(lat^lon)",
+		},
+		{
+			name:   "success: third level",
+			header: "a(b;(c:(d[])))",
+			want:   "a(b:(c!(d[@])))",
+		},
+		{
+			name:   "success: delimiters in values are skipped",
+			header: "tags[],geo(lat^lon)",
+			records: [][]*csvpp.Field{
+				{{Values: []string{"a~b", "c"}}, {Components: []*csvpp.Field{{Value: "1^2"}, {Value: "3;4"}}}},
+			},
+			want: "tags[^],geo:(lat:lon)",
+		},
+		{
+			name:   "success: delimiters of enclosing fields are skipped",
+			header: "address[](street^phones[])",
+			records: [][]*csvpp.Field{
+				{{Components: []*csvpp.Field{
+					{Components: []*csvpp.Field{{Value: "Main~St"}, {Values: []string{"555"}}}},
+				}}},
+			},
+			want: "address[^];(street;phones[:])",
+		},
+		{
+			name:    "error: every delimiter occurs in the data",
+			header:  "tags[]",
+			records: [][]*csvpp.Field{{{Values: []string{string(csvpp.Delimiters())}}}},
+			wantErr: csvpp.ErrNoFreeDelimiter,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			headers, err := csvpp.NewReader(strings.NewReader(tt.header)).Headers()
+			if err != nil {
+				t.Fatalf("Headers() error = %v", err)
+			}
+
+			got, err := csvpp.AssignDelimiters(headers, tt.records)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("AssignDelimiters() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if diff := cmp.Diff(tt.want, strings.Join(formatHeaders(got), ",")); diff != "" {
+				t.Errorf("AssignDelimiters() mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.header, strings.Join(formatHeaders(headers), ",")); diff != "" {
+				t.Errorf("AssignDelimiters() modified its input (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWriter_AutoDelimiters(t *testing.T) {
+	t.Parallel()
+
+	headers := []*csvpp.ColumnHeader{
+		{Name: "name", Kind: csvpp.SimpleField},
+		{Name: "tags", Kind: csvpp.ArrayField, ArrayDelimiter: csvpp.DefaultArrayDelimiter},
+		{Name: "geo", Kind: csvpp.StructuredField, ComponentDelimiter: csvpp.DefaultComponentDelimiter, Components: []*csvpp.ColumnHeader{
+			{Name: "lat", Kind: csvpp.SimpleField},
+			{Name: "lon", Kind: csvpp.SimpleField},
+		}},
+	}
+	records := [][]*csvpp.Field{
+		{{Value: "Alice"}, {Values: []string{"a~b", "c"}}, {Components: []*csvpp.Field{{Value: "1^2"}, {Value: "3"}}}},
+	}
+
+	var buf bytes.Buffer
+	w := csvpp.NewWriter(&buf)
+	w.AutoDelimiters = true
+	w.SetHeaders(headers)
+	if err := w.WriteAll(records); err != nil {
+		t.Fatalf("WriteAll() error = %v", err)
+	}
+
+	want := "name,tags[^],geo;(lat;lon)\nAlice,a~b^c,1^2;3\n"
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("WriteAll() mismatch (-want +got):\n%s", diff)
+	}
+
+	got, err := csvpp.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if diff := cmp.Diff(records, got); diff != "" {
+		t.Errorf("round trip mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func formatHeaders(headers []*csvpp.ColumnHeader) []string {
+	parts := make([]string, len(headers))
+	for i, h := range headers {
+		parts[i] = csvpp.FormatColumnHeader(h)
+	}
+	return parts
+}
@@ -84,6 +84,23 @@
 //	    log.Fatal(err)
 //	}
 //
+// # Field Paths
+//
+// A [Record] pairs a row's fields with its headers and resolves dotted paths
+// through the header components, so nested values can be read by name:
+//
+//	rec, err := r.ReadRecord()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	street, err := rec.Get("address[1].street") // one element's component
+//	cities, err := rec.GetAll("address[].city")  // every element's component
+//	lat, err := rec.Float("geo.lat")             // typed helpers: Int, Float, Bool
+//
+// Use [ParsePath] to parse a path once and resolve it against many records
+// with [Record.GetPath] and [Record.GetAllPath].
+//
 // # Delimiter Conventions
 //
 // The IETF CSV++ specification recommends using specific delimiters for nested structures
@@ -137,8 +154,12 @@
 //   - [ErrNoHeader]: returned when attempting to read without a header row
 //   - [ErrInvalidHeader]: returned when header format is invalid
 //   - [ErrNestingTooDeep]: returned when nesting exceeds MaxNestingDepth
+//   - [ErrNoFreeDelimiter]: returned by [AssignDelimiters] when every delimiter occurs in the data
+//   - [ErrInvalidPath]: returned when a field path is malformed or selects the wrong shape
+//   - [ErrFieldNotFound]: returned when a field path does not exist
 //
 // Parse errors are wrapped in [ParseError], which provides line/column information.
+// Path resolution errors are wrapped in [PathError].
 //
 // # Constants
 //
@@ -148,6 +169,11 @@
 //   - [DefaultComponentDelimiter]: ^ (caret) for structured fields
 //   - [DefaultMaxNestingDepth]: 10 (IETF recommended limit)
 //
+// [AssignDelimiters] assigns delimiters to nested headers by level following the
+// recommended progression (~ ^ for the top level, ; : for its components, ...),
+// skipping delimiters that occur in the data. Set [Writer.AutoDelimiters] to
+// apply it in [Writer.WriteAll].
+//
 // # Specification Reference
 //
 // For the complete IETF CSV++ specification, see:
@@ -281,3 +281,31 @@ Bob;25
 	// Alice is 30
 	// Bob is 25
 }
+
+func ExampleRecord_Get() {
+	input := `name,phone[],geo(lat^lon),address[](street^city)
+Alice,555-1234~555-5678,34.0522^-118.2437,123 Main^LA~456 Oak^NY
+`
+
+	reader := csvpp.NewReader(strings.NewReader(input))
+	record, err := reader.ReadRecord()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	phone, _ := record.Get("phone[0]")
+	city, _ := record.Get("address[1].city")
+	lat, _ := record.Float("geo.lat")
+	streets, _ := record.GetAll("address[].street")
+
+	fmt.Println(phone)
+	fmt.Println(city)
+	fmt.Println(lat)
+	fmt.Println(streets)
+
+	// Output:
+	// 555-1234
+	// NY
+	// 34.0522
+	// [123 Main 456 Oak]
+}
@@ -102,6 +102,26 @@ func parseName(s string) (name, rest string, err error) {
 	return s[:i], s[i:], nil
 }
 
+// IsValidName reports whether name is a valid field name per IETF CSV++ Section 2.2:
+// one or more ASCII letters, digits, underscores and hyphens.
+//
+// Names from other formats can be checked with it before they are used in headers:
+//
+//	if !csvpp.IsValidName(name) {
+//	    return fmt.Errorf("%q is not a valid CSV++ name", name)
+//	}
+func IsValidName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if !isFieldChar(r) {
+			return false
+		}
+	}
+	return true
+}
+
 // isFieldChar checks if the rune is a valid field-char per IETF CSV++ Section 2.2.
 // ABNF: field-char = ALPHA / DIGIT / "_" / "-"
 // This restricts header names to ASCII alphanumeric characters, underscore, and hyphen.
@@ -341,6 +341,33 @@ func TestIsFieldChar(t *testing.T) {
 	}
 }
 
+func TestIsValidName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+		want  bool
+	}{
+		{name: "success: letters, digits, underscore and hyphen", input: "first_name-2", want: true},
+		{name: "success: invalid empty name", input: "", want: false},
+		{name: "success: invalid space", input: "first name", want: false},
+		{name: "success: invalid dot", input: "geo.lat", want: false},
+		{name: "success: invalid brackets", input: "tags[]", want: false},
+		{name: "success: invalid non-ASCII letter", input: "café", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := csvpp.IsValidName(tt.input); got != tt.want {
+				t.Errorf("IsValidName(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestSplitByDelimiter(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,58 @@
+package expr
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Order orders a and b, numerically when both are numbers.
+// ok is false when a number is compared with a non-number, in which case
+// c is the lexical order.
+func Order(a, b string) (c int, ok bool) {
+	af, aerr := strconv.ParseFloat(strings.TrimSpace(a), 64)
+	bf, berr := strconv.ParseFloat(strings.TrimSpace(b), 64)
+	switch {
+	case aerr == nil && berr == nil:
+		switch {
+		case af < bf:
+			return -1, true
+		case af > bf:
+			return 1, true
+		default:
+			return 0, true
+		}
+	case aerr == nil || berr == nil:
+		return strings.Compare(a, b), false
+	default:
+		return strings.Compare(a, b), true
+	}
+}
+
+// Compare applies the comparison operator op ("==", "!=", "<", "<=", ">" or
+// ">=") to l and r as ordered by Order. Ordering a number against a
+// non-number (e.g. an empty field) is false.
+func Compare(op, l, r string) bool {
+	c, ok := Order(l, r)
+
+	switch op {
+	case "==":
+		return c == 0
+	case "!=":
+		return c != 0
+	}
+	if !ok {
+		return false
+	}
+	switch op {
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	default:
+		return false
+	}
+}
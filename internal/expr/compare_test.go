@@ -0,0 +1,36 @@
+package expr_test
+
+import (
+	"testing"
+
+	"github.com/osamingo/go-csvpp/internal/expr"
+)
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		op   string
+		l, r string
+		want bool
+	}{
+		{name: "success: numeric equality", op: "==", l: "1.0", r: " 1", want: true},
+		{name: "success: numeric order", op: "<", l: "9", r: "10", want: true},
+		{name: "success: lexical order", op: "<", l: "b", r: "a", want: false},
+		{name: "success: lexical inequality", op: "!=", l: "a", r: "b", want: true},
+		{name: "success: number against non-number is unequal", op: "!=", l: "1", r: "", want: true},
+		{name: "success: number against non-number is unordered", op: ">=", l: "1", r: "", want: false},
+		{name: "success: unknown operator", op: "=~", l: "a", r: "a", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := expr.Compare(tt.op, tt.l, tt.r); got != tt.want {
+				t.Errorf("Compare(%q, %q, %q) = %v, want %v", tt.op, tt.l, tt.r, got, tt.want)
+			}
+		})
+	}
+}
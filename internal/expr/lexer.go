@@ -0,0 +1,195 @@
+// Package expr provides the lexer and value comparison shared by the filter
+// expressions of the csvpp command and the WHERE clauses of csvppsql.
+package expr
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Kind identifies the lexical class of a token. Kinds from Symbol upwards are
+// defined by the Syntax in use.
+type Kind int
+
+const (
+	EOF    Kind = iota
+	Ident       // field path, keyword, or quoted identifier
+	String      // quoted string
+	Number      // 42, -1.5
+	Symbol      // first kind available for operators and punctuation
+)
+
+// Token is a single lexical token.
+type Token struct {
+	Kind   Kind
+	Text   string // raw text, or unquoted value for quoted tokens
+	Quoted bool   // token was quoted; a quoted identifier is never a keyword
+	Pos    int    // byte offset in the source
+}
+
+// SymbolDef defines an operator or punctuation token.
+type SymbolDef struct {
+	Text string // source text
+	Kind Kind
+	As   string // token text if it differs from the source text
+}
+
+// Syntax describes the tokens of a language.
+type Syntax struct {
+	// Symbols are tried in order, so longer symbols must precede their prefixes.
+	Symbols []SymbolDef
+	// Quotes maps each quote character to the kind of token it encloses.
+	Quotes map[rune]Kind
+	// DoubledQuotes escapes a quote by doubling it, as in SQL. Otherwise a
+	// backslash escapes the next character, and \n and \t are a newline and a tab.
+	DoubledQuotes bool
+	// Brackets allows "[" and "]" in identifiers for indexed field paths.
+	Brackets bool
+}
+
+// Error is a lexical error at a position in the source.
+type Error struct {
+	Pos int
+	Err error
+}
+
+func (e *Error) Error() string { return fmt.Sprintf("position %d: %v", e.Pos, e.Err) }
+func (e *Error) Unwrap() error { return e.Err }
+
+// ErrUnterminated is returned for a quoted token without a closing quote.
+var ErrUnterminated = errors.New("unterminated string")
+
+// Lex splits src into tokens, ending with an EOF token. Runs of CSV++
+// field-chars and "." are identifiers, or numbers if they parse as one;
+// words such as "inf" or "nan" are identifiers.
+func (s *Syntax) Lex(src string) ([]Token, error) {
+	var tokens []Token
+
+	for i := 0; i < len(src); {
+		r, size := utf8.DecodeRuneInString(src[i:])
+		start := i
+
+		if r == ' ' || r == '\t' || r == '\n' || r == '\r' {
+			i += size
+			continue
+		}
+		if kind, ok := s.Quotes[r]; ok {
+			text, n, err := s.lexQuoted(src[i:], r)
+			if err != nil {
+				return nil, &Error{Pos: start, Err: err}
+			}
+			tokens = append(tokens, Token{Kind: kind, Text: text, Quoted: true, Pos: start})
+			i += n
+			continue
+		}
+		if sym, ok := s.symbol(src[i:]); ok {
+			text := sym.Text
+			if sym.As != "" {
+				text = sym.As
+			}
+			tokens = append(tokens, Token{Kind: sym.Kind, Text: text, Pos: start})
+			i += len(sym.Text)
+			continue
+		}
+		if !s.isIdentRune(r) {
+			return nil, &Error{Pos: start, Err: fmt.Errorf("unexpected character %q", r)}
+		}
+
+		n := s.identLength(src[i:])
+		text := src[i : i+n]
+		kind := Ident
+		if isNumber(text) {
+			kind = Number
+		}
+		tokens = append(tokens, Token{Kind: kind, Text: text, Pos: start})
+		i += n
+	}
+
+	tokens = append(tokens, Token{Kind: EOF, Pos: len(src)})
+	return tokens, nil
+}
+
+// symbol returns the symbol at the start of src.
+func (s *Syntax) symbol(src string) (SymbolDef, bool) {
+	for _, sym := range s.Symbols {
+		if strings.HasPrefix(src, sym.Text) {
+			return sym, true
+		}
+	}
+	return SymbolDef{}, false
+}
+
+// lexQuoted reads a quoted token starting at src[0] and returns its unquoted
+// value and the number of bytes consumed.
+func (s *Syntax) lexQuoted(src string, quote rune) (string, int, error) {
+	var b strings.Builder
+	for i := 1; i < len(src); {
+		r, size := utf8.DecodeRuneInString(src[i:])
+		switch {
+		case r == quote:
+			next, nsize := utf8.DecodeRuneInString(src[i+size:])
+			if !s.DoubledQuotes || next != quote {
+				return b.String(), i + size, nil
+			}
+			b.WriteRune(quote)
+			i += size + nsize
+			continue
+		case r == '\\' && !s.DoubledQuotes:
+			if i+size >= len(src) {
+				return "", 0, ErrUnterminated
+			}
+			next, nsize := utf8.DecodeRuneInString(src[i+size:])
+			switch next {
+			case 'n':
+				b.WriteRune('\n')
+			case 't':
+				b.WriteRune('\t')
+			default:
+				b.WriteRune(next)
+			}
+			i += size + nsize
+			continue
+		default:
+			b.WriteRune(r)
+		}
+		i += size
+	}
+	return "", 0, ErrUnterminated
+}
+
+// isIdentRune reports whether r can appear in an identifier or number:
+// CSV++ field-chars, "." for components and file names, and "[" "]" for
+// indices if the syntax allows them.
+func (s *Syntax) isIdentRune(r rune) bool {
+	return (r >= 'a' && r <= 'z') ||
+		(r >= 'A' && r <= 'Z') ||
+		(r >= '0' && r <= '9') ||
+		r == '_' || r == '-' || r == '.' ||
+		s.Brackets && (r == '[' || r == ']')
+}
+
+// identLength returns the byte length of the identifier run at the start of src.
+func (s *Syntax) identLength(src string) int {
+	n := 0
+	for n < len(src) {
+		r, size := utf8.DecodeRuneInString(src[n:])
+		if !s.isIdentRune(r) {
+			break
+		}
+		n += size
+	}
+	return n
+}
+
+// isNumber reports whether s is a numeric literal.
+func isNumber(s string) bool {
+	digits := strings.TrimPrefix(s, "-")
+	if digits == "" || (digits[0] < '0' || digits[0] > '9') && digits[0] != '.' {
+		return false
+	}
+	_, err := strconv.ParseFloat(s, 64)
+	return err == nil
+}
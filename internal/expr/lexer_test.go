@@ -0,0 +1,117 @@
+package expr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/osamingo/go-csvpp/internal/expr"
+)
+
+const (
+	tokOp = expr.Symbol + iota
+	tokLParen
+)
+
+func TestSyntax_Lex(t *testing.T) {
+	t.Parallel()
+
+	backslash := &expr.Syntax{
+		Symbols: []expr.SymbolDef{
+			{Text: "==", Kind: tokOp},
+			{Text: "=", Kind: tokOp, As: "=="},
+			{Text: "(", Kind: tokLParen},
+		},
+		Quotes:   map[rune]expr.Kind{'"': expr.String},
+		Brackets: true,
+	}
+	doubled := &expr.Syntax{
+		Symbols:       []expr.SymbolDef{{Text: "=", Kind: tokOp}},
+		Quotes:        map[rune]expr.Kind{'\'': expr.String, '"': expr.Ident},
+		DoubledQuotes: true,
+	}
+
+	tests := []struct {
+		name    string
+		syntax  *expr.Syntax
+		src     string
+		want    []expr.Token
+		wantPos int // position of the error, or -1 for success
+	}{
+		{
+			name:   "success: paths, numbers and aliased symbols",
+			syntax: backslash,
+			src:    `(tags[0] = -1.5e2`,
+			want: []expr.Token{
+				{Kind: tokLParen, Text: "(", Pos: 0},
+				{Kind: expr.Ident, Text: "tags[0]", Pos: 1},
+				{Kind: tokOp, Text: "==", Pos: 9},
+				{Kind: expr.Number, Text: "-1.5e2", Pos: 11},
+				{Kind: expr.EOF, Pos: 17},
+			},
+			wantPos: -1,
+		},
+		{
+			name:   "success: words and dates are identifiers",
+			syntax: backslash,
+			src:    "inf==2024-01-01",
+			want: []expr.Token{
+				{Kind: expr.Ident, Text: "inf", Pos: 0},
+				{Kind: tokOp, Text: "==", Pos: 3},
+				{Kind: expr.Ident, Text: "2024-01-01", Pos: 5},
+				{Kind: expr.EOF, Pos: 15},
+			},
+			wantPos: -1,
+		},
+		{
+			name:   "success: backslash escapes",
+			syntax: backslash,
+			src:    `"a\"b\tc"`,
+			want: []expr.Token{
+				{Kind: expr.String, Text: "a\"b\tc", Quoted: true, Pos: 0},
+				{Kind: expr.EOF, Pos: 9},
+			},
+			wantPos: -1,
+		},
+		{
+			name:   "success: doubled quotes and quoted identifiers",
+			syntax: doubled,
+			src:    `"a b"='it''s'`,
+			want: []expr.Token{
+				{Kind: expr.Ident, Text: "a b", Quoted: true, Pos: 0},
+				{Kind: tokOp, Text: "=", Pos: 5},
+				{Kind: expr.String, Text: "it's", Quoted: true, Pos: 6},
+				{Kind: expr.EOF, Pos: 13},
+			},
+			wantPos: -1,
+		},
+		{name: "error: unterminated string", syntax: backslash, src: `a == "b\"`, wantPos: 5},
+		{name: "error: brackets not allowed", syntax: doubled, src: "a[0]", wantPos: 1},
+		{name: "error: unexpected character", syntax: backslash, src: "a # b", wantPos: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tt.syntax.Lex(tt.src)
+			if tt.wantPos >= 0 {
+				var lexErr *expr.Error
+				if !errors.As(err, &lexErr) {
+					t.Fatalf("Lex() error = %v, want *expr.Error", err)
+				}
+				if lexErr.Pos != tt.wantPos {
+					t.Errorf("Lex() error position = %d, want %d", lexErr.Pos, tt.wantPos)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Lex() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Lex() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -72,6 +72,17 @@ func (r *Reader) Read() ([]*Field, error) {
 	return fields, nil
 }
 
+// ReadRecord reads one record like Read and pairs it with the parsed headers,
+// allowing fields to be accessed by path (see Record).
+// Returns io.EOF when the end of file is reached.
+func (r *Reader) ReadRecord() (*Record, error) {
+	fields, err := r.Read()
+	if err != nil {
+		return nil, err
+	}
+	return NewRecord(r.headers, fields), nil
+}
+
 // ReadAll reads and returns all records.
 // The header row is automatically parsed on the first call.
 func (r *Reader) ReadAll() ([][]*Field, error) {
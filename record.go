@@ -0,0 +1,487 @@
+package csvpp
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Path errors.
+var (
+	ErrInvalidPath   = errors.New("csvpp: invalid field path")
+	ErrFieldNotFound = errors.New("csvpp: field not found")
+)
+
+// Record is a single data row paired with the headers that describe it.
+// It resolves dotted field paths through ColumnHeader.Components so callers
+// do not have to navigate Field.Components by index.
+//
+// Path syntax:
+//
+//	name                 simple column
+//	phone[0]             first element of an array field
+//	phone[]              every element of an array field
+//	geo.lat              component of a structured field
+//	address[1].street    component of one element of an array-structured field
+//	address[].street     component of every element of an array-structured field
+type Record struct {
+	Headers []*ColumnHeader // Column headers (typically from Reader.Headers)
+	Fields  []*Field        // Parsed fields (typically from Reader.Read)
+}
+
+// NewRecord creates a new Record from headers and fields.
+func NewRecord(headers []*ColumnHeader, fields []*Field) *Record {
+	return &Record{
+		Headers: headers,
+		Fields:  fields,
+	}
+}
+
+// Get returns the single value selected by path.
+// It returns ErrInvalidPath if the path selects more than one value
+// or a structured value, and ErrFieldNotFound if the path does not exist.
+func (r *Record) Get(path string) (string, error) {
+	p, err := ParsePath(path)
+	if err != nil {
+		return "", err
+	}
+	return r.GetPath(p)
+}
+
+// GetAll returns every value selected by path.
+// Array fields and "[]" segments expand to all of their elements.
+func (r *Record) GetAll(path string) ([]string, error) {
+	p, err := ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetAllPath(p)
+}
+
+// GetPath is like Get but takes a pre-parsed Path.
+func (r *Record) GetPath(p *Path) (string, error) {
+	targets, multi, err := resolveTargets(r.Headers, r.Fields, p.segments)
+	if err != nil {
+		return "", &PathError{Path: p.raw, Err: err}
+	}
+	values, err := targetValues(targets)
+	if err != nil {
+		return "", &PathError{Path: p.raw, Err: err}
+	}
+	if multi || len(targets) != 1 || targets[0].header.Kind != SimpleField {
+		return "", &PathError{Path: p.raw, Err: fmt.Errorf("%w: path selects multiple values", ErrInvalidPath)}
+	}
+	return values[0], nil
+}
+
+// GetAllPath is like GetAll but takes a pre-parsed Path.
+func (r *Record) GetAllPath(p *Path) ([]string, error) {
+	targets, _, err := resolveTargets(r.Headers, r.Fields, p.segments)
+	if err != nil {
+		return nil, &PathError{Path: p.raw, Err: err}
+	}
+	values, err := targetValues(targets)
+	if err != nil {
+		return nil, &PathError{Path: p.raw, Err: err}
+	}
+	return values, nil
+}
+
+// Len returns the number of elements selected by path.
+// For an array or array-structured field it is the number of elements in the field;
+// for any other path it is the number of values the path selects.
+func (r *Record) Len(path string) (int, error) {
+	p, err := ParsePath(path)
+	if err != nil {
+		return 0, err
+	}
+	return r.LenPath(p)
+}
+
+// LenPath is like Len but takes a pre-parsed Path.
+func (r *Record) LenPath(p *Path) (int, error) {
+	targets, _, err := resolveTargets(r.Headers, r.Fields, p.segments)
+	if err != nil {
+		return 0, &PathError{Path: p.raw, Err: err}
+	}
+
+	n := 0
+	for _, t := range targets {
+		switch t.header.Kind {
+		case ArrayField:
+			n += len(t.field.Values)
+		case ArrayStructuredField:
+			n += len(t.field.Components)
+		default:
+			n++
+		}
+	}
+	return n, nil
+}
+
+// Elements returns one Record per element of the array-structured field selected by path.
+// Each element Record uses the field's component headers, so paths passed to it are
+// relative to the element (e.g. "street" for elements of "address").
+func (r *Record) Elements(path string) ([]*Record, error) {
+	p, err := ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	return r.ElementsPath(p)
+}
+
+// ElementsPath is like Elements but takes a pre-parsed Path.
+func (r *Record) ElementsPath(p *Path) ([]*Record, error) {
+	targets, _, err := resolveTargets(r.Headers, r.Fields, p.segments)
+	if err != nil {
+		return nil, &PathError{Path: p.raw, Err: err}
+	}
+
+	var elems []*Record
+	for _, t := range targets {
+		switch t.header.Kind {
+		case ArrayStructuredField:
+			for _, e := range t.field.Components {
+				if e != nil {
+					elems = append(elems, NewRecord(t.header.Components, e.Components))
+				}
+			}
+		case StructuredField:
+			elems = append(elems, NewRecord(t.header.Components, t.field.Components))
+		case SimpleField, ArrayField:
+			return nil, &PathError{Path: p.raw, Err: fmt.Errorf("%w: %q is not array-structured", ErrInvalidPath, t.header.Name)}
+		}
+	}
+	return elems, nil
+}
+
+// Int returns the value selected by path parsed as a base-10 integer.
+func (r *Record) Int(path string) (int64, error) {
+	v, err := r.Get(path)
+	if err != nil {
+		return 0, err
+	}
+	n, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+	if err != nil {
+		return 0, &PathError{Path: path, Err: err}
+	}
+	return n, nil
+}
+
+// Float returns the value selected by path parsed as a float64.
+func (r *Record) Float(path string) (float64, error) {
+	v, err := r.Get(path)
+	if err != nil {
+		return 0, err
+	}
+	f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+	if err != nil {
+		return 0, &PathError{Path: path, Err: err}
+	}
+	return f, nil
+}
+
+// Bool returns the value selected by path parsed with strconv.ParseBool.
+func (r *Record) Bool(path string) (bool, error) {
+	v, err := r.Get(path)
+	if err != nil {
+		return false, err
+	}
+	b, err := strconv.ParseBool(strings.TrimSpace(v))
+	if err != nil {
+		return false, &PathError{Path: path, Err: err}
+	}
+	return b, nil
+}
+
+// PathError records a failure to resolve a field path.
+type PathError struct {
+	Path string // Path as given by the caller
+	Err  error  // Original error
+}
+
+// Error returns the error message for PathError.
+func (e *PathError) Error() string {
+	return fmt.Sprintf("csvpp: path %q: %v", e.Path, e.Err)
+}
+
+// Unwrap returns the original error.
+func (e *PathError) Unwrap() error {
+	return e.Err
+}
+
+// Path is a parsed field path such as "address[1].street".
+// See Record for the path syntax.
+type Path struct {
+	raw      string
+	segments []pathSegment
+}
+
+// pathSegment is one dot-separated element of a Path.
+type pathSegment struct {
+	name    string
+	indexed bool // "[n]" or "[]" is present
+	all     bool // "[]" selects every element
+	index   int  // element index when indexed && !all
+}
+
+// ParsePath parses a dotted field path.
+// The returned Path can be reused across records to avoid re-parsing.
+func ParsePath(s string) (*Path, error) {
+	if s == "" {
+		return nil, fmt.Errorf("%w: empty path", ErrInvalidPath)
+	}
+
+	parts := strings.Split(s, ".")
+	segments := make([]pathSegment, 0, len(parts))
+	for _, part := range parts {
+		seg, err := parsePathSegment(part)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q: %w", ErrInvalidPath, s, err)
+		}
+		segments = append(segments, seg)
+	}
+
+	return &Path{raw: s, segments: segments}, nil
+}
+
+// String returns the path as originally given to ParsePath.
+func (p *Path) String() string {
+	return p.raw
+}
+
+// Header returns the column header describing the value selected by p.
+// An indexed ("[n]" or "[]") array element is described by a SimpleField header
+// and an indexed array-structured element by a StructuredField header.
+// It returns ErrFieldNotFound or ErrInvalidPath if p does not fit headers.
+func (p *Path) Header(headers []*ColumnHeader) (*ColumnHeader, error) {
+	h, err := resolveHeader(headers, p.segments)
+	if err != nil {
+		return nil, &PathError{Path: p.raw, Err: err}
+	}
+	return h, nil
+}
+
+// parsePathSegment parses a single segment of the form name, name[n] or name[].
+func parsePathSegment(s string) (pathSegment, error) {
+	name, rest, err := parseName(s)
+	if err != nil {
+		return pathSegment{}, errors.New("segment name is required")
+	}
+
+	seg := pathSegment{name: name}
+	if rest == "" {
+		return seg, nil
+	}
+
+	if !strings.HasPrefix(rest, "[") || !strings.HasSuffix(rest, "]") {
+		return pathSegment{}, fmt.Errorf("unexpected characters %q", rest)
+	}
+
+	seg.indexed = true
+	raw := rest[1 : len(rest)-1]
+	if raw == "" {
+		seg.all = true
+		return seg, nil
+	}
+
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 0 {
+		return pathSegment{}, fmt.Errorf("invalid index %q", raw)
+	}
+	seg.index = n
+
+	return seg, nil
+}
+
+// pathTarget is a header/field pair selected by a path.
+type pathTarget struct {
+	header *ColumnHeader
+	field  *Field
+}
+
+// lookupColumn finds the header named name and its corresponding field.
+// A missing field (short row) is returned as an empty Field.
+func lookupColumn(headers []*ColumnHeader, fields []*Field, name string) (*ColumnHeader, *Field, error) {
+	for i, h := range headers {
+		if h.Name != name {
+			continue
+		}
+		if i < len(fields) && fields[i] != nil {
+			return h, fields[i], nil
+		}
+		return h, &Field{}, nil
+	}
+	return nil, nil, fmt.Errorf("%w: %q", ErrFieldNotFound, name)
+}
+
+// elementHeader returns the header describing a single element of an array
+// or array-structured field.
+func elementHeader(h *ColumnHeader) *ColumnHeader {
+	if h.Kind == ArrayStructuredField {
+		return &ColumnHeader{
+			Name:               h.Name,
+			Kind:               StructuredField,
+			ComponentDelimiter: h.ComponentDelimiter,
+			Components:         h.Components,
+		}
+	}
+	return &ColumnHeader{Name: h.Name, Kind: SimpleField}
+}
+
+// resolveTargets walks headers and fields along segments and returns the selected targets.
+// Indexed array elements are returned with SimpleField headers and indexed
+// array-structured elements with StructuredField headers (see elementHeader).
+// multi reports whether the path expanded to more than one element at any step.
+func resolveTargets(headers []*ColumnHeader, fields []*Field, segments []pathSegment) (targets []pathTarget, multi bool, err error) {
+	seg := segments[0]
+	rest := segments[1:]
+
+	header, field, err := lookupColumn(headers, fields, seg.name)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := checkSegment(header, seg, rest); err != nil {
+		return nil, false, err
+	}
+
+	switch header.Kind {
+	case ArrayField:
+		if !seg.indexed {
+			return []pathTarget{{header: header, field: field}}, false, nil
+		}
+		elem := elementHeader(header)
+		if seg.all {
+			for _, v := range field.Values {
+				targets = append(targets, pathTarget{header: elem, field: &Field{Value: v}})
+			}
+			return targets, true, nil
+		}
+		if seg.index >= len(field.Values) {
+			return nil, false, fmt.Errorf("%w: %s[%d]", ErrFieldNotFound, seg.name, seg.index)
+		}
+		return []pathTarget{{header: elem, field: &Field{Value: field.Values[seg.index]}}}, false, nil
+
+	case StructuredField:
+		if len(rest) == 0 {
+			return []pathTarget{{header: header, field: field}}, false, nil
+		}
+		return resolveTargets(header.Components, field.Components, rest)
+
+	case ArrayStructuredField:
+		if !seg.indexed && len(rest) == 0 {
+			return []pathTarget{{header: header, field: field}}, false, nil
+		}
+
+		elems := field.Components
+		multi = true
+		if seg.indexed && !seg.all {
+			if seg.index >= len(field.Components) || field.Components[seg.index] == nil {
+				return nil, false, fmt.Errorf("%w: %s[%d]", ErrFieldNotFound, seg.name, seg.index)
+			}
+			elems = field.Components[seg.index : seg.index+1]
+			multi = false
+		}
+
+		if len(rest) == 0 {
+			elem := elementHeader(header)
+			for _, e := range elems {
+				if e != nil {
+					targets = append(targets, pathTarget{header: elem, field: e})
+				}
+			}
+			return targets, multi, nil
+		}
+
+		if len(elems) == 0 {
+			// Validate the remaining path against the headers even without data.
+			_, err := resolveHeader(header.Components, rest)
+			return nil, multi, err
+		}
+		for _, e := range elems {
+			if e == nil {
+				continue
+			}
+			ts, m, err := resolveTargets(header.Components, e.Components, rest)
+			if err != nil {
+				return nil, false, err
+			}
+			targets = append(targets, ts...)
+			multi = multi || m
+		}
+		return targets, multi, nil
+
+	default:
+		return []pathTarget{{header: header, field: field}}, false, nil
+	}
+}
+
+// resolveHeader walks headers along segments and returns the header of the selected value,
+// using the same element headers as resolveTargets.
+func resolveHeader(headers []*ColumnHeader, segments []pathSegment) (*ColumnHeader, error) {
+	seg := segments[0]
+	rest := segments[1:]
+
+	header, _, err := lookupColumn(headers, nil, seg.name)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSegment(header, seg, rest); err != nil {
+		return nil, err
+	}
+
+	switch header.Kind {
+	case ArrayField:
+		if seg.indexed {
+			return elementHeader(header), nil
+		}
+		return header, nil
+	case StructuredField:
+		if len(rest) == 0 {
+			return header, nil
+		}
+		return resolveHeader(header.Components, rest)
+	case ArrayStructuredField:
+		if len(rest) == 0 {
+			if seg.indexed {
+				return elementHeader(header), nil
+			}
+			return header, nil
+		}
+		return resolveHeader(header.Components, rest)
+	default:
+		return header, nil
+	}
+}
+
+// checkSegment reports whether seg (followed by rest) is applicable to header.
+func checkSegment(header *ColumnHeader, seg pathSegment, rest []pathSegment) error {
+	isArray := header.Kind == ArrayField || header.Kind == ArrayStructuredField
+	hasComponents := header.Kind == StructuredField || header.Kind == ArrayStructuredField
+
+	if seg.indexed && !isArray {
+		return fmt.Errorf("%w: %q is not an array", ErrInvalidPath, seg.name)
+	}
+	if len(rest) > 0 && !hasComponents {
+		return fmt.Errorf("%w: %q has no components", ErrInvalidPath, seg.name)
+	}
+	return nil
+}
+
+// targetValues flattens targets into their string values.
+// Structured targets cannot be represented as strings and are rejected.
+func targetValues(targets []pathTarget) ([]string, error) {
+	var values []string
+	for _, t := range targets {
+		switch t.header.Kind {
+		case SimpleField:
+			values = append(values, t.field.Value)
+		case ArrayField:
+			values = append(values, t.field.Values...)
+		case StructuredField, ArrayStructuredField:
+			return nil, fmt.Errorf("%w: %q is structured; select a component", ErrInvalidPath, t.header.Name)
+		}
+	}
+	return values, nil
+}
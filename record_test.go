@@ -0,0 +1,491 @@
+package csvpp_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/osamingo/go-csvpp"
+)
+
+// newTestRecord reads the first data row of input as a Record.
+func newTestRecord(t *testing.T, input string) *csvpp.Record {
+	t.Helper()
+
+	rec, err := csvpp.NewReader(strings.NewReader(input)).ReadRecord()
+	if err != nil {
+		t.Fatalf("ReadRecord() error = %v", err)
+	}
+	return rec
+}
+
+const recordTestInput = `name,age,score,active,phone[],geo(lat^lon),address[](street^city^tags[;])
+Alice,30,9.5,true,555-1234~555-5678,34.0522^-118.2437,123 Main^LA^home;main~456 Oak^NY^work
+`
+
+func TestRecord_Get(t *testing.T) {
+	t.Parallel()
+
+	rec := newTestRecord(t, recordTestInput)
+
+	tests := []struct {
+		name    string
+		path    string
+		want    string
+		wantErr error
+	}{
+		{
+			name: "success: simple field",
+			path: "name",
+			want: "Alice",
+		},
+		{
+			name: "success: array element",
+			path: "phone[1]",
+			want: "555-5678",
+		},
+		{
+			name: "success: structured component",
+			path: "geo.lat",
+			want: "34.0522",
+		},
+		{
+			name: "success: array structured element component",
+			path: "address[1].city",
+			want: "NY",
+		},
+		{
+			name: "success: nested array element",
+			path: "address[0].tags[1]",
+			want: "main",
+		},
+		{
+			name:    "error: unknown field",
+			path:    "email",
+			wantErr: csvpp.ErrFieldNotFound,
+		},
+		{
+			name:    "error: unknown component",
+			path:    "geo.alt",
+			wantErr: csvpp.ErrFieldNotFound,
+		},
+		{
+			name:    "error: index out of range",
+			path:    "address[2].city",
+			wantErr: csvpp.ErrFieldNotFound,
+		},
+		{
+			name:    "error: array without index",
+			path:    "phone",
+			wantErr: csvpp.ErrInvalidPath,
+		},
+		{
+			name:    "error: all elements",
+			path:    "address[].city",
+			wantErr: csvpp.ErrInvalidPath,
+		},
+		{
+			name:    "error: structured without component",
+			path:    "geo",
+			wantErr: csvpp.ErrInvalidPath,
+		},
+		{
+			name:    "error: index on simple field",
+			path:    "name[0]",
+			wantErr: csvpp.ErrInvalidPath,
+		},
+		{
+			name:    "error: component of simple field",
+			path:    "name.first",
+			wantErr: csvpp.ErrInvalidPath,
+		},
+		{
+			name:    "error: malformed path",
+			path:    "geo..lat",
+			wantErr: csvpp.ErrInvalidPath,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := rec.Get(tt.path)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Get(%q) error = %v, want %v", tt.path, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Get(%q) unexpected error: %v", tt.path, err)
+			}
+			if got != tt.want {
+				t.Errorf("Get(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecord_GetAll(t *testing.T) {
+	t.Parallel()
+
+	rec := newTestRecord(t, recordTestInput)
+
+	tests := []struct {
+		name    string
+		path    string
+		want    []string
+		wantErr error
+	}{
+		{
+			name: "success: simple field",
+			path: "name",
+			want: []string{"Alice"},
+		},
+		{
+			name: "success: array field without brackets",
+			path: "phone",
+			want: []string{"555-1234", "555-5678"},
+		},
+		{
+			name: "success: array field with brackets",
+			path: "phone[]",
+			want: []string{"555-1234", "555-5678"},
+		},
+		{
+			name: "success: component of every element",
+			path: "address[].street",
+			want: []string{"123 Main", "456 Oak"},
+		},
+		{
+			name: "success: nested arrays of every element",
+			path: "address[].tags[]",
+			want: []string{"home", "main", "work"},
+		},
+		{
+			name: "success: component of one element",
+			path: "address[0].city",
+			want: []string{"LA"},
+		},
+		{
+			name:    "error: unknown component",
+			path:    "address[].zip",
+			wantErr: csvpp.ErrFieldNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := rec.GetAll(tt.path)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("GetAll(%q) error = %v, want %v", tt.path, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetAll(%q) unexpected error: %v", tt.path, err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("GetAll(%q) mismatch (-want +got):\n%s", tt.path, diff)
+			}
+		})
+	}
+}
+
+func TestRecord_GetAll_EmptyArrayStructured(t *testing.T) {
+	t.Parallel()
+
+	rec := newTestRecord(t, "name,address[](street^city)\nAlice,\n")
+
+	got, err := rec.GetAll("address[].city")
+	if err != nil {
+		t.Fatalf("GetAll() unexpected error: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("GetAll() = %v, want empty", got)
+	}
+
+	if _, err := rec.GetAll("address[].zip"); !errors.Is(err, csvpp.ErrFieldNotFound) {
+		t.Errorf("GetAll() error = %v, want %v", err, csvpp.ErrFieldNotFound)
+	}
+}
+
+func TestRecord_TypedGetters(t *testing.T) {
+	t.Parallel()
+
+	rec := newTestRecord(t, recordTestInput)
+
+	t.Run("success: Int", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := rec.Int("age")
+		if err != nil {
+			t.Fatalf("Int() unexpected error: %v", err)
+		}
+		if got != 30 {
+			t.Errorf("Int() = %d, want 30", got)
+		}
+	})
+
+	t.Run("success: Float", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := rec.Float("geo.lon")
+		if err != nil {
+			t.Fatalf("Float() unexpected error: %v", err)
+		}
+		if got != -118.2437 {
+			t.Errorf("Float() = %v, want -118.2437", got)
+		}
+	})
+
+	t.Run("success: Bool", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := rec.Bool("active")
+		if err != nil {
+			t.Fatalf("Bool() unexpected error: %v", err)
+		}
+		if !got {
+			t.Error("Bool() = false, want true")
+		}
+	})
+
+	t.Run("error: Int of non-numeric value", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := rec.Int("name"); err == nil {
+			t.Error("expected error but got nil")
+		}
+	})
+
+	t.Run("error: Float of missing field", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := rec.Float("missing"); !errors.Is(err, csvpp.ErrFieldNotFound) {
+			t.Errorf("Float() error = %v, want %v", err, csvpp.ErrFieldNotFound)
+		}
+	})
+
+	t.Run("error: Bool of non-boolean value", func(t *testing.T) {
+		t.Parallel()
+
+		var pathErr *csvpp.PathError
+		if _, err := rec.Bool("score"); !errors.As(err, &pathErr) {
+			t.Errorf("Bool() error = %v, want *PathError", err)
+		}
+	})
+}
+
+func TestRecord_Len(t *testing.T) {
+	t.Parallel()
+
+	rec := newTestRecord(t, recordTestInput)
+
+	tests := []struct {
+		name    string
+		path    string
+		want    int
+		wantErr error
+	}{
+		{name: "success: array field", path: "phone", want: 2},
+		{name: "success: array structured field", path: "address", want: 2},
+		{name: "success: every element", path: "address[]", want: 2},
+		{name: "success: nested arrays of every element", path: "address[].tags", want: 3},
+		{name: "success: nested array of one element", path: "address[1].tags", want: 1},
+		{name: "success: simple field", path: "name", want: 1},
+		{name: "error: unknown field", path: "email", wantErr: csvpp.ErrFieldNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := rec.Len(tt.path)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Len(%q) error = %v, want %v", tt.path, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Len(%q) unexpected error: %v", tt.path, err)
+			}
+			if got != tt.want {
+				t.Errorf("Len(%q) = %d, want %d", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecord_Elements(t *testing.T) {
+	t.Parallel()
+
+	rec := newTestRecord(t, recordTestInput)
+
+	t.Run("success: array structured field", func(t *testing.T) {
+		t.Parallel()
+
+		elems, err := rec.Elements("address")
+		if err != nil {
+			t.Fatalf("Elements() unexpected error: %v", err)
+		}
+
+		var got []string
+		for _, e := range elems {
+			city, err := e.Get("city")
+			if err != nil {
+				t.Fatalf("Get() unexpected error: %v", err)
+			}
+			got = append(got, city)
+		}
+		if diff := cmp.Diff([]string{"LA", "NY"}, got); diff != "" {
+			t.Errorf("Elements() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("success: single element", func(t *testing.T) {
+		t.Parallel()
+
+		elems, err := rec.Elements("address[1]")
+		if err != nil {
+			t.Fatalf("Elements() unexpected error: %v", err)
+		}
+		if len(elems) != 1 {
+			t.Fatalf("Elements() returned %d elements, want 1", len(elems))
+		}
+		if got, _ := elems[0].Get("street"); got != "456 Oak" {
+			t.Errorf("Get() = %q, want %q", got, "456 Oak")
+		}
+	})
+
+	t.Run("error: array field", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := rec.Elements("phone"); !errors.Is(err, csvpp.ErrInvalidPath) {
+			t.Errorf("Elements() error = %v, want %v", err, csvpp.ErrInvalidPath)
+		}
+	})
+}
+
+func TestPath_Header(t *testing.T) {
+	t.Parallel()
+
+	rec := newTestRecord(t, recordTestInput)
+
+	tests := []struct {
+		name     string
+		path     string
+		wantName string
+		wantKind csvpp.FieldKind
+		wantErr  error
+	}{
+		{name: "success: simple field", path: "name", wantName: "name", wantKind: csvpp.SimpleField},
+		{name: "success: array field", path: "phone", wantName: "phone", wantKind: csvpp.ArrayField},
+		{name: "success: array element", path: "phone[0]", wantName: "phone", wantKind: csvpp.SimpleField},
+		{name: "success: array structured field", path: "address", wantName: "address", wantKind: csvpp.ArrayStructuredField},
+		{name: "success: array structured element", path: "address[]", wantName: "address", wantKind: csvpp.StructuredField},
+		{name: "success: nested component", path: "address[].tags", wantName: "tags", wantKind: csvpp.ArrayField},
+		{name: "error: unknown component", path: "geo.alt", wantErr: csvpp.ErrFieldNotFound},
+		{name: "error: index on structured field", path: "geo[0]", wantErr: csvpp.ErrInvalidPath},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			p, err := csvpp.ParsePath(tt.path)
+			if err != nil {
+				t.Fatalf("ParsePath(%q) unexpected error: %v", tt.path, err)
+			}
+
+			got, err := p.Header(rec.Headers)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Header(%q) error = %v, want %v", tt.path, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Header(%q) unexpected error: %v", tt.path, err)
+			}
+			if got.Name != tt.wantName || got.Kind != tt.wantKind {
+				t.Errorf("Header(%q) = %s %s, want %s %s", tt.path, got.Name, got.Kind, tt.wantName, tt.wantKind)
+			}
+		})
+	}
+}
+
+func TestParsePath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		in      string
+		wantErr bool
+	}{
+		{name: "success: simple", in: "name"},
+		{name: "success: dotted", in: "geo.lat"},
+		{name: "success: indexed", in: "address[10].street"},
+		{name: "success: all elements", in: "address[].tags[]"},
+		{name: "error: empty", in: "", wantErr: true},
+		{name: "error: empty segment", in: "geo.", wantErr: true},
+		{name: "error: negative index", in: "phone[-1]", wantErr: true},
+		{name: "error: non-numeric index", in: "phone[x]", wantErr: true},
+		{name: "error: unclosed bracket", in: "phone[0", wantErr: true},
+		{name: "error: invalid character", in: "first name", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			p, err := csvpp.ParsePath(tt.in)
+			if tt.wantErr {
+				if !errors.Is(err, csvpp.ErrInvalidPath) {
+					t.Errorf("ParsePath(%q) error = %v, want %v", tt.in, err, csvpp.ErrInvalidPath)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParsePath(%q) unexpected error: %v", tt.in, err)
+			}
+			if p.String() != tt.in {
+				t.Errorf("String() = %q, want %q", p.String(), tt.in)
+			}
+		})
+	}
+}
+
+func TestReader_ReadRecord(t *testing.T) {
+	t.Parallel()
+
+	reader := csvpp.NewReader(strings.NewReader("name,geo(lat^lon)\nAlice,1^2\nBob,3^4\n"))
+
+	var got []string
+	for {
+		rec, err := reader.ReadRecord()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("ReadRecord() unexpected error: %v", err)
+		}
+		lat, err := rec.Get("geo.lat")
+		if err != nil {
+			t.Fatalf("Get() unexpected error: %v", err)
+		}
+		got = append(got, lat)
+	}
+
+	if diff := cmp.Diff([]string{"1", "3"}, got); diff != "" {
+		t.Errorf("ReadRecord() mismatch (-want +got):\n%s", diff)
+	}
+}
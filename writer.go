@@ -14,6 +14,9 @@ type Writer struct {
 	Comma rune
 	// UseCRLF uses \r\n as the line terminator if true.
 	UseCRLF bool
+	// AutoDelimiters makes WriteAll replace the delimiters of the headers with
+	// ones assigned by AssignDelimiters, so that no value contains a delimiter.
+	AutoDelimiters bool
 
 	w         io.Writer
 	csvWriter *csv.Writer
@@ -69,6 +72,14 @@ func (w *Writer) Write(record []*Field) error {
 // WriteAll writes all records.
 // The header row is also written automatically.
 func (w *Writer) WriteAll(records [][]*Field) error {
+	if w.AutoDelimiters && len(w.headers) > 0 {
+		headers, err := AssignDelimiters(w.headers, records)
+		if err != nil {
+			return err
+		}
+		w.headers = headers
+	}
+
 	if err := w.WriteHeader(); err != nil {
 		return err
 	}